@@ -96,3 +96,54 @@ var EmulatorFlagAutoUpdateConnector = &StringFlag{
 	Name:  "auto-update-connector",
 	Usage: "auto update connector",
 }
+
+var EmulatorFlagSignerType = &StringFlag{
+	Name:  "signer-type",
+	Usage: "signer backend of the deployer: private_key, keystore or web3signer, overrides deployer-key-file",
+}
+
+var EmulatorFlagSignerPrivateKey = &StringFlag{
+	Name:    "signer-private-key",
+	Usage:   "hex encoded key of the private_key signer, prefer the env var to keep it out of the process list",
+	EnvVars: []string{"PELL_SIGNER_PRIVATE_KEY"},
+}
+
+var EmulatorFlagSignerKeystoreFile = &StringFlag{
+	Name:  "signer-keystore-file",
+	Usage: "keystore file of the keystore signer",
+}
+
+var EmulatorFlagSignerPasswordFile = &StringFlag{
+	Name:  "signer-password-file",
+	Usage: "file containing the password of the keystore signer",
+}
+
+var EmulatorFlagSignerPasswordEnv = &StringFlag{
+	Name:  "signer-password-env",
+	Usage: "name of the env var containing the password of the keystore signer",
+}
+
+var EmulatorFlagSignerWeb3SignerURL = &StringFlag{
+	Name:  "signer-web3signer-url",
+	Usage: "url of the web3signer",
+}
+
+var EmulatorFlagSignerAddress = &StringFlag{
+	Name:  "signer-address",
+	Usage: "address of the web3signer key",
+}
+
+var EmulatorFlagPellDeployments = &StringFlag{
+	Name:  "pell-deployments",
+	Usage: "hardhat deployment output of the pell contracts, its addresses override contract_address",
+}
+
+var EmulatorFlagDVSDeployments = &StringFlag{
+	Name:  "dvs-deployments",
+	Usage: "hardhat deployment output of the dvs contracts, its addresses override contract_address",
+}
+
+var EmulatorFlagFoundryBroadcast = &StringFlag{
+	Name:  "foundry-broadcast",
+	Usage: "foundry broadcast dir, the addresses of its run-latest.json for the chain id of rpc-url override contract_address",
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/events"
+)
+
+var EmulatorDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "check the config against the chain before starting the emulator",
+	Example: `
+pell-emulator doctor \
+	--home <home-dir>
+
+pell-emulator doctor \
+	--home <home-dir> \
+	--rpc-url http://localhost:8545 \
+	--ws-url ws://localhost:8545
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetGlobalConfig()
+		connectors, err := events.ConnectorContracts(cfg.Routes)
+		if err != nil {
+			return err
+		}
+		report := chains.Doctor(cmd.Context(), cfg, logger, chains.WithConnectorContracts(connectors))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, check := range report.Checks {
+			fmt.Fprintf(w, "[%s]\t%s\t%s\n", check.Status, check.Name, check.Detail)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if failed := report.Failed(); failed > 0 {
+			return errors.Errorf("%d of %d checks failed", failed, len(report.Checks))
+		}
+		fmt.Printf("\nall %d checks passed\n", len(report.Checks))
+		return nil
+	},
+}
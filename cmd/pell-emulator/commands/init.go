@@ -13,6 +13,12 @@ import (
 	"github.com/0xPellNetwork/pell-emulator/config"
 )
 
+func init() {
+	chainflags.EmulatorFlagPellDeployments.AddToCmdFlag(EmulatorInitCmd)
+	chainflags.EmulatorFlagDVSDeployments.AddToCmdFlag(EmulatorInitCmd)
+	chainflags.EmulatorFlagFoundryBroadcast.AddToCmdFlag(EmulatorInitCmd)
+}
+
 var EmulatorInitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "init pell emulator config",
@@ -20,6 +26,14 @@ var EmulatorInitCmd = &cobra.Command{
 `,
 	Example: `
 pell-emulator init --home <home-dir>, defaults to ~/.pell-emulator>
+
+pell-emulator init --home <home-dir> \
+	--pell-deployments <pell-hardhat-deployments.json> \
+	--dvs-deployments <dvs-hardhat-deployments.json>
+
+pell-emulator init --home <home-dir> \
+	--rpc-url <rpc-url> \
+	--foundry-broadcast <foundry-project>/broadcast
 `,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		return nil
@@ -39,6 +53,13 @@ pell-emulator init --home <home-dir>, defaults to ~/.pell-emulator>
 			return nil
 		}
 
+		if err := applyHardhatDeployments(dftConfig); err != nil {
+			return err
+		}
+		if err := applyFoundryBroadcasts(cmd.Context(), dftConfig); err != nil {
+			return err
+		}
+
 		dir, filename := filepath.Split(cfgFile)
 		logger.Info("config file", "dir", dir, "filename", filename)
 
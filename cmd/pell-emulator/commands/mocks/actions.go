@@ -0,0 +1,366 @@
+package mocks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/pelldelegationmanager.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/pellstrategymanager.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/registryrouter.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v3/delegationmanager.sol"
+	"github.com/0xPellNetwork/pell-middleware-contracts/pkg/src/centralscheduler.sol"
+	"github.com/0xPellNetwork/pell-middleware-contracts/pkg/src/operatorstakemanager.sol"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/crypto/bls"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+	"github.com/0xPellNetwork/pell-emulator/libs/utils"
+)
+
+// Env is the chain the mock actions run against
+type Env struct {
+	Bindings *chains.ChainBindings
+	// WaitTimeout bounds the wait for the emulator to forward the source tx, 0 to not wait
+	WaitTimeout time.Duration
+}
+
+// Outcome is the source tx of a mock action and the tx the emulator forwarded it to,
+// Forward is nil when the forward is not awaited
+type Outcome struct {
+	Source  *Result
+	Forward *Result
+}
+
+func (env *Env) txMgr(key *ecdsa.PrivateKey) (txmgr.TxManager, error) {
+	return utils.CreateTxMgrByKeyFile(key, env.Bindings.RPCClient, env.Bindings.ChainID, logger)
+}
+
+// awaitForward waits for the forward of source when env waits for forwards
+func (env *Env) awaitForward(ctx context.Context, source *Result, q forwardQuery) (*Outcome, error) {
+	outcome := &Outcome{Source: source}
+	if env.WaitTimeout == 0 {
+		return outcome, nil
+	}
+	forward, err := waitForForward(ctx, logger, env.Bindings.RPCClient, source.Receipt.BlockNumber.Uint64(), q, env.WaitTimeout)
+	if err != nil {
+		return nil, err
+	}
+	outcome.Forward = forward
+	return outcome, nil
+}
+
+// StakingDeposit deposits amount of token into strategy for the owner of key
+func StakingDeposit(
+	ctx context.Context, env *Env, key *ecdsa.PrivateKey, token, strategy gethcommon.Address, amount *big.Int,
+) (*Outcome, error) {
+	staker := crypto.PubkeyToAddress(key.PublicKey)
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, err
+	}
+	mgr, err := NewStakingStrategyManager(env.Bindings.RPCClient, txMgr, env.Bindings.ContractAddress.StakingStrategyManager)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := mgr.DepositIntoStrategy(ctx, staker, strategy, token, amount)
+	if err != nil {
+		return nil, err
+	}
+	shares, _ := eventArg[*big.Int](result, "StakingStrategyManager", "Deposit", "shares")
+
+	return env.awaitForward(ctx, result, forwardQuery{
+		contract: "PellStrategyManager",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.PellStrategyManager),
+		metaData: pellstrategymanager.PellStrategyManagerMetaData,
+		method:   "syncDepositState",
+		// syncDepositState(chainId, staker, strategy, shares)
+		match: func(args []any) bool {
+			if len(args) != 4 || args[1] != staker || args[2] != strategy {
+				return false
+			}
+			forwarded, ok := args[3].(*big.Int)
+			return ok && (shares == nil || forwarded.Cmp(shares) == 0)
+		},
+	})
+}
+
+// PellStakerShares returns the shares of staker in strategy of the chain of env, as the
+// PellStrategyManager accounts them after the deposits were forwarded
+func PellStakerShares(ctx context.Context, env *Env, staker, strategy gethcommon.Address) (*big.Int, error) {
+	mgr, err := NewPellStrategyManager(env.Bindings.RPCClient, env.Bindings.ContractAddress.PellStrategyManager)
+	if err != nil {
+		return nil, err
+	}
+	return mgr.StakerStrategyShares(ctx, env.Bindings.ChainID, staker, strategy)
+}
+
+// StakingDelegateTo delegates the owner of key to operator
+func StakingDelegateTo(ctx context.Context, env *Env, key *ecdsa.PrivateKey, operator gethcommon.Address) (*Outcome, error) {
+	staker := crypto.PubkeyToAddress(key.PublicKey)
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, err
+	}
+	mgr, err := NewStakingDelegationManager(env.Bindings.RPCClient, txMgr, env.Bindings.ContractAddress.StakingDelegationManager)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := mgr.DelegateTo(ctx, operator.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	return env.awaitForward(ctx, result, forwardQuery{
+		contract: "PellDelegationManager",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.PellDelegationManager),
+		metaData: pelldelegationmanager.PellDelegationManagerMetaData,
+		method:   "syncDelegateState",
+		// syncDelegateState(chainId, staker, operator)
+		match: func(args []any) bool {
+			return len(args) == 3 && args[1] == staker && args[2] == operator
+		},
+	})
+}
+
+// StakingUndelegate undelegates the owner of key from its operator
+func StakingUndelegate(ctx context.Context, env *Env, key *ecdsa.PrivateKey) (*Outcome, error) {
+	staker := crypto.PubkeyToAddress(key.PublicKey)
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, err
+	}
+	mgr, err := NewStakingDelegationManager(env.Bindings.RPCClient, txMgr, env.Bindings.ContractAddress.StakingDelegationManager)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := mgr.Undelegate(ctx, staker)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.awaitForward(ctx, result, forwardQuery{
+		contract: "PellDelegationManager",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.PellDelegationManager),
+		metaData: pelldelegationmanager.PellDelegationManagerMetaData,
+		method:   "syncUndelegateState",
+		// syncUndelegateState(chainId, staker)
+		match: func(args []any) bool {
+			return len(args) == 2 && args[1] == staker
+		},
+	})
+}
+
+// StakingQueueWithdrawal queues the withdrawal of shares[i] from strategies[i] for the owner of key
+func StakingQueueWithdrawal(
+	ctx context.Context, env *Env, key *ecdsa.PrivateKey, strategies []gethcommon.Address, shares []*big.Int,
+) (*Outcome, error) {
+	staker := crypto.PubkeyToAddress(key.PublicKey)
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, err
+	}
+	mgr, err := NewStakingDelegationManager(env.Bindings.RPCClient, txMgr, env.Bindings.ContractAddress.StakingDelegationManager)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := mgr.QueueWithdrawals(ctx, strategies, shares, staker)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.awaitForward(ctx, result, forwardQuery{
+		contract: "PellDelegationManager",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.PellDelegationManager),
+		metaData: pelldelegationmanager.PellDelegationManagerMetaData,
+		method:   "syncWithdrawalState",
+		// syncWithdrawalState(chainId, staker, operator, (strategies, shares))
+		match: func(args []any) bool {
+			if len(args) != 4 || args[1] != staker {
+				return false
+			}
+			var params struct {
+				Strategies []gethcommon.Address
+				Shares     []*big.Int
+			}
+			return unpackTuple(args[3], &params) &&
+				reflect.DeepEqual(params.Strategies, strategies) && equalAmounts(params.Shares, shares)
+		},
+	})
+}
+
+// PellRegisterOperator registers the owner of key as an operator on pell.
+// An operator already registered is not registered again, the outcome is nil then.
+func PellRegisterOperator(ctx context.Context, env *Env, key *ecdsa.PrivateKey, metadataURI string) (*Outcome, error) {
+	operator := crypto.PubkeyToAddress(key.PublicKey)
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, err
+	}
+	mgr, err := NewPellDelegationManager(env.Bindings.RPCClient, txMgr, env.Bindings.ContractAddress.PellDelegationManager)
+	if err != nil {
+		return nil, err
+	}
+
+	isOperator, err := mgr.IsOperator(ctx, operator)
+	if err != nil {
+		return nil, err
+	}
+	if isOperator {
+		logger.Info("operator already registered on pell, skip", "operator", operator.Hex())
+		return nil, nil
+	}
+
+	result, err := mgr.RegisterAsOperator(ctx, metadataURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.awaitForward(ctx, result, forwardQuery{
+		contract: "StakingDelegationManager",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.StakingDelegationManager),
+		metaData: delegationmanager.DelegationManagerMetaData,
+		method:   "syncRegisterAsOperator",
+		// syncRegisterAsOperator(operator, details)
+		match: func(args []any) bool {
+			return len(args) == 2 && args[0] == operator
+		},
+	})
+}
+
+// DVSRegisterOperator registers the owner of key with blsKey to groups of the DVS
+func DVSRegisterOperator(
+	ctx context.Context, env *Env, key *ecdsa.PrivateKey, blsKey *bls.KeyPair, groups []byte, socket string,
+	dvsDirectory gethcommon.Address,
+) (*Outcome, error) {
+	operator := crypto.PubkeyToAddress(key.PublicKey)
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, err
+	}
+	router, err := NewPellRegistryRouter(env.Bindings.RPCClient, txMgr, env.Bindings.ContractAddress.PellRegistryRouter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := router.RegisterOperator(ctx, key, blsKey, groups, socket, dvsDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.awaitForward(ctx, result, forwardQuery{
+		contract: "DVSCentralScheduler",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.DVSCentralScheduler),
+		metaData: centralscheduler.CentralSchedulerMetaData,
+		method:   "syncRegisterOperator",
+		// syncRegisterOperator(operator, groupNumbers, params)
+		match: func(args []any) bool {
+			return len(args) == 3 && args[0] == operator
+		},
+	})
+}
+
+// DVSRegisterChain registers the DVS central scheduler to pell, approverKey signs for the dvs chain approver
+func DVSRegisterChain(ctx context.Context, env *Env, key, approverKey *ecdsa.PrivateKey) (*Outcome, error) {
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, err
+	}
+	scheduler, err := NewDVSCentralScheduler(env.Bindings.RPCClient, txMgr,
+		env.Bindings.ContractAddress.DVSCentralScheduler, env.Bindings.ContractAddress.PellRegistryRouter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := scheduler.RegisterChainToPell(ctx, env.Bindings.ChainID,
+		gethcommon.HexToAddress(env.Bindings.ContractAddress.DVSOperatorStakeManager), approverKey,
+		gethcommon.HexToAddress(env.Bindings.ContractAddress.PellRegistryInteractor))
+	if err != nil {
+		return nil, err
+	}
+
+	centralScheduler := gethcommon.HexToAddress(env.Bindings.ContractAddress.DVSCentralScheduler)
+	return env.awaitForward(ctx, result, forwardQuery{
+		contract: "PellRegistryRouter",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.PellRegistryRouter),
+		metaData: registryrouter.RegistryRouterMetaData,
+		method:   "addSupportedChain",
+		// addSupportedChain(dvsInfo, dvsChainApproverSignature)
+		match: func(args []any) bool {
+			var info dvsInfo
+			return len(args) == 2 && unpackTuple(args[0], &info) && info.CentralScheduler == centralScheduler
+		},
+	})
+}
+
+// DVSCreateGroup creates a group of cfg on the registry router and returns its number
+func DVSCreateGroup(ctx context.Context, env *Env, key *ecdsa.PrivateKey, cfg *GroupConfig) (*Outcome, uint8, error) {
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	router, err := NewPellRegistryRouter(env.Bindings.RPCClient, txMgr, env.Bindings.ContractAddress.PellRegistryRouter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result, err := router.CreateGroup(ctx, cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+	groupNumber, ok := eventArg[uint8](result, "PellRegistryRouter", "SyncCreateGroup", "groupNumber")
+	if !ok {
+		return nil, 0, errors.Errorf("no SyncCreateGroup event in tx %s", result.Receipt.TxHash.Hex())
+	}
+
+	outcome, err := env.awaitForward(ctx, result, forwardQuery{
+		contract: "DVSCentralScheduler",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.DVSCentralScheduler),
+		metaData: centralscheduler.CentralSchedulerMetaData,
+		method:   "syncCreateGroup",
+		// syncCreateGroup(groupNumber, operatorSetParams, minimumStake, poolParams)
+		match: func(args []any) bool {
+			return len(args) == 4 && args[0] == groupNumber
+		},
+	})
+	return outcome, groupNumber, err
+}
+
+// DVSAddPools adds the pools of cfg to the group
+func DVSAddPools(
+	ctx context.Context, env *Env, key *ecdsa.PrivateKey, groupNumber uint8, cfg *GroupConfig,
+) (*Outcome, error) {
+	txMgr, err := env.txMgr(key)
+	if err != nil {
+		return nil, err
+	}
+	router, err := NewPellStakeRegistryRouter(env.Bindings.RPCClient, txMgr, env.Bindings.ContractAddress.PellStakeRegistryRouter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := router.AddPools(ctx, groupNumber, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.awaitForward(ctx, result, forwardQuery{
+		contract: "DVSOperatorStakeManager",
+		target:   gethcommon.HexToAddress(env.Bindings.ContractAddress.DVSOperatorStakeManager),
+		metaData: operatorstakemanager.OperatorStakeManagerMetaData,
+		method:   "syncAddPools",
+		// syncAddPools(groupNumber, poolParams)
+		match: func(args []any) bool {
+			return len(args) == 2 && args[0] == groupNumber
+		},
+	})
+}
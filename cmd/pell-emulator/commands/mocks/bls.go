@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/crypto/bls"
+)
+
+// LoadOrCreateBLSKey reads the bls key at path, generating and saving a new one when there is none.
+// Without a path the generated key is not saved.
+func LoadOrCreateBLSKey(path, password string) (*bls.KeyPair, error) {
+	if path != "" {
+		if _, err := os.Stat(path); err == nil {
+			key, err := bls.ReadPrivateKeyFromFile(path, password)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read bls key %s", path)
+			}
+			logger.Info("loaded bls key", "file", path, "pubkeyG1", key.GetPubKeyG1().String())
+			return key, nil
+		} else if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "failed to stat bls key %s", path)
+		}
+	}
+
+	key, err := bls.GenRandomBlsKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate bls key")
+	}
+	if path == "" {
+		logger.Info("generated bls key, it is not saved without --bls-key-file", "pubkeyG1", key.GetPubKeyG1().String())
+		return key, nil
+	}
+	if err := key.SaveToFile(path, password); err != nil {
+		return nil, errors.Wrapf(err, "failed to save bls key %s", path)
+	}
+	logger.Info("generated bls key", "file", path, "pubkeyG1", key.GetPubKeyG1().String())
+	return key, nil
+}
+
+// g1Coordinates returns the coordinates of p as the contracts take them
+func g1Coordinates(p *bls.G1Point) (x, y *big.Int) {
+	return p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int))
+}
+
+// g2Coordinates returns the coordinates of p as the contracts take them, see bls.NewG2Point
+func g2Coordinates(p *bls.G2Point) (x, y [2]*big.Int) {
+	x = [2]*big.Int{p.X.A1.BigInt(new(big.Int)), p.X.A0.BigInt(new(big.Int))}
+	y = [2]*big.Int{p.Y.A1.BigInt(new(big.Int)), p.Y.A0.BigInt(new(big.Int))}
+	return x, y
+}
+
+// parseGroupNumbers parses comma separated group numbers
+func parseGroupNumbers(value string) ([]byte, error) {
+	var groups []byte
+	for _, s := range strings.Split(value, ",") {
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 8)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid group number %q", s)
+		}
+		groups = append(groups, byte(n))
+	}
+	return groups, nil
+}
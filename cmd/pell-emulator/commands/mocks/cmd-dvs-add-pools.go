@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+)
+
+func init() {
+	KeyFileFlag.AddToCmdFlag(EmulatorMocksCmdDVSAddPoolsCmd)
+	GroupNumberFlag.AddToCmdFlag(EmulatorMocksCmdDVSAddPoolsCmd)
+	GroupConfigFlag.AddToCmdFlag(EmulatorMocksCmdDVSAddPoolsCmd)
+	WaitTimeoutFlag.AddToCmdFlag(EmulatorMocksCmdDVSAddPoolsCmd)
+
+	// mark required flags
+	_ = chainflags.MarkFlagsAreRequired(EmulatorMocksCmdDVSAddPoolsCmd,
+		KeyFileFlag,
+		GroupNumberFlag,
+		GroupConfigFlag,
+	)
+}
+
+var EmulatorMocksCmdDVSAddPoolsCmd = &cobra.Command{
+	Use:   "dvs-add-pools",
+	Short: "pell_emulator mocks add pools to a dvs group",
+	Long:  "Adds the pool_params of the group config to an existing group, the other fields of the config are ignored.",
+	Example: `
+	pell-emulator mocks dvs-add-pools --key-file ./admin.ecdsa.key.json --group 0 --group-config ./group-0-pools.json
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if GroupNumberFlag.Value < 0 || GroupNumberFlag.Value > 255 {
+			return errors.Errorf("invalid group number %d", GroupNumberFlag.Value)
+		}
+		groupCfg, err := LoadGroupConfig(GroupConfigFlag.Value)
+		if err != nil {
+			return err
+		}
+		env, err := newMocksEnv(cmd)
+		if err != nil {
+			return err
+		}
+		pk, err := env.Bindings.ReadKeyFile(KeyFileFlag.Value)
+		if err != nil {
+			return err
+		}
+
+		outcome, err := DVSAddPools(cmd.Context(), env, pk, uint8(GroupNumberFlag.Value), groupCfg)
+		if err != nil {
+			return err
+		}
+
+		keyvals := []any{"groupNumber", GroupNumberFlag.Value}
+		if outcome.Forward != nil {
+			keyvals = append(keyvals, "dvsEvents", len(outcome.Forward.Events))
+		}
+		logOutcome("pools", outcome, keyvals...)
+		return nil
+	},
+}
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+)
+
+func init() {
+	KeyFileFlag.AddToCmdFlag(EmulatorMocksCmdDVSCreateGroupCmd)
+	GroupConfigFlag.AddToCmdFlag(EmulatorMocksCmdDVSCreateGroupCmd)
+	WaitTimeoutFlag.AddToCmdFlag(EmulatorMocksCmdDVSCreateGroupCmd)
+
+	// mark required flags
+	_ = chainflags.MarkFlagsAreRequired(EmulatorMocksCmdDVSCreateGroupCmd,
+		KeyFileFlag,
+		GroupConfigFlag,
+	)
+}
+
+var EmulatorMocksCmdDVSCreateGroupCmd = &cobra.Command{
+	Use:   "dvs-create-group",
+	Short: "pell_emulator mocks create a dvs group on the registry router",
+	Example: `
+	pell-emulator mocks dvs-create-group --key-file ./admin.ecdsa.key.json --group-config ./group-0-config.json
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		groupCfg, err := LoadGroupConfig(GroupConfigFlag.Value)
+		if err != nil {
+			return err
+		}
+		env, err := newMocksEnv(cmd)
+		if err != nil {
+			return err
+		}
+		pk, err := env.Bindings.ReadKeyFile(KeyFileFlag.Value)
+		if err != nil {
+			return err
+		}
+
+		outcome, groupNumber, err := DVSCreateGroup(cmd.Context(), env, pk, groupCfg)
+		if err != nil {
+			return err
+		}
+
+		keyvals := []any{"groupNumber", groupNumber}
+		if outcome.Forward != nil {
+			scheduler, err := NewDVSCentralScheduler(env.Bindings.RPCClient, nil,
+				env.Bindings.ContractAddress.DVSCentralScheduler, env.Bindings.ContractAddress.PellRegistryRouter)
+			if err != nil {
+				return err
+			}
+			groupCount, err := scheduler.GroupCount(cmd.Context())
+			if err != nil {
+				return err
+			}
+			keyvals = append(keyvals, "dvsGroupCount", groupCount)
+		}
+		logOutcome("group creation", outcome, keyvals...)
+		return nil
+	},
+}
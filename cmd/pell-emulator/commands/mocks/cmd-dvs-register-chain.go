@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+)
+
+func init() {
+	KeyFileFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterChainCmd)
+	ApproverKeyFileFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterChainCmd)
+	WaitTimeoutFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterChainCmd)
+
+	// mark required flags
+	_ = chainflags.MarkFlagsAreRequired(EmulatorMocksCmdDVSRegisterChainCmd,
+		KeyFileFlag,
+	)
+}
+
+var EmulatorMocksCmdDVSRegisterChainCmd = &cobra.Command{
+	Use:   "dvs-register-chain",
+	Short: "pell_emulator mocks register the dvs central scheduler to pell",
+	Example: `
+	pell-emulator mocks dvs-register-chain --key-file ./admin.ecdsa.key.json --approver-key-file ./approver.ecdsa.key.json
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, err := newMocksEnv(cmd)
+		if err != nil {
+			return err
+		}
+		pk, err := env.Bindings.ReadKeyFile(KeyFileFlag.Value)
+		if err != nil {
+			return err
+		}
+		approverKey := pk
+		if ApproverKeyFileFlag.Value != "" {
+			approverKey, err = env.Bindings.ReadKeyFile(ApproverKeyFileFlag.Value)
+			if err != nil {
+				return err
+			}
+		}
+
+		outcome, err := DVSRegisterChain(cmd.Context(), env, pk, approverKey)
+		if err != nil {
+			return err
+		}
+
+		logOutcome("chain registration", outcome, "centralScheduler", env.Bindings.ContractAddress.DVSCentralScheduler)
+		return nil
+	},
+}
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+)
+
+func init() {
+	KeyFileFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterOperatorCmd)
+	BLSKeyFileFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterOperatorCmd)
+	BLSKeyPasswordFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterOperatorCmd)
+	GroupsFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterOperatorCmd)
+	SocketFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterOperatorCmd)
+	chainflags.PellDvsDirectoryAddress.AddToCmdFlag(EmulatorMocksCmdDVSRegisterOperatorCmd)
+	WaitTimeoutFlag.AddToCmdFlag(EmulatorMocksCmdDVSRegisterOperatorCmd)
+
+	// mark required flags
+	_ = chainflags.MarkFlagsAreRequired(EmulatorMocksCmdDVSRegisterOperatorCmd,
+		KeyFileFlag,
+		SocketFlag,
+	)
+}
+
+var EmulatorMocksCmdDVSRegisterOperatorCmd = &cobra.Command{
+	Use:   "dvs-register-operator",
+	Short: "pell_emulator mocks register operator to dvs groups",
+	Example: `
+	pell-emulator mocks dvs-register-operator --key-file ./operator.ecdsa.key.json \
+		--bls-key-file ./operator.bls.key.json \
+		--dvs-directory 0x0165878A594ca255338adfa4d48449f69242Eb8F \
+		--groups 0 --socket http://127.0.0.1:26657
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dvsDirectory := chainflags.PellDvsDirectoryAddress.GetValue()
+		if !gethcommon.IsHexAddress(dvsDirectory) {
+			return errors.Errorf("invalid --%s %q", chainflags.PellDvsDirectoryAddress.Name, dvsDirectory)
+		}
+		groups, err := parseGroupNumbers(GroupsFlag.Value)
+		if err != nil {
+			return err
+		}
+		env, err := newMocksEnv(cmd)
+		if err != nil {
+			return err
+		}
+		pk, err := env.Bindings.ReadKeyFile(KeyFileFlag.Value)
+		if err != nil {
+			return err
+		}
+		blsKey, err := LoadOrCreateBLSKey(BLSKeyFileFlag.Value, BLSKeyPasswordFlag.Value)
+		if err != nil {
+			return err
+		}
+
+		outcome, err := DVSRegisterOperator(cmd.Context(), env, pk, blsKey, groups, SocketFlag.Value,
+			gethcommon.HexToAddress(dvsDirectory))
+		if err != nil {
+			return err
+		}
+
+		logOutcome("dvs operator registration", outcome,
+			"operator", crypto.PubkeyToAddress(pk.PublicKey).Hex(), "groups", groups)
+		return nil
+	},
+}
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+)
+
+func init() {
+	KeyFileFlag.AddToCmdFlag(EmulatorMocksCmdPellRegisterOperatorCmd)
+	MetadataURIFlag.AddToCmdFlag(EmulatorMocksCmdPellRegisterOperatorCmd)
+	WaitTimeoutFlag.AddToCmdFlag(EmulatorMocksCmdPellRegisterOperatorCmd)
+
+	// mark required flags
+	_ = chainflags.MarkFlagsAreRequired(EmulatorMocksCmdPellRegisterOperatorCmd,
+		KeyFileFlag,
+	)
+}
+
+var EmulatorMocksCmdPellRegisterOperatorCmd = &cobra.Command{
+	Use:   "pell-register-operator",
+	Short: "pell_emulator mocks register operator on pell",
+	Example: `
+	pell-emulator mocks pell-register-operator --key-file ./operator.ecdsa.key.json \
+		--metadata-uri https://example.com/operator.json
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, err := newMocksEnv(cmd)
+		if err != nil {
+			return err
+		}
+		pk, err := env.Bindings.ReadKeyFile(KeyFileFlag.Value)
+		if err != nil {
+			return err
+		}
+
+		outcome, err := PellRegisterOperator(cmd.Context(), env, pk, MetadataURIFlag.Value)
+		if err != nil || outcome == nil {
+			return err
+		}
+
+		logOutcome("operator registration", outcome, "operator", crypto.PubkeyToAddress(pk.PublicKey).Hex())
+		return nil
+	},
+}
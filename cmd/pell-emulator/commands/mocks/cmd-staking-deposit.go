@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+)
+
+func init() {
+	KeyFileFlag.AddToCmdFlag(EmulatorMocksCmdStakingDepositCmd)
+	TokenFlag.AddToCmdFlag(EmulatorMocksCmdStakingDepositCmd)
+	StrategyFlag.AddToCmdFlag(EmulatorMocksCmdStakingDepositCmd)
+	AmountFlag.AddToCmdFlag(EmulatorMocksCmdStakingDepositCmd)
+	WaitTimeoutFlag.AddToCmdFlag(EmulatorMocksCmdStakingDepositCmd)
+
+	// mark required flags
+	_ = chainflags.MarkFlagsAreRequired(EmulatorMocksCmdStakingDepositCmd,
+		KeyFileFlag,
+		TokenFlag,
+		StrategyFlag,
+		AmountFlag,
+	)
+}
+
+var EmulatorMocksCmdStakingDepositCmd = &cobra.Command{
+	Use:   "staking-deposit",
+	Short: "pell_emulator mocks staking deposit into a strategy",
+	Example: `
+	pell-emulator mocks staking-deposit --key-file ./staker.ecdsa.key.json \
+		--token 0x5FbDB2315678afecb367f032d93F642f64180aa3 \
+		--strategy 0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512 \
+		--amount 1000000000000000000
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		amount, ok := new(big.Int).SetString(AmountFlag.Value, 10)
+		if !ok || amount.Sign() <= 0 {
+			return errors.Errorf("invalid amount %q", AmountFlag.Value)
+		}
+		env, err := newMocksEnv(cmd)
+		if err != nil {
+			return err
+		}
+		pk, err := env.Bindings.ReadKeyFile(KeyFileFlag.Value)
+		if err != nil {
+			return err
+		}
+
+		strategy := gethcommon.HexToAddress(StrategyFlag.Value)
+		outcome, err := StakingDeposit(cmd.Context(), env, pk, gethcommon.HexToAddress(TokenFlag.Value), strategy, amount)
+		if err != nil {
+			return err
+		}
+
+		// the shares the pell side accounts the staker, next to the ones minted on the staking chain
+		sourceShares, _ := eventArg[*big.Int](outcome.Source, "StakingStrategyManager", "Deposit", "shares")
+		shares, err := PellStakerShares(cmd.Context(), env, crypto.PubkeyToAddress(pk.PublicKey), strategy)
+		if err != nil {
+			return err
+		}
+		logOutcome("deposit", outcome, "shares", shares, "sourceShares", sourceShares)
+		return nil
+	},
+}
@@ -0,0 +1,94 @@
+package mocks
+
+import (
+	"math/big"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+)
+
+func init() {
+	KeyFileFlag.AddToCmdFlag(EmulatorMocksCmdStakingQueueWithdrawalCmd)
+	StrategiesFlag.AddToCmdFlag(EmulatorMocksCmdStakingQueueWithdrawalCmd)
+	SharesFlag.AddToCmdFlag(EmulatorMocksCmdStakingQueueWithdrawalCmd)
+	WaitTimeoutFlag.AddToCmdFlag(EmulatorMocksCmdStakingQueueWithdrawalCmd)
+
+	// mark required flags
+	_ = chainflags.MarkFlagsAreRequired(EmulatorMocksCmdStakingQueueWithdrawalCmd,
+		KeyFileFlag,
+		StrategiesFlag,
+		SharesFlag,
+	)
+}
+
+var EmulatorMocksCmdStakingQueueWithdrawalCmd = &cobra.Command{
+	Use:   "staking-queue-withdrawal",
+	Short: "pell_emulator mocks staking queue withdrawal",
+	Example: `
+	pell-emulator mocks staking-queue-withdrawal --key-file ./staker.ecdsa.key.json \
+		--strategies 0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512,0x9fE46736679d2D9a65F0992F2272dE9f3c7fa6e0 \
+		--shares 1000000000000000000,500000000000000000
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		strategies, shares, err := parseStrategyShares(StrategiesFlag.Value, SharesFlag.Value)
+		if err != nil {
+			return err
+		}
+		env, err := newMocksEnv(cmd)
+		if err != nil {
+			return err
+		}
+		pk, err := env.Bindings.ReadKeyFile(KeyFileFlag.Value)
+		if err != nil {
+			return err
+		}
+
+		outcome, err := StakingQueueWithdrawal(cmd.Context(), env, pk, strategies, shares)
+		if err != nil {
+			return err
+		}
+
+		logOutcome("withdrawal", outcome, "withdrawalRoots", withdrawalRoots(outcome.Source))
+		return nil
+	},
+}
+
+// parseStrategyShares parses comma separated strategies and the share amounts withdrawn from them
+func parseStrategyShares(strategiesValue, sharesValue string) ([]gethcommon.Address, []*big.Int, error) {
+	var strategies []gethcommon.Address
+	for _, s := range strings.Split(strategiesValue, ",") {
+		s = strings.TrimSpace(s)
+		if !gethcommon.IsHexAddress(s) {
+			return nil, nil, errors.Errorf("invalid strategy address %q", s)
+		}
+		strategies = append(strategies, gethcommon.HexToAddress(s))
+	}
+	var shares []*big.Int
+	for _, s := range strings.Split(sharesValue, ",") {
+		amount, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+		if !ok || amount.Sign() <= 0 {
+			return nil, nil, errors.Errorf("invalid share amount %q", s)
+		}
+		shares = append(shares, amount)
+	}
+	if len(strategies) != len(shares) {
+		return nil, nil, errors.Errorf("got %d strategies and %d share amounts", len(strategies), len(shares))
+	}
+	return strategies, shares, nil
+}
+
+func equalAmounts(a, b []*big.Int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] == nil || a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
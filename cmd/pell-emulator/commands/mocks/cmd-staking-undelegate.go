@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+)
+
+func init() {
+	KeyFileFlag.AddToCmdFlag(EmulatorMocksCmdStakingUndelegateCmd)
+	WaitTimeoutFlag.AddToCmdFlag(EmulatorMocksCmdStakingUndelegateCmd)
+
+	// mark required flags
+	_ = chainflags.MarkFlagsAreRequired(EmulatorMocksCmdStakingUndelegateCmd,
+		KeyFileFlag,
+	)
+}
+
+var EmulatorMocksCmdStakingUndelegateCmd = &cobra.Command{
+	Use:   "staking-undelegate",
+	Short: "pell_emulator mocks staking undelegate",
+	Example: `
+	pell-emulator mocks staking-undelegate --key-file ./staker.ecdsa.key.json
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, err := newMocksEnv(cmd)
+		if err != nil {
+			return err
+		}
+		pk, err := env.Bindings.ReadKeyFile(KeyFileFlag.Value)
+		if err != nil {
+			return err
+		}
+
+		outcome, err := StakingUndelegate(cmd.Context(), env, pk)
+		if err != nil {
+			return err
+		}
+
+		logOutcome("undelegation", outcome, "withdrawalRoots", withdrawalRoots(outcome.Source))
+		return nil
+	},
+}
@@ -0,0 +1,83 @@
+package mocks
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+)
+
+// erc20MetaData is the part of the ERC-20 abi the mocks use
+var erc20MetaData = &bind.MetaData{
+	ABI: `[
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"event","name":"Approval","anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`,
+}
+
+type ERC20 struct {
+	contract *bind.BoundContract
+	txMgr    txmgr.TxManager
+	address  gethcommon.Address
+}
+
+func NewERC20(rpcClient eth.Client, txMgr txmgr.TxManager, address string) (*ERC20, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20MetaData.ABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse erc20 abi")
+	}
+	token := gethcommon.HexToAddress(address)
+	return &ERC20{
+		contract: bind.NewBoundContract(token, parsed, rpcClient, rpcClient, rpcClient),
+		txMgr:    txMgr,
+		address:  token,
+	}, nil
+}
+
+func (t *ERC20) eventSource() eventSource {
+	return eventSource{"ERC20", t.address, erc20MetaData}
+}
+
+func (t *ERC20) call(ctx context.Context, method string, args ...any) (*big.Int, error) {
+	var out []any
+	if err := t.contract.Call(&bind.CallOpts{Context: ctx}, &out, method, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to call %s", method)
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+func (t *ERC20) BalanceOf(ctx context.Context, account gethcommon.Address) (*big.Int, error) {
+	return t.call(ctx, "balanceOf", account)
+}
+
+func (t *ERC20) Allowance(ctx context.Context, owner, spender gethcommon.Address) (*big.Int, error) {
+	return t.call(ctx, "allowance", owner, spender)
+}
+
+// Approve lets spender transfer amount of the tokens of the sender
+func (t *ERC20) Approve(ctx context.Context, spender gethcommon.Address, amount *big.Int) (*Result, error) {
+	lg := logger.With("comps", "MocksERC20", "token", t.address.Hex())
+	lg.Info("Approving", "spender", spender.Hex(), "amount", amount)
+
+	noSendTxOpts, err := t.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
+	}
+	tx, err := t.contract.Transact(noSendTxOpts, "approve", spender, amount)
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+	return sendMockTx(ctx, lg, t.txMgr, tx, t.eventSource())
+}
@@ -2,7 +2,81 @@ package mocks
 
 import "github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
 
-var KeyFileFlag = chainflags.StringFlag{
+var KeyFileFlag = &chainflags.StringFlag{
 	Name:  "key-file",
 	Usage: "key file",
 }
+
+var TokenFlag = &chainflags.StringFlag{
+	Name:  "token",
+	Usage: "ERC-20 token address",
+}
+
+var StrategyFlag = &chainflags.StringFlag{
+	Name:  "strategy",
+	Usage: "strategy address",
+}
+
+var AmountFlag = &chainflags.StringFlag{
+	Name:  "amount",
+	Usage: "token amount in wei",
+}
+
+var WaitTimeoutFlag = &chainflags.StringFlag{
+	Name:    "wait-timeout",
+	Usage:   "how long to wait for the emulator to forward the event, 0 to not wait",
+	Value:   "2m",
+	Default: "2m",
+}
+
+var StrategiesFlag = &chainflags.StringFlag{
+	Name:  "strategies",
+	Usage: "comma separated strategy addresses",
+}
+
+var SharesFlag = &chainflags.StringFlag{
+	Name:  "shares",
+	Usage: "comma separated share amounts in wei, one for each strategy",
+}
+
+var MetadataURIFlag = &chainflags.StringFlag{
+	Name:  "metadata-uri",
+	Usage: "operator metadata uri",
+}
+
+var BLSKeyFileFlag = &chainflags.StringFlag{
+	Name:  "bls-key-file",
+	Usage: "bls key file, a new key is generated and saved to it when it does not exist",
+}
+
+var BLSKeyPasswordFlag = &chainflags.StringFlag{
+	Name:  "bls-key-password",
+	Usage: "password of the bls key file",
+}
+
+var GroupsFlag = &chainflags.StringFlag{
+	Name:    "groups",
+	Usage:   "comma separated group numbers",
+	Value:   "0",
+	Default: "0",
+}
+
+var SocketFlag = &chainflags.StringFlag{
+	Name:  "socket",
+	Usage: "operator socket",
+}
+
+var GroupConfigFlag = &chainflags.StringFlag{
+	Name:  "group-config",
+	Usage: "group config file with minimum_stake, pool_params and operator_set_params",
+}
+
+var GroupNumberFlag = &chainflags.IntFlag{
+	Name:  "group",
+	Usage: "group number",
+}
+
+var ApproverKeyFileFlag = &chainflags.StringFlag{
+	Name:  "approver-key-file",
+	Usage: "key file of the dvs chain approver, the key file by default",
+}
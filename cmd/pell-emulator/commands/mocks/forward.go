@@ -0,0 +1,119 @@
+package mocks
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var forwardPollInterval = 2 * time.Second
+
+// forwardQuery describes the target call the emulator makes for a source event
+type forwardQuery struct {
+	contract string
+	target   gethcommon.Address
+	metaData *bind.MetaData
+	method   string
+	// match tells whether the unpacked arguments of a call are the forward of the source event
+	match func(args []any) bool
+}
+
+// waitForForward waits for the emulator to call q.method on q.target in a block from fromBlock on,
+// and returns the receipt of that call with the events of the target decoded. A reverted call
+// is returned as an error.
+func waitForForward(
+	ctx context.Context, lg log.Logger, client eth.Client, fromBlock uint64, q forwardQuery, timeout time.Duration,
+) (*Result, error) {
+	contractABI, err := q.metaData.GetAbi()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse abi of %s", q.contract)
+	}
+	method, ok := contractABI.Methods[q.method]
+	if !ok {
+		return nil, errors.Errorf("%s has no method %s", q.contract, q.method)
+	}
+
+	lg = lg.With("target", q.contract+"."+q.method)
+	lg.Info("waiting for the emulator to forward the event", "timeout", timeout)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(forwardPollInterval)
+	defer ticker.Stop()
+
+	next := fromBlock
+	for {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get block number")
+		}
+		for ; next <= head; next++ {
+			block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(next))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get block %d", next)
+			}
+			for _, tx := range block.Transactions() {
+				if tx.To() == nil || *tx.To() != q.target || !bytes.HasPrefix(tx.Data(), method.ID) {
+					continue
+				}
+				args, err := method.Inputs.Unpack(tx.Data()[len(method.ID):])
+				if err != nil || !q.match(args) {
+					continue
+				}
+
+				receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to get receipt of %s", tx.Hash().Hex())
+				}
+				if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+					lg.Error("forward reverted", "txHash", tx.Hash().Hex(), "block", next)
+					return nil, errors.Errorf("%s.%s forward %s reverted in block %d", q.contract, q.method, tx.Hash().Hex(), next)
+				}
+				events, err := decodeEvents(receipt.Logs, []eventSource{{q.contract, q.target, q.metaData}})
+				if err != nil {
+					return nil, err
+				}
+				result := &Result{Receipt: receipt, Events: events}
+				logResult(lg, result)
+				return result, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Errorf("no %s.%s forward found up to block %d, is the emulator running?",
+				q.contract, q.method, next-1)
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseWaitTimeout returns the value of WaitTimeoutFlag, 0 means the forward is not awaited
+func parseWaitTimeout() (time.Duration, error) {
+	timeout, err := time.ParseDuration(WaitTimeoutFlag.Value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid wait timeout %q", WaitTimeoutFlag.Value)
+	}
+	return timeout, nil
+}
+
+// unpackTuple copies a tuple argument unpacked by waitForForward into dst, a pointer to a struct
+// with the fields of the tuple. It reports whether the tuple has them.
+func unpackTuple(arg any, dst any) bool {
+	target := reflect.ValueOf(dst).Elem()
+	if arg == nil || !reflect.TypeOf(arg).ConvertibleTo(target.Type()) {
+		return false
+	}
+	target.Set(reflect.ValueOf(arg).Convert(target.Type()))
+	return true
+}
@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/registryrouter.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/stakeregistryrouter.sol"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// GroupConfig is the group file of the dvs mocks, in the format of the pelldvs group config
+type GroupConfig struct {
+	MinimumStake      *big.Int         `json:"minimum_stake"`
+	PoolParams        []PoolParam      `json:"pool_params"`
+	OperatorSetParams OperatorSetParam `json:"operator_set_params"`
+}
+
+type PoolParam struct {
+	ChainID    uint64   `json:"chain_id"`
+	Pool       string   `json:"pool"`
+	Multiplier *big.Int `json:"multiplier"`
+}
+
+type OperatorSetParam struct {
+	MaxOperatorCount        uint32 `json:"max_operator_count"`
+	KickBIPsOfOperatorStake uint16 `json:"kick_bi_ps_of_operator_stake"`
+	KickBIPsOfTotalStake    uint16 `json:"kick_bi_ps_of_total_stake"`
+}
+
+func LoadGroupConfig(path string) (*GroupConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read group config %s", path)
+	}
+	var cfg GroupConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse group config %s", path)
+	}
+	if cfg.MinimumStake == nil {
+		cfg.MinimumStake = big.NewInt(0)
+	}
+	for i, p := range cfg.PoolParams {
+		if !gethcommon.IsHexAddress(p.Pool) {
+			return nil, errors.Errorf("pool_params[%d]: invalid pool %q", i, p.Pool)
+		}
+		if p.Multiplier == nil || p.Multiplier.Sign() <= 0 {
+			return nil, errors.Errorf("pool_params[%d]: multiplier must be positive", i)
+		}
+	}
+	return &cfg, nil
+}
+
+func (c *GroupConfig) registryRouterOperatorSetParam() registryrouter.IRegistryRouterOperatorSetParam {
+	return registryrouter.IRegistryRouterOperatorSetParam{
+		MaxOperatorCount:        c.OperatorSetParams.MaxOperatorCount,
+		KickBIPsOfOperatorStake: c.OperatorSetParams.KickBIPsOfOperatorStake,
+		KickBIPsOfTotalStake:    c.OperatorSetParams.KickBIPsOfTotalStake,
+	}
+}
+
+func (c *GroupConfig) registryRouterPoolParams() []registryrouter.IStakeRegistryRouterPoolParams {
+	params := make([]registryrouter.IStakeRegistryRouterPoolParams, len(c.PoolParams))
+	for i, p := range c.PoolParams {
+		params[i] = registryrouter.IStakeRegistryRouterPoolParams{
+			ChainId:    new(big.Int).SetUint64(p.ChainID),
+			Pool:       gethcommon.HexToAddress(p.Pool),
+			Multiplier: p.Multiplier,
+		}
+	}
+	return params
+}
+
+func (c *GroupConfig) stakeRegistryRouterPoolParams() []stakeregistryrouter.IStakeRegistryRouterPoolParams {
+	params := make([]stakeregistryrouter.IStakeRegistryRouterPoolParams, len(c.PoolParams))
+	for i, p := range c.PoolParams {
+		params[i] = stakeregistryrouter.IStakeRegistryRouterPoolParams{
+			ChainId:    new(big.Int).SetUint64(p.ChainID),
+			Pool:       gethcommon.HexToAddress(p.Pool),
+			Multiplier: p.Multiplier,
+		}
+	}
+	return params
+}
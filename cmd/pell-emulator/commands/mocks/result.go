@@ -0,0 +1,133 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+// DecodedEvent is a log of a mock tx decoded with the ABI of the contract that emitted it
+type DecodedEvent struct {
+	Contract string         `json:"contract"`
+	Address  string         `json:"address"`
+	Name     string         `json:"name"`
+	Args     map[string]any `json:"args,omitempty"`
+}
+
+// Result is the outcome of a mock tx
+type Result struct {
+	Receipt *gethtypes.Receipt `json:"receipt"`
+	Events  []DecodedEvent     `json:"events"`
+}
+
+// eventSource is a contract whose events are decoded from the receipt of a mock tx
+type eventSource struct {
+	name     string
+	address  gethcommon.Address
+	metaData *bind.MetaData
+}
+
+// sendMockTx sends tx with txMgr and decodes the logs of its receipt emitted by sources.
+// A reverted tx is an error.
+func sendMockTx(
+	ctx context.Context, lg log.Logger, txMgr txmgr.TxManager, tx *gethtypes.Transaction, sources ...eventSource,
+) (*Result, error) {
+	receipt, err := txMgr.Send(ctx, tx)
+	if err != nil {
+		lg.Error("failed to send tx", "err", err)
+		return nil, errors.Wrap(err, "failed to send tx")
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		lg.Error("tx reverted", "txHash", receipt.TxHash.String())
+		return nil, errors.Errorf("tx %s reverted", receipt.TxHash.String())
+	}
+
+	events, err := decodeEvents(receipt.Logs, sources)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Receipt: receipt, Events: events}
+	logResult(lg, result)
+	return result, nil
+}
+
+func decodeEvents(logs []*gethtypes.Log, sources []eventSource) ([]DecodedEvent, error) {
+	abis := make(map[gethcommon.Address]*abi.ABI, len(sources))
+	names := make(map[gethcommon.Address]string, len(sources))
+	for _, source := range sources {
+		contractABI, err := source.metaData.GetAbi()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse abi of %s", source.name)
+		}
+		abis[source.address] = contractABI
+		names[source.address] = source.name
+	}
+
+	events := make([]DecodedEvent, 0, len(logs))
+	for _, l := range logs {
+		event := DecodedEvent{Contract: names[l.Address], Address: l.Address.Hex()}
+		contractABI, ok := abis[l.Address]
+		if !ok || len(l.Topics) == 0 {
+			events = append(events, event)
+			continue
+		}
+		abiEvent, err := contractABI.EventByID(l.Topics[0])
+		if err != nil {
+			events = append(events, event)
+			continue
+		}
+
+		event.Name = abiEvent.Name
+		event.Args = make(map[string]any)
+		if len(l.Data) > 0 {
+			if err := contractABI.UnpackIntoMap(event.Args, abiEvent.Name, l.Data); err != nil {
+				return nil, errors.Wrapf(err, "failed to decode %s", abiEvent.Name)
+			}
+		}
+		var indexed abi.Arguments
+		for _, arg := range abiEvent.Inputs {
+			if arg.Indexed {
+				indexed = append(indexed, arg)
+			}
+		}
+		if err := abi.ParseTopicsIntoMap(event.Args, indexed, l.Topics[1:]); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode topics of %s", abiEvent.Name)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func logResult(lg log.Logger, result *Result) {
+	lg.Info("tx successfully included",
+		"txHash", result.Receipt.TxHash.String(),
+		"block", result.Receipt.BlockNumber,
+		"gasUsed", result.Receipt.GasUsed,
+	)
+	for _, event := range result.Events {
+		if event.Name == "" {
+			lg.Info("event", "address", event.Address, "contract", event.Contract, "decoded", false)
+			continue
+		}
+		lg.Info("event", "name", event.Name, "contract", event.Contract, "args", event.Args)
+	}
+}
+
+// eventArg returns the argument name of the first event of contract in result
+func eventArg[T any](result *Result, contract, event, name string) (T, bool) {
+	var zero T
+	for _, e := range result.Events {
+		if e.Contract == contract && e.Name == event {
+			v, ok := e.Args[name].(T)
+			return v, ok
+		}
+	}
+	return zero, false
+}
@@ -19,6 +19,14 @@ func init() {
 
 	// add subcommands
 	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdStakingDelegateToCmd)
+	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdStakingDepositCmd)
+	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdStakingUndelegateCmd)
+	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdStakingQueueWithdrawalCmd)
+	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdPellRegisterOperatorCmd)
+	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdDVSRegisterOperatorCmd)
+	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdDVSRegisterChainCmd)
+	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdDVSCreateGroupCmd)
+	EmulatorMocksCmd.AddCommand(EmulatorMocksCmdDVSAddPoolsCmd)
 }
 
 var EmulatorMocksCmd = &cobra.Command{
@@ -39,3 +47,27 @@ func setupForMocksCmd(cmd *cobra.Command) (*chains.ChainBindings, error) {
 
 	return bindings, nil
 }
+
+// newMocksEnv sets up the chain of the mock commands, waiting for forwards as long as WaitTimeoutFlag
+func newMocksEnv(cmd *cobra.Command) (*Env, error) {
+	waitTimeout, err := parseWaitTimeout()
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := setupForMocksCmd(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &Env{Bindings: bindings, WaitTimeout: waitTimeout}, nil
+}
+
+// logOutcome logs the source tx of a mock action and its forward
+func logOutcome(action string, outcome *Outcome, keyvals ...any) {
+	keyvals = append([]any{"sourceTxHash", outcome.Source.Receipt.TxHash.String()}, keyvals...)
+	if outcome.Forward == nil {
+		logger.Info(action+" sent", keyvals...)
+		return
+	}
+	keyvals = append(keyvals, "forwardTxHash", outcome.Forward.Receipt.TxHash.String())
+	logger.Info(action+" forwarded", keyvals...)
+}
@@ -0,0 +1,157 @@
+package mocks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/service_evm/registryinteractor.sol"
+	"github.com/0xPellNetwork/pell-middleware-contracts/pkg/src/centralscheduler.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+)
+
+// dvsChainRegistrationABI is the part of the DVSCentralScheduler and PellRegistryRouter abis
+// the chain registration uses
+const dvsChainRegistrationABI = `[
+	{"type":"function","name":"ejectionManager","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"registerChainToPell","stateMutability":"nonpayable","inputs":[{"name":"dvsChainApproverSignature","type":"tuple","components":[{"name":"signature","type":"bytes"},{"name":"salt","type":"bytes32"},{"name":"expiry","type":"uint256"}]}],"outputs":[]},
+	{"type":"function","name":"calculateAddSupportedDVSApproverDigestHash","stateMutability":"view","inputs":[{"name":"dvsInfo","type":"tuple","components":[{"name":"chainId","type":"uint256"},{"name":"centralScheduler","type":"address"},{"name":"ejectionManager","type":"address"},{"name":"stakeManager","type":"address"}]},{"name":"approver","type":"address"},{"name":"salt","type":"bytes32"},{"name":"expiry","type":"uint256"}],"outputs":[{"name":"","type":"bytes32"}]}
+]`
+
+// dvsInfo is the DVS a chain registration adds to the PellRegistryRouter
+type dvsInfo struct {
+	ChainId          *big.Int
+	CentralScheduler gethcommon.Address
+	EjectionManager  gethcommon.Address
+	StakeManager     gethcommon.Address
+}
+
+type signatureWithSaltAndExpiry struct {
+	Signature []byte
+	Salt      [32]byte
+	Expiry    *big.Int
+}
+
+type DVSCentralScheduler struct {
+	contract       *centralscheduler.CentralScheduler
+	registration   *bind.BoundContract
+	registryRouter *bind.BoundContract
+	rpcClient      eth.Client
+	txMgr          txmgr.TxManager
+	address        gethcommon.Address
+}
+
+func NewDVSCentralScheduler(
+	rpcClient eth.Client, txMgr txmgr.TxManager, address string, registryRouter string,
+) (*DVSCentralScheduler, error) {
+	dcs := &DVSCentralScheduler{
+		rpcClient: rpcClient,
+		txMgr:     txMgr,
+		address:   gethcommon.HexToAddress(address),
+	}
+	var err error
+	dcs.contract, err = centralscheduler.NewCentralScheduler(dcs.address, rpcClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create central scheduler")
+	}
+	parsed, err := abi.JSON(strings.NewReader(dvsChainRegistrationABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse chain registration abi")
+	}
+	dcs.registration = bind.NewBoundContract(dcs.address, parsed, rpcClient, rpcClient, rpcClient)
+	dcs.registryRouter = bind.NewBoundContract(gethcommon.HexToAddress(registryRouter), parsed, rpcClient, rpcClient, rpcClient)
+	return dcs, nil
+}
+
+func (dcs *DVSCentralScheduler) eventSource() eventSource {
+	return eventSource{"DVSCentralScheduler", dcs.address, centralscheduler.CentralSchedulerMetaData}
+}
+
+func (dcs *DVSCentralScheduler) GroupCount(ctx context.Context) (uint8, error) {
+	count, err := dcs.contract.GroupCount(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to call groupCount")
+	}
+	return count, nil
+}
+
+// RegisterChainToPell registers the DVS of the central scheduler to Pell. approverKey signs the
+// registration for the dvs chain approver of the PellRegistryRouter.
+func (dcs *DVSCentralScheduler) RegisterChainToPell(
+	ctx context.Context, chainID *big.Int, stakeManager gethcommon.Address, approverKey *ecdsa.PrivateKey,
+	interactor gethcommon.Address,
+) (*Result, error) {
+	lg := logger.With("comps", "MocksDVS.CentralScheduler")
+
+	var out []any
+	if err := dcs.registration.Call(&bind.CallOpts{Context: ctx}, &out, "ejectionManager"); err != nil {
+		return nil, errors.Wrap(err, "failed to call ejectionManager")
+	}
+	info := dvsInfo{
+		ChainId:          chainID,
+		CentralScheduler: dcs.address,
+		EjectionManager:  *abi.ConvertType(out[0], new(gethcommon.Address)).(*gethcommon.Address),
+		StakeManager:     stakeManager,
+	}
+	lg.Info("Registering chain to pell", "chainID", chainID, "centralScheduler", info.CentralScheduler.Hex(),
+		"ejectionManager", info.EjectionManager.Hex(), "stakeManager", info.StakeManager.Hex())
+
+	signature, err := dcs.approverSignature(ctx, info, approverKey)
+	if err != nil {
+		return nil, err
+	}
+
+	noSendTxOpts, err := dcs.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
+	}
+
+	tx, err := dcs.registration.Transact(noSendTxOpts, "registerChainToPell", signature)
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+
+	return sendMockTx(ctx, lg, dcs.txMgr, tx, dcs.eventSource(),
+		eventSource{"PellRegistryInteractor", interactor, registryinteractor.RegistryInteractorMetaData},
+	)
+}
+
+// approverSignature signs the addition of info to the PellRegistryRouter with approverKey
+func (dcs *DVSCentralScheduler) approverSignature(
+	ctx context.Context, info dvsInfo, approverKey *ecdsa.PrivateKey,
+) (signatureWithSaltAndExpiry, error) {
+	var signature signatureWithSaltAndExpiry
+	if _, err := rand.Read(signature.Salt[:]); err != nil {
+		return signature, errors.Wrap(err, "failed to generate random salt")
+	}
+	sigValidForSeconds := int64(60 * 60) // 1 hour
+	signature.Expiry = big.NewInt(time.Now().Unix() + sigValidForSeconds)
+
+	var out []any
+	err := dcs.registryRouter.Call(&bind.CallOpts{Context: ctx}, &out, "calculateAddSupportedDVSApproverDigestHash",
+		info, crypto.PubkeyToAddress(approverKey.PublicKey), signature.Salt, signature.Expiry)
+	if err != nil {
+		return signature, errors.Wrap(err, "failed to calculate dvs approver digest hash")
+	}
+	digest := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	sig, err := crypto.Sign(digest[:], approverKey)
+	if err != nil {
+		return signature, errors.Wrap(err, "failed to sign dvs approver digest hash")
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	signature.Signature = sig
+	return signature, nil
+}
@@ -0,0 +1,72 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/pelldelegationmanager.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+)
+
+type PellDelegationManager struct {
+	contract  *pelldelegationmanager.PellDelegationManager
+	rpcClient eth.Client
+	txMgr     txmgr.TxManager
+	address   gethcommon.Address
+}
+
+func NewPellDelegationManager(rpcClient eth.Client, txMgr txmgr.TxManager, address string) (*PellDelegationManager, error) {
+	pdm := &PellDelegationManager{
+		rpcClient: rpcClient,
+		txMgr:     txMgr,
+		address:   gethcommon.HexToAddress(address),
+	}
+	var err error
+	pdm.contract, err = pelldelegationmanager.NewPellDelegationManager(pdm.address, rpcClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create pell delegation manager")
+	}
+	return pdm, nil
+}
+
+func (pdm *PellDelegationManager) eventSource() eventSource {
+	return eventSource{"PellDelegationManager", pdm.address, pelldelegationmanager.PellDelegationManagerMetaData}
+}
+
+// RegisterAsOperator registers the sender as an operator without delegation approver
+func (pdm *PellDelegationManager) RegisterAsOperator(ctx context.Context, metadataURI string) (*Result, error) {
+	lg := logger.With("comps", "MocksPell.DelegationManager")
+	lg.Info("Registering as operator", "metadataURI", metadataURI)
+
+	noSendTxOpts, err := pdm.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
+	}
+
+	tx, err := pdm.contract.RegisterAsOperator(noSendTxOpts,
+		pelldelegationmanager.IPellDelegationManagerOperatorDetails{
+			DelegationApprover: gethcommon.Address{},
+			StakerOptOutWindow: 0,
+		},
+		metadataURI,
+	)
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+
+	return sendMockTx(ctx, lg, pdm.txMgr, tx, pdm.eventSource())
+}
+
+func (pdm *PellDelegationManager) IsOperator(ctx context.Context, operator gethcommon.Address) (bool, error) {
+	isOperator, err := pdm.contract.IsOperator(&bind.CallOpts{Context: ctx}, operator)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to call isOperator")
+	}
+	return isOperator, nil
+}
@@ -0,0 +1,168 @@
+package mocks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/registryrouter.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/crypto/bls"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+)
+
+// dvsDirectoryABI is the part of the PellDVSDirectory abi the mocks use
+const dvsDirectoryABI = `[
+	{"type":"function","name":"calculateOperatorDVSRegistrationDigestHash","stateMutability":"view","inputs":[{"name":"operator","type":"address"},{"name":"dvs","type":"address"},{"name":"salt","type":"bytes32"},{"name":"expiry","type":"uint256"}],"outputs":[{"name":"","type":"bytes32"}]}
+]`
+
+type PellRegistryRouter struct {
+	contract  *registryrouter.RegistryRouter
+	rpcClient eth.Client
+	txMgr     txmgr.TxManager
+	address   gethcommon.Address
+}
+
+func NewPellRegistryRouter(rpcClient eth.Client, txMgr txmgr.TxManager, address string) (*PellRegistryRouter, error) {
+	prr := &PellRegistryRouter{
+		rpcClient: rpcClient,
+		txMgr:     txMgr,
+		address:   gethcommon.HexToAddress(address),
+	}
+	var err error
+	prr.contract, err = registryrouter.NewRegistryRouter(prr.address, rpcClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create registry router")
+	}
+	return prr, nil
+}
+
+func (prr *PellRegistryRouter) eventSource() eventSource {
+	return eventSource{"PellRegistryRouter", prr.address, registryrouter.RegistryRouterMetaData}
+}
+
+// RegisterOperator registers the operator owning operatorKey to groups of the DVS behind the router,
+// proving it owns blsKey. The operator signature is over the digest of dvsDirectory.
+func (prr *PellRegistryRouter) RegisterOperator(
+	ctx context.Context, operatorKey *ecdsa.PrivateKey, blsKey *bls.KeyPair, groups []byte, socket string,
+	dvsDirectory gethcommon.Address,
+) (*Result, error) {
+	lg := logger.With("comps", "MocksPell.RegistryRouter")
+	operator := crypto.PubkeyToAddress(operatorKey.PublicKey)
+	lg.Info("Registering operator to dvs", "operator", operator.Hex(), "groups", groups, "socket", socket)
+
+	pubkeyParams, err := prr.pubkeyRegistrationParams(ctx, operator, blsKey)
+	if err != nil {
+		return nil, err
+	}
+	operatorSignature, err := prr.operatorSignature(ctx, operatorKey, dvsDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	noSendTxOpts, err := prr.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
+	}
+
+	tx, err := prr.contract.RegisterOperator(noSendTxOpts, groups, socket, pubkeyParams, operatorSignature)
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+
+	return sendMockTx(ctx, lg, prr.txMgr, tx, prr.eventSource())
+}
+
+// pubkeyRegistrationParams signs the registration message of operator with blsKey
+func (prr *PellRegistryRouter) pubkeyRegistrationParams(
+	ctx context.Context, operator gethcommon.Address, blsKey *bls.KeyPair,
+) (registryrouter.IOperatorKeyManagerPubkeyRegistrationParams, error) {
+	var params registryrouter.IOperatorKeyManagerPubkeyRegistrationParams
+	msgHash, err := prr.contract.PubkeyRegistrationMessageHash(&bind.CallOpts{Context: ctx}, operator)
+	if err != nil {
+		return params, errors.Wrap(err, "failed to get pubkey registration message hash")
+	}
+	signature := blsKey.SignHashedToCurveMessage(bls.NewG1Point(msgHash.X, msgHash.Y).G1Affine)
+
+	sigX, sigY := g1Coordinates(signature.G1Point)
+	g1X, g1Y := g1Coordinates(blsKey.GetPubKeyG1())
+	g2X, g2Y := g2Coordinates(blsKey.GetPubKeyG2())
+	params.PubkeyRegistrationSignature = registryrouter.BN254G1Point{X: sigX, Y: sigY}
+	params.PubkeyG1 = registryrouter.BN254G1Point{X: g1X, Y: g1Y}
+	params.PubkeyG2 = registryrouter.BN254G2Point{X: g2X, Y: g2Y}
+	return params, nil
+}
+
+// operatorSignature signs the registration of the operator to the DVS with operatorKey
+func (prr *PellRegistryRouter) operatorSignature(
+	ctx context.Context, operatorKey *ecdsa.PrivateKey, dvsDirectory gethcommon.Address,
+) (registryrouter.ISignatureUtilsSignatureWithSaltAndExpiry, error) {
+	var signature registryrouter.ISignatureUtilsSignatureWithSaltAndExpiry
+	parsed, err := abi.JSON(strings.NewReader(dvsDirectoryABI))
+	if err != nil {
+		return signature, errors.Wrap(err, "failed to parse dvs directory abi")
+	}
+	directory := bind.NewBoundContract(dvsDirectory, parsed, prr.rpcClient, prr.rpcClient, prr.rpcClient)
+
+	var salt [32]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		return signature, errors.Wrap(err, "failed to generate random salt")
+	}
+	sigValidForSeconds := int64(60 * 60) // 1 hour
+	expiry := big.NewInt(time.Now().Unix() + sigValidForSeconds)
+
+	var out []any
+	err = directory.Call(&bind.CallOpts{Context: ctx}, &out, "calculateOperatorDVSRegistrationDigestHash",
+		crypto.PubkeyToAddress(operatorKey.PublicKey), prr.address, salt, expiry)
+	if err != nil {
+		return signature, errors.Wrap(err, "failed to calculate operator dvs registration digest hash")
+	}
+	digest := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	sig, err := crypto.Sign(digest[:], operatorKey)
+	if err != nil {
+		return signature, errors.Wrap(err, "failed to sign operator dvs registration digest hash")
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+
+	signature.Signature = sig
+	signature.Salt = salt
+	signature.Expiry = expiry
+	return signature, nil
+}
+
+// CreateGroup creates a group of the DVS behind the router
+func (prr *PellRegistryRouter) CreateGroup(ctx context.Context, cfg *GroupConfig) (*Result, error) {
+	lg := logger.With("comps", "MocksPell.RegistryRouter")
+	lg.Info("Creating group", "minimumStake", cfg.MinimumStake, "pools", len(cfg.PoolParams),
+		"operatorSetParams", cfg.OperatorSetParams)
+
+	noSendTxOpts, err := prr.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
+	}
+
+	tx, err := prr.contract.CreateGroup(noSendTxOpts,
+		cfg.registryRouterOperatorSetParam(),
+		cfg.MinimumStake,
+		cfg.registryRouterPoolParams(),
+	)
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+
+	return sendMockTx(ctx, lg, prr.txMgr, tx, prr.eventSource())
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/stakeregistryrouter.sol"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+)
+
+type PellStakeRegistryRouter struct {
+	contract  *stakeregistryrouter.StakeRegistryRouter
+	rpcClient eth.Client
+	txMgr     txmgr.TxManager
+	address   gethcommon.Address
+}
+
+func NewPellStakeRegistryRouter(rpcClient eth.Client, txMgr txmgr.TxManager, address string) (*PellStakeRegistryRouter, error) {
+	psr := &PellStakeRegistryRouter{
+		rpcClient: rpcClient,
+		txMgr:     txMgr,
+		address:   gethcommon.HexToAddress(address),
+	}
+	var err error
+	psr.contract, err = stakeregistryrouter.NewStakeRegistryRouter(psr.address, rpcClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create stake registry router")
+	}
+	return psr, nil
+}
+
+func (psr *PellStakeRegistryRouter) eventSource() eventSource {
+	return eventSource{"PellStakeRegistryRouter", psr.address, stakeregistryrouter.StakeRegistryRouterMetaData}
+}
+
+// AddPools adds the pools of cfg to an existing group
+func (psr *PellStakeRegistryRouter) AddPools(ctx context.Context, groupNumber uint8, cfg *GroupConfig) (*Result, error) {
+	lg := logger.With("comps", "MocksPell.StakeRegistryRouter")
+	lg.Info("Adding pools", "groupNumber", groupNumber, "pools", len(cfg.PoolParams))
+
+	if len(cfg.PoolParams) == 0 {
+		return nil, errors.New("no pool_params to add")
+	}
+
+	noSendTxOpts, err := psr.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
+	}
+
+	tx, err := psr.contract.AddPools(noSendTxOpts, groupNumber, cfg.stakeRegistryRouterPoolParams())
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+
+	return sendMockTx(ctx, lg, psr.txMgr, tx, psr.eventSource())
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/pellstrategymanager.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+)
+
+type PellStrategyManager struct {
+	contract *pellstrategymanager.PellStrategyManager
+	address  gethcommon.Address
+}
+
+func NewPellStrategyManager(rpcClient eth.Client, address string) (*PellStrategyManager, error) {
+	psm := &PellStrategyManager{
+		address: gethcommon.HexToAddress(address),
+	}
+	var err error
+	psm.contract, err = pellstrategymanager.NewPellStrategyManager(psm.address, rpcClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create pell strategy manager")
+	}
+	return psm, nil
+}
+
+// StakerStrategyShares returns the shares of staker in strategy of chainID on the pell side
+func (psm *PellStrategyManager) StakerStrategyShares(
+	ctx context.Context, chainID *big.Int, staker, strategy gethcommon.Address,
+) (*big.Int, error) {
+	shares, err := psm.contract.StakerStrategyShares(&bind.CallOpts{Context: ctx}, chainID, staker, strategy)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call stakerStrategyShares")
+	}
+	return shares, nil
+}
@@ -2,51 +2,44 @@ package mocks
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"crypto/rand"
-	"encoding/hex"
-	"fmt"
 	"math/big"
-	"os"
 	"time"
 
 	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v3/delegationmanager.sol"
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils/exec"
 )
 
 type StakingDelegationManager struct {
-	contract   *delegationmanager.DelegationManager
-	rpcClient  eth.Client
-	txMgr      txmgr.TxManager
-	rpcURL     string
-	address    string
-	privateKey *ecdsa.PrivateKey
+	contract  *delegationmanager.DelegationManager
+	rpcClient eth.Client
+	txMgr     txmgr.TxManager
+	address   gethcommon.Address
 }
 
-func NewStakingDelegationManager(rpcURL string, rpcClient eth.Client, privateKey *ecdsa.PrivateKey, txMgr txmgr.TxManager, address string) (*StakingDelegationManager, error) {
+func NewStakingDelegationManager(rpcClient eth.Client, txMgr txmgr.TxManager, address string) (*StakingDelegationManager, error) {
 	sdm := &StakingDelegationManager{
-
-		rpcClient:  rpcClient,
-		txMgr:      txMgr,
-		rpcURL:     rpcURL,
-		address:    address,
-		privateKey: privateKey,
+		rpcClient: rpcClient,
+		txMgr:     txMgr,
+		address:   gethcommon.HexToAddress(address),
 	}
 	var err error
-	sdm.contract, err = delegationmanager.NewDelegationManager(gethcommon.HexToAddress(address), rpcClient)
+	sdm.contract, err = delegationmanager.NewDelegationManager(sdm.address, rpcClient)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create delegation manager")
 	}
 	return sdm, nil
 }
 
-func (sdm *StakingDelegationManager) DelegateTo(ctx context.Context, operator string) (*gethtypes.Receipt, error) {
+func (sdm *StakingDelegationManager) eventSource() eventSource {
+	return eventSource{"StakingDelegationManager", sdm.address, delegationmanager.DelegationManagerMetaData}
+}
+
+func (sdm *StakingDelegationManager) DelegateTo(ctx context.Context, operator string) (*Result, error) {
 
 	lg := logger.With("comps", "MocksStaking.DelegationManager")
 	lg.Info("Delegating to operator", "operator", operator)
@@ -74,26 +67,6 @@ func (sdm *StakingDelegationManager) DelegateTo(ctx context.Context, operator st
 		"approverSignatureAndExpiry", approverSignatureAndExpiry,
 	)
 
-	funcSignature := "delegateTo(address,(bytes,uint256),bytes32)"
-	args := []string{
-		"cast", "send", // command
-		"--rpc-url", sdm.rpcURL, // rpc url
-		sdm.address,   // contract address
-		funcSignature, // function signature
-		operator,      // operator address
-		"(0x0000000000000000000000000000000000000000000000000000000000000000,0)", // approverSignatureAndExpiry
-		fmt.Sprintf("0x%x", sigSalt),                                  // sigSalt
-		"--private-key", hex.EncodeToString(sdm.privateKey.D.Bytes()), // private key
-	}
-
-	logger.Info("executing command", "args", args)
-
-	err = exec.CommandVerbose(ctx, args...)
-
-	if os.Getenv("USEGO") == "" {
-		return nil, err
-	}
-
 	noSendTxOpts, err := sdm.txMgr.GetNoSendTxOpts()
 	if err != nil {
 		lg.Error("failed to get no send tx opts", "err", err)
@@ -105,19 +78,75 @@ func (sdm *StakingDelegationManager) DelegateTo(ctx context.Context, operator st
 		approverSignatureAndExpiry,
 		sigSalt,
 	)
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+
+	return sendMockTx(ctx, lg, sdm.txMgr, tx, sdm.eventSource())
+}
 
+// Undelegate undelegates staker from its operator, queueing the withdrawal of all its shares
+func (sdm *StakingDelegationManager) Undelegate(ctx context.Context, staker gethcommon.Address) (*Result, error) {
+	lg := logger.With("comps", "MocksStaking.DelegationManager")
+	lg.Info("Undelegating", "staker", staker.Hex())
+
+	noSendTxOpts, err := sdm.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
+	}
+
+	tx, err := sdm.contract.Undelegate(noSendTxOpts, staker)
 	if err != nil {
 		lg.Error("failed to create tx", "err", err)
 		return nil, errors.Wrap(err, "failed to create tx")
 	}
 
-	receipt, err := sdm.txMgr.Send(ctx, tx)
+	return sendMockTx(ctx, lg, sdm.txMgr, tx, sdm.eventSource())
+}
+
+// QueueWithdrawals queues the withdrawal of shares from strategies, shares[i] from strategies[i],
+// to withdrawer
+func (sdm *StakingDelegationManager) QueueWithdrawals(
+	ctx context.Context, strategies []gethcommon.Address, shares []*big.Int, withdrawer gethcommon.Address,
+) (*Result, error) {
+	lg := logger.With("comps", "MocksStaking.DelegationManager")
+	lg.Info("Queueing withdrawal", "strategies", strategies, "shares", shares, "withdrawer", withdrawer.Hex())
+
+	if len(strategies) == 0 || len(strategies) != len(shares) {
+		return nil, errors.Errorf("got %d strategies and %d share amounts", len(strategies), len(shares))
+	}
+
+	noSendTxOpts, err := sdm.txMgr.GetNoSendTxOpts()
 	if err != nil {
-		lg.Error("failed to send tx", "err", err)
-		return nil, errors.Wrap(err, "failed to send tx")
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
 	}
 
-	lg.Info("tx successfully included", "txHash", receipt.TxHash.String())
+	tx, err := sdm.contract.QueueWithdrawals(noSendTxOpts, []delegationmanager.IDelegationManagerQueuedWithdrawalParams{{
+		Strategies: strategies,
+		Shares:     shares,
+		Withdrawer: withdrawer,
+	}})
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+
+	return sendMockTx(ctx, lg, sdm.txMgr, tx, sdm.eventSource())
+}
 
-	return receipt, nil
+// withdrawalRoots returns the roots of the withdrawals queued by a tx
+func withdrawalRoots(result *Result) []string {
+	var roots []string
+	for _, event := range result.Events {
+		if event.Contract != "StakingDelegationManager" || event.Name != "WithdrawalQueued" {
+			continue
+		}
+		if root, ok := event.Args["withdrawalRoot"].([32]byte); ok {
+			roots = append(roots, gethcommon.Hash(root).Hex())
+		}
+	}
+	return roots
 }
@@ -0,0 +1,85 @@
+package mocks
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v2/strategymanager.sol"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+)
+
+type StakingStrategyManager struct {
+	contract  *strategymanager.StrategyManager
+	rpcClient eth.Client
+	txMgr     txmgr.TxManager
+	address   gethcommon.Address
+}
+
+func NewStakingStrategyManager(rpcClient eth.Client, txMgr txmgr.TxManager, address string) (*StakingStrategyManager, error) {
+	ssm := &StakingStrategyManager{
+		rpcClient: rpcClient,
+		txMgr:     txMgr,
+		address:   gethcommon.HexToAddress(address),
+	}
+	var err error
+	ssm.contract, err = strategymanager.NewStrategyManager(ssm.address, rpcClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create strategy manager")
+	}
+	return ssm, nil
+}
+
+func (ssm *StakingStrategyManager) eventSource() eventSource {
+	return eventSource{"StakingStrategyManager", ssm.address, strategymanager.StrategyManagerMetaData}
+}
+
+// DepositIntoStrategy deposits amount of token into strategy, approving the strategy manager first
+// when its allowance is not enough
+func (ssm *StakingStrategyManager) DepositIntoStrategy(
+	ctx context.Context, staker, strategy, token gethcommon.Address, amount *big.Int,
+) (*Result, error) {
+	lg := logger.With("comps", "MocksStaking.StrategyManager")
+	lg.Info("Depositing into strategy", "staker", staker.Hex(), "strategy", strategy.Hex(),
+		"token", token.Hex(), "amount", amount)
+
+	erc20, err := NewERC20(ssm.rpcClient, ssm.txMgr, token.Hex())
+	if err != nil {
+		return nil, err
+	}
+	balance, err := erc20.BalanceOf(ctx, staker)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, errors.Errorf("token balance %s of %s is less than %s", balance, staker.Hex(), amount)
+	}
+	allowance, err := erc20.Allowance(ctx, staker, ssm.address)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(amount) < 0 {
+		if _, err := erc20.Approve(ctx, ssm.address, amount); err != nil {
+			return nil, errors.Wrap(err, "failed to approve the strategy manager")
+		}
+	} else {
+		lg.Info("allowance is enough, skip approve", "allowance", allowance)
+	}
+
+	noSendTxOpts, err := ssm.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		lg.Error("failed to get no send tx opts", "err", err)
+		return nil, errors.Wrap(err, "failed to get no send tx opts")
+	}
+
+	tx, err := ssm.contract.DepositIntoStrategy(noSendTxOpts, strategy, token, amount)
+	if err != nil {
+		lg.Error("failed to create tx", "err", err)
+		return nil, errors.Wrap(err, "failed to create tx")
+	}
+
+	return sendMockTx(ctx, lg, ssm.txMgr, tx, ssm.eventSource(), erc20.eventSource())
+}
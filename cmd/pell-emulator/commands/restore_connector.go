@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var EmulatorRestoreConnectorCmd = &cobra.Command{
+	Use:   "restore-connector",
+	Short: "hand the connector role back to the connectors replaced by update-connector",
+	Long: `restore-connector sets the connector of every contract recorded in connector_file, defaults
+to <home>/data/connectors.json, back to the connector it had before the emulator took it over.
+Contracts whose connector was changed by someone else since are left untouched.
+`,
+	Example: `
+pell-emulator restore-connector \
+	--home <home-dir> \
+	--rpc-url <rpc-url> \
+	--ws-url <ws-url>
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bindings, err := newConnectorBindings(cmd)
+		if err != nil {
+			return err
+		}
+		defer bindings.Close()
+		return bindings.RestoreConnector(cmd.Context())
+	},
+}
@@ -1,17 +1,21 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
 	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/commands/mocks"
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/commands/scenario"
 	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/utils"
 	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/types"
 	"github.com/0xPellNetwork/pell-emulator/libs/cli"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
 )
@@ -70,6 +74,148 @@ func ParseConfig(configFile, chainFile string) (*config.Config, error) {
 	return conf, nil
 }
 
+// reloadConfigFile loads the config like the root command, but fails instead of falling
+// back to the default config. Log settings of the file win over unset log flags. The
+// deployment artifacts are not applied again, see reloadedContractAddress.
+func reloadConfigFile(cmd *cobra.Command, configFile, chainFile string) (*config.Config, error) {
+	conf, err := config.LoadConfigFromFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if chainFile != "" && conf.ContractAddress == nil {
+		conf.ContractAddress, err = config.LoadContractAddressFromFile(chainFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logLevel, logFormat := conf.LogLevel, conf.LogFormat
+	overwriteConfigOnRootCmd(conf)
+	if !cmd.Flags().Changed(chainflags.LogLevelFlag.Name) && logLevel != "" {
+		conf.LogLevel = logLevel
+	}
+	if !cmd.Flags().Changed(chainflags.LogFormatFlag.Name) && logFormat != "" {
+		conf.LogFormat = logFormat
+	}
+	return conf, nil
+}
+
+// reloadedContractAddress returns the contract addresses of a reloaded config. The deployment
+// artifacts are applied once at startup: while the file has the contract_address it had then,
+// the started addresses are kept, once it is changed in the file the file wins.
+func reloadedContractAddress(reloaded, startFile, started *config.ContractAddress) *config.ContractAddress {
+	if !equalContractAddress(reloaded, startFile) || started == nil {
+		return reloaded
+	}
+	address := *started
+	return &address
+}
+
+func equalContractAddress(a, b *config.ContractAddress) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// applyHardhatDeployments overrides the contract addresses of conf with the hardhat deployment
+// outputs given by --pell-deployments and --dvs-deployments
+func applyHardhatDeployments(conf *config.Config) error {
+	pellFile := chainflags.EmulatorFlagPellDeployments.Value
+	dvsFile := chainflags.EmulatorFlagDVSDeployments.Value
+	if pellFile == "" && dvsFile == "" {
+		return nil
+	}
+
+	var pell *types.TypesHardhatDeploymentsContractAddressPell
+	if pellFile != "" {
+		var err error
+		pell, err = types.LoadTypesHardhatDeploymentsContractAddressPellFromFile(pellFile)
+		if err != nil {
+			logger.Error("Failed to load pell deployments", "error", err, "file", pellFile)
+			return fmt.Errorf("failed to load pell deployments %s: %w", pellFile, err)
+		}
+	}
+
+	var dvs *types.TypesHardhatDeploymentsContractAddressDVS
+	if dvsFile != "" {
+		var err error
+		dvs, err = types.LoadTypesHardhatDeploymentsContractAddressDVSFromFile(dvsFile)
+		if err != nil {
+			logger.Error("Failed to load dvs deployments", "error", err, "file", dvsFile)
+			return fmt.Errorf("failed to load dvs deployments %s: %w", dvsFile, err)
+		}
+	}
+
+	if conf.ContractAddress == nil {
+		conf.ContractAddress = &config.ContractAddress{}
+	}
+	missing := types.ApplyHardhatDeployments(conf.ContractAddress, pell, dvs)
+	if len(missing) > 0 {
+		logger.Error("Contracts missing from the hardhat deployments", "contracts", missing,
+			"pellDeployments", pellFile, "dvsDeployments", dvsFile)
+		return fmt.Errorf("contracts missing from the hardhat deployments: %s", strings.Join(missing, ", "))
+	}
+	logger.Info("contract addresses loaded from hardhat deployments",
+		"pellDeployments", pellFile, "dvsDeployments", dvsFile)
+	return nil
+}
+
+// applyFoundryBroadcasts overrides the contract addresses of conf with the contracts deployed
+// in the --foundry-broadcast dir for the chain id of the rpc endpoint
+func applyFoundryBroadcasts(ctx context.Context, conf *config.Config) error {
+	broadcastDir := chainflags.EmulatorFlagFoundryBroadcast.Value
+	if broadcastDir == "" {
+		return nil
+	}
+
+	rpcURL := conf.RPCURL
+	if chainflags.EmulatorFlagRPCURL.Value != "" {
+		rpcURL = chainflags.EmulatorFlagRPCURL.Value
+	}
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain id from %s: %w", rpcURL, err)
+	}
+
+	broadcasts, err := types.LoadFoundryBroadcasts(broadcastDir, chainID.Uint64())
+	if err != nil {
+		logger.Error("Failed to load foundry broadcasts", "error", err, "dir", broadcastDir)
+		return err
+	}
+
+	if conf.ContractAddress == nil {
+		conf.ContractAddress = &config.ContractAddress{}
+	}
+	missing, err := types.ApplyFoundryBroadcasts(conf.ContractAddress, broadcasts)
+	if err != nil {
+		logger.Error("Failed to map foundry broadcasts", "error", err, "dir", broadcastDir)
+		return err
+	}
+	files := make([]string, 0, len(broadcasts))
+	for _, b := range broadcasts {
+		files = append(files, b.File)
+	}
+	if len(missing) > 0 {
+		logger.Info("contracts not found in the foundry broadcasts, keeping the configured addresses",
+			"contracts", missing)
+	}
+	logger.Info("contract addresses loaded from foundry broadcasts", "chainID", chainID, "files", files)
+	return nil
+}
+
+func configFilePath(cmd *cobra.Command) string {
+	if chainflags.EmulatorFlagConfigFile.Value != "" {
+		return chainflags.EmulatorFlagConfigFile.Value
+	}
+	return utils.GetHomeDir(cmd) + "/config/config.json"
+}
+
 func overwriteConfigOnRootCmd(conf *config.Config) {
 	if chainflags.LogLevelFlag.GetValue() != "" {
 		conf.LogLevel = chainflags.LogLevelFlag.GetValue()
@@ -92,6 +238,38 @@ func overwriteConfigOnRootCmd(conf *config.Config) {
 	if chainflags.EmulatorFlagDeployerKeyFile.Value != "" {
 		conf.DeployerKeyFile = chainflags.EmulatorFlagDeployerKeyFile.Value
 	}
+
+	overwriteSignerConfig(conf)
+}
+
+// overwriteSignerConfig sets the fields of the signer section given by flags, the others keep
+// their value of the config file. --signer-type is needed if the file has no signer section.
+func overwriteSignerConfig(conf *config.Config) {
+	signer := conf.Signer
+	if signer == nil {
+		if chainflags.EmulatorFlagSignerType.GetValue() == "" {
+			return
+		}
+		signer = &config.SignerConfig{}
+		conf.Signer = signer
+	}
+
+	for _, f := range []struct {
+		flag  *chainflags.StringFlag
+		field *string
+	}{
+		{chainflags.EmulatorFlagSignerType, &signer.Type},
+		{chainflags.EmulatorFlagSignerPrivateKey, &signer.PrivateKey},
+		{chainflags.EmulatorFlagSignerKeystoreFile, &signer.KeystoreFile},
+		{chainflags.EmulatorFlagSignerPasswordFile, &signer.PasswordFile},
+		{chainflags.EmulatorFlagSignerPasswordEnv, &signer.PasswordEnv},
+		{chainflags.EmulatorFlagSignerWeb3SignerURL, &signer.Web3SignerURL},
+		{chainflags.EmulatorFlagSignerAddress, &signer.Address},
+	} {
+		if v := f.flag.GetValue(); v != "" {
+			*f.field = v
+		}
+	}
 }
 
 func validateFlagsOnRootCmd() error {
@@ -126,10 +304,7 @@ var RootCmd = &cobra.Command{
 			logger = log.NewTracingLogger(logger)
 		}
 
-		configFile := utils.GetHomeDir(cmd) + "/config/config.json"
-		if chainflags.EmulatorFlagConfigFile.Value != "" {
-			configFile = chainflags.EmulatorFlagConfigFile.Value
-		}
+		configFile := configFilePath(cmd)
 		contractConfigFile := chainflags.EmulatorFlagContractAddressFile.Value
 
 		logger.Info("config file", "path", configFile)
@@ -139,6 +314,13 @@ var RootCmd = &cobra.Command{
 			return err
 		}
 
+		if err := applyHardhatDeployments(conf); err != nil {
+			return err
+		}
+		if err := applyFoundryBroadcasts(cmd.Context(), conf); err != nil {
+			return err
+		}
+
 		overwriteConfigOnRootCmd(conf)
 
 		config.SetGlobalConfig(conf)
@@ -155,11 +337,23 @@ func init() {
 	chainflags.EmulatorFlagRPCURL.AddToCmdPersistentFlags(RootCmd)
 	chainflags.EmulatorFlagWSURL.AddToCmdPersistentFlags(RootCmd)
 
+	chainflags.EmulatorFlagSignerType.AddToCmdPersistentFlags(RootCmd)
+	chainflags.EmulatorFlagSignerPrivateKey.AddToCmdPersistentFlags(RootCmd)
+	chainflags.EmulatorFlagSignerKeystoreFile.AddToCmdPersistentFlags(RootCmd)
+	chainflags.EmulatorFlagSignerPasswordFile.AddToCmdPersistentFlags(RootCmd)
+	chainflags.EmulatorFlagSignerPasswordEnv.AddToCmdPersistentFlags(RootCmd)
+	chainflags.EmulatorFlagSignerWeb3SignerURL.AddToCmdPersistentFlags(RootCmd)
+	chainflags.EmulatorFlagSignerAddress.AddToCmdPersistentFlags(RootCmd)
+
 	RootCmd.AddCommand(EmulatorInitCmd)
 	RootCmd.AddCommand(EmulatorStartCmd)
 	RootCmd.AddCommand(EmulatorUpdateConnectorCmd)
+	RootCmd.AddCommand(EmulatorRestoreConnectorCmd)
+	RootCmd.AddCommand(EmulatorDoctorCmd)
+	RootCmd.AddCommand(EmulatorVerifyCmd)
 
 	RootCmd.AddCommand(mocks.EmulatorMocksCmd)
+	RootCmd.AddCommand(scenario.EmulatorScenarioCmd)
 	RootCmd.AddCommand(VersionCmd)
 	RootCmd.AddCommand(cli.NewCompletionCmd(RootCmd, true))
 }
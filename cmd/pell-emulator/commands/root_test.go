@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+	"github.com/0xPellNetwork/pell-emulator/config"
+)
+
+func TestOverwriteSignerConfig(t *testing.T) {
+	var tests = map[string]struct {
+		file  *config.SignerConfig
+		flags map[*chainflags.StringFlag]string
+		want  *config.SignerConfig
+	}{
+		"no flags": {
+			file: &config.SignerConfig{Type: config.SignerTypeKeystore, KeystoreFile: "key.json"},
+			want: &config.SignerConfig{Type: config.SignerTypeKeystore, KeystoreFile: "key.json"},
+		},
+		"private key from flags": {
+			flags: map[*chainflags.StringFlag]string{
+				chainflags.EmulatorFlagSignerType:       config.SignerTypePrivateKey,
+				chainflags.EmulatorFlagSignerPrivateKey: "0x01",
+			},
+			want: &config.SignerConfig{Type: config.SignerTypePrivateKey, PrivateKey: "0x01"},
+		},
+		"flag merged into the file section": {
+			file: &config.SignerConfig{
+				Type:         config.SignerTypeKeystore,
+				KeystoreFile: "key.json",
+				PasswordFile: "password.txt",
+			},
+			flags: map[*chainflags.StringFlag]string{
+				chainflags.EmulatorFlagSignerPasswordEnv: "KEY_PASSWORD",
+			},
+			want: &config.SignerConfig{
+				Type:         config.SignerTypeKeystore,
+				KeystoreFile: "key.json",
+				PasswordFile: "password.txt",
+				PasswordEnv:  "KEY_PASSWORD",
+			},
+		},
+		"no section without a type": {
+			flags: map[*chainflags.StringFlag]string{
+				chainflags.EmulatorFlagSignerPasswordEnv: "KEY_PASSWORD",
+			},
+			want: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for flag, value := range tt.flags {
+				flag.Value = value
+			}
+			t.Cleanup(func() {
+				for flag := range tt.flags {
+					flag.Value = ""
+				}
+			})
+
+			conf := &config.Config{Signer: tt.file}
+			overwriteSignerConfig(conf)
+			assert.Equal(t, tt.want, conf.Signer)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// view is a contract function read by a check, abi is its json abi
+type view struct {
+	method string
+	abi    string
+}
+
+var (
+	viewIsOperator = view{"isOperator",
+		`[{"type":"function","name":"isOperator","stateMutability":"view","inputs":[{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}]}]`}
+	viewDelegatedTo = view{"delegatedTo",
+		`[{"type":"function","name":"delegatedTo","stateMutability":"view","inputs":[{"name":"staker","type":"address"}],"outputs":[{"name":"","type":"address"}]}]`}
+	viewStakingOperatorShares = view{"getOperatorShares",
+		`[{"type":"function","name":"getOperatorShares","stateMutability":"view","inputs":[{"name":"operator","type":"address"},{"name":"strategies","type":"address[]"}],"outputs":[{"name":"","type":"uint256[]"}]}]`}
+	viewOmniOperatorShares = view{"getOperatorShares",
+		`[{"type":"function","name":"getOperatorShares","stateMutability":"view","inputs":[{"name":"operator","type":"address"},{"name":"strategies","type":"tuple[]","components":[{"name":"chainId","type":"uint256"},{"name":"strategy","type":"address"}]}],"outputs":[{"name":"","type":"uint256[]"}]}]`}
+	viewGroupCount = view{"groupCount",
+		`[{"type":"function","name":"groupCount","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}]`}
+	viewOperatorWeight = view{"weightOfOperatorForGroup",
+		`[{"type":"function","name":"weightOfOperatorForGroup","stateMutability":"view","inputs":[{"name":"groupNumber","type":"uint8"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"uint96"}]}]`}
+	viewGetOperator = view{"getOperator",
+		`[{"type":"function","name":"getOperator","stateMutability":"view","inputs":[{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"tuple","components":[{"name":"operatorId","type":"bytes32"},{"name":"status","type":"uint8"}]}]}]`}
+)
+
+// omniStrategy is a strategy of a chain, as the pell and service contracts take it
+type omniStrategy struct {
+	ChainId  *big.Int
+	Strategy gethcommon.Address
+}
+
+// operatorInfo is the operator of the DVSCentralScheduler
+type operatorInfo struct {
+	OperatorId [32]byte
+	Status     uint8
+}
+
+// check reads a value of a contract to compare with Expectation.Equals
+type check struct {
+	// views are the views of the check by the contracts it supports
+	views map[string]view
+	// args are the arguments of the view for exp
+	args func(r *runner, exp Expectation) ([]any, error)
+	// format renders the output of the view like Expectation.Equals
+	format func(out []any) string
+	// describe renders the arguments of exp for the report
+	describe func(exp Expectation) string
+}
+
+var operatorStatuses = []string{"never_registered", "registered", "deregistered"}
+
+var checks = map[string]check{
+	"is-operator": {
+		views: map[string]view{
+			"StakingDelegationManager": viewIsOperator,
+			"PellDelegationManager":    viewIsOperator,
+		},
+		args: func(r *runner, exp Expectation) ([]any, error) {
+			account, err := r.account(exp.Account)
+			return []any{account}, err
+		},
+		format:   formatFirst,
+		describe: func(exp Expectation) string { return "account=" + exp.Account },
+	},
+	"delegated-to": {
+		views: map[string]view{
+			"StakingDelegationManager": viewDelegatedTo,
+		},
+		args: func(r *runner, exp Expectation) ([]any, error) {
+			account, err := r.account(exp.Account)
+			return []any{account}, err
+		},
+		format:   formatFirst,
+		describe: func(exp Expectation) string { return "account=" + exp.Account },
+	},
+	"operator-shares": {
+		views: map[string]view{
+			"StakingDelegationManager":         viewStakingOperatorShares,
+			"PellDelegationManager":            viewOmniOperatorShares,
+			"ServiceOmniOperatorSharesManager": viewOmniOperatorShares,
+		},
+		args: func(r *runner, exp Expectation) ([]any, error) {
+			account, err := r.account(exp.Account)
+			if err != nil {
+				return nil, err
+			}
+			if !gethcommon.IsHexAddress(exp.Strategy) {
+				return nil, errors.Errorf("invalid strategy %q", exp.Strategy)
+			}
+			strategy := gethcommon.HexToAddress(exp.Strategy)
+			if exp.Contract == "StakingDelegationManager" {
+				return []any{account, []gethcommon.Address{strategy}}, nil
+			}
+			chainID := r.env.Bindings.ChainID
+			if exp.ChainID != 0 {
+				chainID = new(big.Int).SetUint64(exp.ChainID)
+			}
+			return []any{account, []omniStrategy{{chainID, strategy}}}, nil
+		},
+		format: func(out []any) string {
+			shares := *abi.ConvertType(out[0], new([]*big.Int)).(*[]*big.Int)
+			if len(shares) != 1 {
+				return fmt.Sprint(shares)
+			}
+			return shares[0].String()
+		},
+		describe: func(exp Expectation) string {
+			desc := fmt.Sprintf("account=%s strategy=%s", exp.Account, exp.Strategy)
+			if exp.ChainID != 0 {
+				desc += fmt.Sprintf(" chain_id=%d", exp.ChainID)
+			}
+			return desc
+		},
+	},
+	"group-count": {
+		views: map[string]view{
+			"PellRegistryRouter":  viewGroupCount,
+			"DVSCentralScheduler": viewGroupCount,
+		},
+		args:     func(r *runner, exp Expectation) ([]any, error) { return nil, nil },
+		format:   formatFirst,
+		describe: func(exp Expectation) string { return "" },
+	},
+	"operator-weight": {
+		views: map[string]view{
+			"PellStakeRegistryRouter": viewOperatorWeight,
+			"DVSOperatorStakeManager": viewOperatorWeight,
+		},
+		args: func(r *runner, exp Expectation) ([]any, error) {
+			account, err := r.account(exp.Account)
+			return []any{exp.Group, account}, err
+		},
+		format: formatFirst,
+		describe: func(exp Expectation) string {
+			return fmt.Sprintf("group=%d account=%s", exp.Group, exp.Account)
+		},
+	},
+	"operator-status": {
+		views: map[string]view{
+			"DVSCentralScheduler": viewGetOperator,
+		},
+		args: func(r *runner, exp Expectation) ([]any, error) {
+			account, err := r.account(exp.Account)
+			return []any{account}, err
+		},
+		format: func(out []any) string {
+			var info operatorInfo
+			infoType := reflect.TypeOf(info)
+			if !reflect.TypeOf(out[0]).ConvertibleTo(infoType) {
+				return fmt.Sprint(out[0])
+			}
+			info = reflect.ValueOf(out[0]).Convert(infoType).Interface().(operatorInfo)
+			if int(info.Status) < len(operatorStatuses) {
+				return operatorStatuses[info.Status]
+			}
+			return fmt.Sprint(info.Status)
+		},
+		describe: func(exp Expectation) string { return "account=" + exp.Account },
+	},
+}
+
+func formatFirst(out []any) string {
+	switch v := out[0].(type) {
+	case gethcommon.Address:
+		return v.Hex()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (exp Expectation) validate() error {
+	c, ok := checks[exp.Check]
+	if !ok {
+		return errors.Errorf("unknown check %q, expected one of %s", exp.Check, strings.Join(checkNames(), ", "))
+	}
+	if _, ok := c.views[exp.Contract]; !ok {
+		var contracts []string
+		for name := range c.views {
+			contracts = append(contracts, name)
+		}
+		sort.Strings(contracts)
+		return errors.Errorf("check %s does not support contract %q, expected one of %s",
+			exp.Check, exp.Contract, strings.Join(contracts, ", "))
+	}
+	return nil
+}
+
+func (exp Expectation) String() string {
+	desc := checks[exp.Check].describe(exp)
+	if desc == "" {
+		return exp.Check + " " + exp.Contract
+	}
+	return exp.Check + " " + exp.Contract + " " + desc
+}
+
+// matches compares an actual value with Equals, addresses regardless of case
+func (exp Expectation) matches(actual string) bool {
+	expected := strings.TrimSpace(exp.Equals)
+	if gethcommon.IsHexAddress(expected) {
+		return strings.EqualFold(expected, actual)
+	}
+	return expected == actual
+}
+
+func checkNames() []string {
+	var names []string
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// read returns the current value of the check of exp
+func (r *runner) read(ctx context.Context, exp Expectation) (string, error) {
+	c := checks[exp.Check]
+	v := c.views[exp.Contract]
+	address, err := r.contractAddress(exp.Contract)
+	if err != nil {
+		return "", err
+	}
+	args, err := c.args(r, exp)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(v.abi))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse abi of %s", v.method)
+	}
+	contract := bind.NewBoundContract(address, parsed, r.env.Bindings.RPCClient, nil, nil)
+	var out []any
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, v.method, args...); err != nil {
+		return "", errors.Wrapf(err, "failed to call %s.%s", exp.Contract, v.method)
+	}
+	return c.format(out), nil
+}
@@ -0,0 +1,51 @@
+package scenario
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/commands/mocks"
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var logger = log.NewLogger(os.Stdout)
+
+func init() {
+	EmulatorScenarioCmd.AddCommand(EmulatorScenarioRunCmd)
+}
+
+var EmulatorScenarioCmd = &cobra.Command{
+	Use:   "scenario",
+	Short: "pell emulator scenarios",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_ = cmd.Help()
+		return nil
+	},
+}
+
+var EmulatorScenarioRunCmd = &cobra.Command{
+	Use:   "run <file.yaml>",
+	Short: "run the steps of a scenario and assert the state of the chains after each of them",
+	Example: `
+	pell-emulator scenario run ./scenarios/delegate.yaml
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg := config.GetGlobalConfig()
+		bindings, err := chains.NewChainBindings(cmd.Context(), cfg, logger)
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+		return Run(cmd.Context(), s, &mocks.Env{Bindings: bindings}, cmd.OutOrStdout())
+	},
+}
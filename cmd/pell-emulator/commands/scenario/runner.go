@@ -0,0 +1,247 @@
+package scenario
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/commands/mocks"
+)
+
+var assertPollInterval = 2 * time.Second
+
+// ErrAssertionFailed is returned by Run when an expectation of a step is not met
+var ErrAssertionFailed = errors.New("assertion failed")
+
+type runner struct {
+	scenario *Scenario
+	env      *mocks.Env
+	keys     map[string]*ecdsa.PrivateKey
+	out      io.Writer
+	// view reads the current value of an expectation, read by default
+	view func(ctx context.Context, exp Expectation) (string, error)
+}
+
+// action sends the mock action of step with its key
+type action func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error)
+
+var actions = map[string]action{
+	"staking-deposit": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		amount, ok := new(big.Int).SetString(step.With.Amount, 10)
+		if !ok || amount.Sign() <= 0 {
+			return nil, errors.Errorf("invalid amount %q", step.With.Amount)
+		}
+		return mocks.StakingDeposit(ctx, r.env, key,
+			gethcommon.HexToAddress(step.With.Token), gethcommon.HexToAddress(step.With.Strategy), amount)
+	},
+	"staking-delegate-to": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		operator, err := r.account(step.With.Operator)
+		if err != nil {
+			return nil, err
+		}
+		return mocks.StakingDelegateTo(ctx, r.env, key, operator)
+	},
+	"staking-undelegate": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		return mocks.StakingUndelegate(ctx, r.env, key)
+	},
+	"staking-queue-withdrawal": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		if len(step.With.Strategies) == 0 || len(step.With.Strategies) != len(step.With.Shares) {
+			return nil, errors.Errorf("got %d strategies and %d share amounts", len(step.With.Strategies), len(step.With.Shares))
+		}
+		strategies := make([]gethcommon.Address, len(step.With.Strategies))
+		shares := make([]*big.Int, len(step.With.Shares))
+		for i := range strategies {
+			strategies[i] = gethcommon.HexToAddress(step.With.Strategies[i])
+			amount, ok := new(big.Int).SetString(step.With.Shares[i], 10)
+			if !ok || amount.Sign() <= 0 {
+				return nil, errors.Errorf("invalid share amount %q", step.With.Shares[i])
+			}
+			shares[i] = amount
+		}
+		return mocks.StakingQueueWithdrawal(ctx, r.env, key, strategies, shares)
+	},
+	"pell-register-operator": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		return mocks.PellRegisterOperator(ctx, r.env, key, step.With.MetadataURI)
+	},
+	"dvs-register-operator": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		blsKey, err := mocks.LoadOrCreateBLSKey(r.scenario.path(step.With.BLSKeyFile), step.With.BLSKeyPassword)
+		if err != nil {
+			return nil, err
+		}
+		groups := step.With.Groups
+		if len(groups) == 0 {
+			groups = []uint8{0}
+		}
+		return mocks.DVSRegisterOperator(ctx, r.env, key, blsKey, groups, step.With.Socket,
+			gethcommon.HexToAddress(step.With.DVSDirectory))
+	},
+	"dvs-register-chain": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		approverKey := key
+		if step.With.Approver != "" {
+			var ok bool
+			if approverKey, ok = r.keys[step.With.Approver]; !ok {
+				return nil, errors.Errorf("unknown approver key %q", step.With.Approver)
+			}
+		}
+		return mocks.DVSRegisterChain(ctx, r.env, key, approverKey)
+	},
+	"dvs-create-group": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		cfg, err := mocks.LoadGroupConfig(r.scenario.path(step.With.GroupConfig))
+		if err != nil {
+			return nil, err
+		}
+		outcome, _, err := mocks.DVSCreateGroup(ctx, r.env, key, cfg)
+		return outcome, err
+	},
+	"dvs-add-pools": func(ctx context.Context, r *runner, step Step, key *ecdsa.PrivateKey) (*mocks.Outcome, error) {
+		cfg, err := mocks.LoadGroupConfig(r.scenario.path(step.With.GroupConfig))
+		if err != nil {
+			return nil, err
+		}
+		return mocks.DVSAddPools(ctx, r.env, key, step.With.Group, cfg)
+	},
+}
+
+// Run runs the steps of s in order against the chain of env, it stops at the first step that fails.
+// The report of each step and a diff of the failed expectations are written to out.
+func Run(ctx context.Context, s *Scenario, env *mocks.Env, out io.Writer) error {
+	r := &runner{
+		scenario: s,
+		env:      &mocks.Env{Bindings: env.Bindings, WaitTimeout: s.WaitTimeout},
+		keys:     make(map[string]*ecdsa.PrivateKey, len(s.Keys)),
+		out:      out,
+	}
+	r.view = r.read
+	for name, file := range s.Keys {
+		key, err := env.Bindings.ReadKeyFile(s.path(file))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read key %s", name)
+		}
+		r.keys[name] = key
+	}
+
+	fmt.Fprintf(out, "scenario %q: %d steps\n", s.Name, len(s.Steps))
+	for i, step := range s.Steps {
+		if err := r.runStep(ctx, i+1, step); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "PASS scenario %q\n", s.Name)
+	return nil
+}
+
+func (r *runner) runStep(ctx context.Context, n int, step Step) error {
+	fmt.Fprintf(r.out, "step %d %q: %s\n", n, step.title(), step.Action)
+	outcome, err := actions[step.Action](ctx, r, step, r.keys[step.Key])
+	if err != nil {
+		fmt.Fprintf(r.out, "FAIL step %d %q: %v\n", n, step.title(), err)
+		return errors.Wrapf(err, "step %d %q", n, step.title())
+	}
+	if outcome != nil {
+		fmt.Fprintf(r.out, "  source tx  %s\n", outcome.Source.Receipt.TxHash.Hex())
+		if outcome.Forward != nil {
+			fmt.Fprintf(r.out, "  forward tx %s\n", outcome.Forward.Receipt.TxHash.Hex())
+		}
+	}
+
+	return r.assert(ctx, n, step)
+}
+
+// assert awaits every expectation of step and writes a diff of the ones not met
+func (r *runner) assert(ctx context.Context, n int, step Step) error {
+	failed := 0
+	for _, exp := range step.Expect {
+		actual, err := r.await(ctx, exp)
+		if err != nil {
+			return errors.Wrapf(err, "step %d %q: %s", n, step.title(), exp)
+		}
+		if exp.matches(actual) {
+			fmt.Fprintf(r.out, "  ok   %s = %s\n", exp, actual)
+			continue
+		}
+		failed++
+		fmt.Fprintf(r.out, "  FAIL %s\n", exp)
+		fmt.Fprintf(r.out, "    - expected: %s\n", exp.Equals)
+		fmt.Fprintf(r.out, "    + actual:   %s\n", actual)
+	}
+	if failed > 0 {
+		return errors.Wrapf(ErrAssertionFailed, "step %d %q: %d of %d expectations failed",
+			n, step.title(), failed, len(step.Expect))
+	}
+	return nil
+}
+
+// await reads the value of exp until it matches or the assert timeout of the scenario is over,
+// and returns the last value read
+func (r *runner) await(ctx context.Context, exp Expectation) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.scenario.AssertTimeout)
+	defer cancel()
+	ticker := time.NewTicker(assertPollInterval)
+	defer ticker.Stop()
+
+	var actual string
+	var lastErr error
+	for {
+		value, err := r.view(ctx, exp)
+		if err == nil {
+			actual, lastErr = value, nil
+			if exp.matches(actual) {
+				return actual, nil
+			}
+		} else {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if actual == "" && lastErr != nil {
+				return "", lastErr
+			}
+			return actual, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// account resolves a key name of the scenario or an address
+func (r *runner) account(value string) (gethcommon.Address, error) {
+	if key, ok := r.keys[value]; ok {
+		return crypto.PubkeyToAddress(key.PublicKey), nil
+	}
+	if gethcommon.IsHexAddress(value) {
+		return gethcommon.HexToAddress(value), nil
+	}
+	return gethcommon.Address{}, errors.Errorf("%q is neither a key of the scenario nor an address", value)
+}
+
+func (r *runner) contractAddress(contract string) (gethcommon.Address, error) {
+	addrs := r.env.Bindings.ContractAddress
+	var address string
+	switch contract {
+	case "PellDelegationManager":
+		address = addrs.PellDelegationManager
+	case "PellRegistryRouter":
+		address = addrs.PellRegistryRouter
+	case "PellStakeRegistryRouter":
+		address = addrs.PellStakeRegistryRouter
+	case "StakingDelegationManager":
+		address = addrs.StakingDelegationManager
+	case "ServiceOmniOperatorSharesManager":
+		address = addrs.ServiceOmniOperatorSharesManager
+	case "DVSCentralScheduler":
+		address = addrs.DVSCentralScheduler
+	case "DVSOperatorStakeManager":
+		address = addrs.DVSOperatorStakeManager
+	}
+	if !gethcommon.IsHexAddress(address) {
+		return gethcommon.Address{}, errors.Errorf("no address configured for %s", contract)
+	}
+	return gethcommon.HexToAddress(address), nil
+}
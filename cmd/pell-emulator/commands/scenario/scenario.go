@@ -0,0 +1,127 @@
+package scenario
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultWaitTimeout   = 2 * time.Minute
+	defaultAssertTimeout = time.Minute
+)
+
+// Scenario is a flow of mock actions, each followed by assertions on the state of the chains
+type Scenario struct {
+	Name string `yaml:"name"`
+	// WaitTimeout bounds the wait for the emulator to forward each action
+	WaitTimeout time.Duration `yaml:"wait_timeout"`
+	// AssertTimeout bounds the retries of an assertion until the state converges
+	AssertTimeout time.Duration `yaml:"assert_timeout"`
+	// Keys are the ecdsa key files of the scenario by name, relative to the scenario file
+	Keys  map[string]string `yaml:"keys"`
+	Steps []Step            `yaml:"steps"`
+
+	dir string
+}
+
+// Step is a mock action sent with a key of the scenario
+type Step struct {
+	Name   string        `yaml:"name"`
+	Action string        `yaml:"action"`
+	Key    string        `yaml:"key"`
+	With   StepArgs      `yaml:"with"`
+	Expect []Expectation `yaml:"expect"`
+}
+
+// StepArgs are the arguments of the actions, named like the flags of the mocks commands.
+// Accounts are key names of the scenario or addresses.
+type StepArgs struct {
+	Token          string   `yaml:"token"`
+	Strategy       string   `yaml:"strategy"`
+	Amount         string   `yaml:"amount"`
+	Operator       string   `yaml:"operator"`
+	Strategies     []string `yaml:"strategies"`
+	Shares         []string `yaml:"shares"`
+	MetadataURI    string   `yaml:"metadata_uri"`
+	BLSKeyFile     string   `yaml:"bls_key_file"`
+	BLSKeyPassword string   `yaml:"bls_key_password"`
+	Groups         []uint8  `yaml:"groups"`
+	Socket         string   `yaml:"socket"`
+	DVSDirectory   string   `yaml:"dvs_directory"`
+	Approver       string   `yaml:"approver"`
+	Group          uint8    `yaml:"group"`
+	GroupConfig    string   `yaml:"group_config"`
+}
+
+// Expectation is an assertion on a view of a contract, see checks for the supported ones
+type Expectation struct {
+	Check    string `yaml:"check"`
+	Contract string `yaml:"contract"`
+	Account  string `yaml:"account"`
+	Strategy string `yaml:"strategy"`
+	ChainID  uint64 `yaml:"chain_id"`
+	Group    uint8  `yaml:"group"`
+	Equals   string `yaml:"equals"`
+}
+
+// Load reads the scenario at path, unknown fields are errors
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read scenario %s", path)
+	}
+	s := &Scenario{
+		WaitTimeout:   defaultWaitTimeout,
+		AssertTimeout: defaultAssertTimeout,
+		dir:           filepath.Dir(path),
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(s); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse scenario %s", path)
+	}
+	if err := s.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid scenario %s", path)
+	}
+	return s, nil
+}
+
+func (s *Scenario) validate() error {
+	if len(s.Steps) == 0 {
+		return errors.New("no steps")
+	}
+	for i, step := range s.Steps {
+		if _, ok := actions[step.Action]; !ok {
+			return errors.Errorf("step %d: unknown action %q", i+1, step.Action)
+		}
+		if _, ok := s.Keys[step.Key]; !ok {
+			return errors.Errorf("step %d: unknown key %q", i+1, step.Key)
+		}
+		for j, exp := range step.Expect {
+			if err := exp.validate(); err != nil {
+				return errors.Wrapf(err, "step %d: expect %d", i+1, j+1)
+			}
+		}
+	}
+	return nil
+}
+
+// path resolves p relative to the scenario file
+func (s *Scenario) path(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(s.dir, p)
+}
+
+func (step Step) title() string {
+	if step.Name == "" {
+		return step.Action
+	}
+	return step.Name
+}
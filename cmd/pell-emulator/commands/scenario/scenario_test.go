@@ -0,0 +1,205 @@
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	s, err := Load("testdata/delegate.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "delegate", s.Name)
+	assert.Equal(t, 30*time.Second, s.WaitTimeout)
+	assert.Equal(t, 10*time.Second, s.AssertTimeout)
+	assert.Equal(t, filepath.Join("testdata", "keys/staker.ecdsa.key.json"), s.path(s.Keys["staker"]))
+
+	require.Len(t, s.Steps, 3)
+	assert.Equal(t, "register the operator", s.Steps[0].title())
+	assert.Equal(t, "https://example.com/operator.json", s.Steps[0].With.MetadataURI)
+	assert.Len(t, s.Steps[0].Expect, 2)
+	assert.Equal(t, "1000", s.Steps[1].With.Amount)
+	assert.Empty(t, s.Steps[1].Expect)
+	assert.Equal(t, "staking-delegate-to", s.Steps[2].title())
+	assert.Equal(t, Expectation{
+		Check:    "operator-shares",
+		Contract: "ServiceOmniOperatorSharesManager",
+		Account:  "operator",
+		Strategy: "0x1000000000000000000000000000000000000002",
+		ChainID:  1337,
+		Equals:   "1000",
+	}, s.Steps[2].Expect[1])
+	assert.Equal(t,
+		"operator-shares ServiceOmniOperatorSharesManager account=operator strategy=0x1000000000000000000000000000000000000002 chain_id=1337",
+		s.Steps[2].Expect[1].String())
+}
+
+func TestLoadDefaults(t *testing.T) {
+	s, err := Load("testdata/defaults.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, defaultWaitTimeout, s.WaitTimeout)
+	assert.Equal(t, defaultAssertTimeout, s.AssertTimeout)
+	assert.Equal(t, "/keys/operator.ecdsa.key.json", s.path(s.Keys["operator"]))
+	assert.Equal(t, "", s.path(""))
+}
+
+func TestLoadInvalid(t *testing.T) {
+	var tests = map[string]struct {
+		file    string
+		wantErr string
+	}{
+		"missing file": {
+			file:    "missing.yaml",
+			wantErr: "failed to read scenario",
+		},
+		"malformed yaml": {
+			file:    "malformed.yaml",
+			wantErr: "failed to parse scenario",
+		},
+		"unknown field": {
+			file:    "unknown-field.yaml",
+			wantErr: "field timeout not found",
+		},
+		"no steps": {
+			file:    "no-steps.yaml",
+			wantErr: "no steps",
+		},
+		"unknown action": {
+			file:    "unknown-action.yaml",
+			wantErr: `step 2: unknown action "staking-redelegate"`,
+		},
+		"unknown key": {
+			file:    "unknown-key.yaml",
+			wantErr: `step 1: unknown key "operator"`,
+		},
+		"unknown check": {
+			file:    "unknown-check.yaml",
+			wantErr: `step 1: expect 2: unknown check "staker-shares"`,
+		},
+		"unsupported contract": {
+			file:    "unsupported-contract.yaml",
+			wantErr: `check delegated-to does not support contract "PellDelegationManager", expected one of StakingDelegationManager`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(filepath.Join("testdata", "invalid", tt.file))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestExpectationMatches(t *testing.T) {
+	var tests = map[string]struct {
+		equals string
+		actual string
+		want   bool
+	}{
+		"same value":            {equals: "1000", actual: "1000", want: true},
+		"other value":           {equals: "1000", actual: "999", want: false},
+		"padded value":          {equals: " true\n", actual: "true", want: true},
+		"checksummed address":   {equals: "0xabcdef0000000000000000000000000000000001", actual: "0xabCDEF0000000000000000000000000000000001", want: true},
+		"other address":         {equals: "0xabcdef0000000000000000000000000000000001", actual: "0xabCDEF0000000000000000000000000000000002", want: false},
+		"case of a non address": {equals: "Registered", actual: "registered", want: false},
+		"empty":                 {equals: "", actual: "0", want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Expectation{Equals: tt.equals}.matches(tt.actual))
+		})
+	}
+}
+
+func TestAssertDiff(t *testing.T) {
+	defer func(interval time.Duration) { assertPollInterval = interval }(assertPollInterval)
+	assertPollInterval = 10 * time.Millisecond
+
+	isOperator := Expectation{Check: "is-operator", Contract: "StakingDelegationManager", Account: "operator", Equals: "true"}
+	delegatedTo := Expectation{Check: "delegated-to", Contract: "StakingDelegationManager", Account: "staker",
+		Equals: "0x0000000000000000000000000000000000000001"}
+	groupCount := Expectation{Check: "group-count", Contract: "DVSCentralScheduler", Equals: "2"}
+
+	var tests = map[string]struct {
+		expect []Expectation
+		// values are the values read for each check, the last one is repeated
+		values  map[string][]string
+		readErr error
+		wantErr error
+		wantOut string
+	}{
+		"all met": {
+			expect: []Expectation{isOperator, groupCount},
+			values: map[string][]string{"is-operator": {"true"}, "group-count": {"2"}},
+			wantOut: "  ok   is-operator StakingDelegationManager account=operator = true\n" +
+				"  ok   group-count DVSCentralScheduler = 2\n",
+		},
+		"met once the state converges": {
+			expect:  []Expectation{groupCount},
+			values:  map[string][]string{"group-count": {"0", "1", "2"}},
+			wantOut: "  ok   group-count DVSCentralScheduler = 2\n",
+		},
+		"not met": {
+			expect: []Expectation{isOperator, delegatedTo, groupCount},
+			values: map[string][]string{
+				"is-operator":  {"true"},
+				"delegated-to": {"0x0000000000000000000000000000000000000000"},
+				"group-count":  {"0", "1"},
+			},
+			wantErr: ErrAssertionFailed,
+			wantOut: "  ok   is-operator StakingDelegationManager account=operator = true\n" +
+				"  FAIL delegated-to StakingDelegationManager account=staker\n" +
+				"    - expected: 0x0000000000000000000000000000000000000001\n" +
+				"    + actual:   0x0000000000000000000000000000000000000000\n" +
+				"  FAIL group-count DVSCentralScheduler\n" +
+				"    - expected: 2\n" +
+				"    + actual:   1\n",
+		},
+		"view fails": {
+			expect:  []Expectation{groupCount},
+			readErr: errors.New("execution reverted"),
+			wantErr: errors.New("execution reverted"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			reads := make(map[string]int)
+			r := &runner{
+				scenario: &Scenario{AssertTimeout: 100 * time.Millisecond},
+				out:      &out,
+				view: func(ctx context.Context, exp Expectation) (string, error) {
+					if tt.readErr != nil {
+						return "", tt.readErr
+					}
+					values := tt.values[exp.Check]
+					n := min(reads[exp.Check], len(values)-1)
+					reads[exp.Check]++
+					return values[n], nil
+				},
+			}
+
+			err := r.assert(context.Background(), 1, Step{Action: "staking-undelegate", Expect: tt.expect})
+			switch {
+			case tt.wantErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tt.wantErr, ErrAssertionFailed):
+				assert.ErrorIs(t, err, ErrAssertionFailed)
+				assert.ErrorContains(t, err, `step 1 "staking-undelegate": 2 of 3 expectations failed`)
+			default:
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+			}
+			assert.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
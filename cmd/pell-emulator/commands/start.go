@@ -10,7 +10,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/utils"
 	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
 	"github.com/0xPellNetwork/pell-emulator/server"
 )
 
@@ -24,6 +26,9 @@ func init() {
 	chainflags.EmulatorFlagWSURL.AddToCmdFlag(EmulatorStartCmd)
 	chainflags.EmulatorFlagAutoUpdateConnector.AddToCmdFlag(EmulatorStartCmd)
 	chainflags.EmulatorFlagDeployerKeyFile.AddToCmdFlag(EmulatorStartCmd)
+	chainflags.EmulatorFlagPellDeployments.AddToCmdFlag(EmulatorStartCmd)
+	chainflags.EmulatorFlagDVSDeployments.AddToCmdFlag(EmulatorStartCmd)
+	chainflags.EmulatorFlagFoundryBroadcast.AddToCmdFlag(EmulatorStartCmd)
 
 	emulatorStartCmdFlagPort.AddToCmdFlag(EmulatorStartCmd)
 }
@@ -37,6 +42,10 @@ pell-emulator start \
 	--rpc-url <rpc-url> \
 	--ws-url <ws-url> \
 	--deployer-key-file <deployer-key-file> \
+	--signer-key-files <extra-key-file-1,extra-key-file-2, optional> \
+	--pell-deployments <pell-hardhat-deployments.json, optional> \
+	--dvs-deployments <dvs-hardhat-deployments.json, optional> \
+	--foundry-broadcast <foundry-broadcast-dir, optional> \
 	--port <port, defaults to 9090> \
 	--auto-update-connector <true/false, currently default false, 1/t/y/yes will be true>
 
@@ -60,21 +69,36 @@ pell-emulator start \
 		)
 
 		cfg := config.GetGlobalConfig()
-		if isValidPort(emulatorStartCmdFlagPort.Value) {
-			cfg.Port = emulatorStartCmdFlagPort.Value
-		}
-		if !isValidPort(cfg.Port) {
-			cfg.Port = config.DefautlHTTPServerPort
-		}
+		applyStartDefaults(cmd, cfg)
 
 		logger.Info("cfg is", "cfg", cfg)
 
+		configFile := configFilePath(cmd)
+		chainFile := chainflags.EmulatorFlagContractAddressFile.Value
+		var startFileAddress *config.ContractAddress
+		if conf, err := reloadConfigFile(cmd, configFile, chainFile); err == nil {
+			startFileAddress = conf.ContractAddress
+		}
+		loadConfig := func() (*config.Config, error) {
+			conf, err := reloadConfigFile(cmd, configFile, chainFile)
+			if err != nil {
+				return nil, err
+			}
+			conf.ContractAddress = reloadedContractAddress(conf.ContractAddress, startFileAddress, cfg.ContractAddress)
+			applyStartDefaults(cmd, conf)
+			return conf, nil
+		}
+		dynamicLogger := log.NewDynamicLogger(logger)
+		logger = dynamicLogger
+
 		rootCtx := cmd.Context()
 		ctx, cancel := context.WithCancel(rootCtx)
 		defer cancel()
 
 		errCh := make(chan error, 1)
-		srv, err := server.NewServer(rootCtx, cfg, logger, cfg.Port)
+		srv, err := server.NewServer(rootCtx, cfg, logger, cfg.Port,
+			server.WithConfigReload(configFile, loadConfig, dynamicLogger),
+		)
 		if err != nil {
 			logger.Error("Failed to create server", "error", err)
 			return err
@@ -110,6 +134,25 @@ pell-emulator start \
 	},
 }
 
+func applyStartDefaults(cmd *cobra.Command, cfg *config.Config) {
+	if isValidPort(emulatorStartCmdFlagPort.Value) {
+		cfg.Port = emulatorStartCmdFlagPort.Value
+	}
+	if !isValidPort(cfg.Port) {
+		cfg.Port = config.DefautlHTTPServerPort
+	}
+	if cfg.JournalFile == "" {
+		cfg.JournalFile = utils.GetHomeDir(cmd) + "/data/journal.jsonl"
+	}
+	applyConnectorFileDefault(cmd, cfg)
+}
+
+func applyConnectorFileDefault(cmd *cobra.Command, cfg *config.Config) {
+	if cfg.ConnectorFile == "" {
+		cfg.ConnectorFile = utils.GetHomeDir(cmd) + "/data/connectors.json"
+	}
+}
+
 func isValidPort(value int) bool {
 	return value > 0 && value <= 65535
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
 	"github.com/0xPellNetwork/pell-emulator/config"
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/events"
 )
 
 func init() {
@@ -46,12 +47,32 @@ pell-emulator update-connector \
 
 		rootCtx := cmd.Context()
 
-		cfg := config.GetGlobalConfig()
-		bindings, err := chains.NewChainBindings(rootCtx, cfg, logger)
+		bindings, err := newConnectorBindings(cmd)
 		if err != nil {
-			logger.Error("failed to create chain bindings", "err", err)
 			return err
 		}
+		defer bindings.Close()
 		return bindings.UpdateConnector(rootCtx)
 	},
 }
+
+// newConnectorBindings returns bindings that manage the connector of the targets of the
+// configured routes, the connector is not updated on creation
+func newConnectorBindings(cmd *cobra.Command) (*chains.ChainBindings, error) {
+	cfg := *config.GetGlobalConfig()
+	cfg.AutoUpdateConnector = false
+	applyConnectorFileDefault(cmd, &cfg)
+
+	connectors, err := events.ConnectorContracts(cfg.Routes)
+	if err != nil {
+		logger.Error("invalid routes", "err", err)
+		return nil, err
+	}
+
+	bindings, err := chains.NewChainBindings(cmd.Context(), &cfg, logger, chains.WithConnectorContracts(connectors))
+	if err != nil {
+		logger.Error("failed to create chain bindings", "err", err)
+		return nil, err
+	}
+	return bindings, nil
+}
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/chainflags"
+	"github.com/0xPellNetwork/pell-emulator/cmd/pell-emulator/utils"
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/consistency"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+)
+
+var emulatorVerifyCmdFlagFromBlock = &chainflags.IntFlag{
+	Name:    "from-block",
+	Usage:   "first block scanned for stakers, operators and strategies, overrides verify.from_block",
+	Default: -1,
+}
+
+var emulatorVerifyCmdFlagJSON = &chainflags.StringFlag{
+	Name:  "json",
+	Usage: "print the report as json, 1/t/y/yes will be true",
+}
+
+var emulatorVerifyCmdFlagReconcile = &chainflags.StringFlag{
+	Name:  "reconcile",
+	Usage: "send the corrective sync calls for the mismatches, 1/t/y/yes will be true",
+}
+
+var emulatorVerifyCmdFlagDryRun = &chainflags.StringFlag{
+	Name:  "dry-run",
+	Usage: "with reconcile, print the corrective sync calls without sending them, 1/t/y/yes will be true",
+}
+
+func init() {
+	emulatorVerifyCmdFlagFromBlock.AddToCmdFlag(EmulatorVerifyCmd)
+	emulatorVerifyCmdFlagJSON.AddToCmdFlag(EmulatorVerifyCmd)
+	emulatorVerifyCmdFlagReconcile.AddToCmdFlag(EmulatorVerifyCmd)
+	emulatorVerifyCmdFlagDryRun.AddToCmdFlag(EmulatorVerifyCmd)
+}
+
+// verifyOutput is the json output of verify
+type verifyOutput struct {
+	*consistency.Report
+	Corrections []*consistency.Correction `json:"corrections,omitempty"`
+}
+
+var EmulatorVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "check the state mirrored by the emulator against its source chain",
+	Long: `verify reads the staker shares and delegations of the staking contracts, their mirror on
+PellStrategyManager and PellDelegationManager, and the operator shares of the staking, pell and
+service contracts. Every mismatch is reported with the route whose forward was most likely lost
+or duplicated. Stakers, operators and strategies are discovered from the staking logs.
+
+With --reconcile, the corrective sync calls are sent through the tx manager of the emulator
+within the rate limits of verify.reconcile, and journaled as reconciliations. Add --dry-run to
+only print them.
+`,
+	Example: `
+pell-emulator verify \
+	--home <home-dir> \
+	--rpc-url http://localhost:8545 \
+	--ws-url ws://localhost:8545 \
+	--from-block <block, optional>
+
+pell-emulator verify \
+	--home <home-dir> \
+	--reconcile true \
+	--dry-run true
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetGlobalConfig()
+		verifyCfg := config.DefaultVerifyConfig()
+		if cfg.Verify != nil {
+			*verifyCfg = *cfg.Verify
+		}
+		if emulatorVerifyCmdFlagFromBlock.Value >= 0 {
+			verifyCfg.FromBlock = uint64(emulatorVerifyCmdFlagFromBlock.Value)
+		}
+
+		bindings, err := chains.NewChainBindings(cmd.Context(), cfg, logger)
+		if err != nil {
+			return err
+		}
+		defer bindings.Close()
+
+		report, err := consistency.NewChecker(bindings, verifyCfg, logger).Check(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var corrections []*consistency.Correction
+		if emulatorVerifyCmdFlagReconcile.GetBool() && len(report.Mismatches) > 0 {
+			reconciler, closeJournal, err := newVerifyReconciler(cmd, cfg, verifyCfg)
+			if err != nil {
+				return err
+			}
+			defer closeJournal()
+			corrections = reconciler.Reconcile(cmd.Context(), bindings, report)
+		}
+
+		if emulatorVerifyCmdFlagJSON.GetBool() {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(verifyOutput{Report: report, Corrections: corrections}); err != nil {
+				return err
+			}
+		} else if err := printVerifyReport(report, corrections); err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+		if len(report.Mismatches) > 0 {
+			return errors.Errorf("%d of %d values do not match", len(report.Mismatches), report.Checked)
+		}
+		return nil
+	},
+}
+
+// newVerifyReconciler returns the reconciler of verify, its corrections are journaled
+// to the journal of the emulator
+func newVerifyReconciler(
+	cmd *cobra.Command, cfg *config.Config, verifyCfg *config.VerifyConfig,
+) (*consistency.Reconciler, func(), error) {
+	reconcileCfg := config.DefaultReconcileConfig()
+	if verifyCfg.Reconcile != nil {
+		*reconcileCfg = *verifyCfg.Reconcile
+	}
+	if emulatorVerifyCmdFlagDryRun.GetValue() != "" {
+		reconcileCfg.DryRun = emulatorVerifyCmdFlagDryRun.GetBool()
+	}
+
+	journalFile := cfg.JournalFile
+	if journalFile == "" {
+		journalFile = utils.GetHomeDir(cmd) + "/data/journal.jsonl"
+	}
+	jn, err := journal.NewFileJournal(journalFile)
+	if err != nil {
+		logger.Error("Failed to open journal", "error", err, "file", journalFile)
+		return nil, nil, errors.Wrap(err, "failed to open journal")
+	}
+	return consistency.NewReconciler(reconcileCfg, jn, logger), func() { _ = jn.Close() }, nil
+}
+
+func printVerifyReport(report *consistency.Report, corrections []*consistency.Correction) error {
+	fmt.Printf("blocks %d-%d: %d stakers, %d operators, %d strategies, %d values checked\n\n",
+		report.FromBlock, report.ToBlock, report.Stakers, report.Operators, report.Strategies, report.Checked)
+
+	if len(report.Mismatches) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tSTAKER\tOPERATOR\tSTRATEGY\tSOURCE\tEXPECTED\tMIRROR\tACTUAL\tLIKELY CAUSE")
+		for _, m := range report.Mismatches {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s %s forward\n",
+				m.Kind, orDash(m.Staker), orDash(m.Operator), orDash(m.Strategy),
+				m.Source, m.Expected, m.Mirror, m.Actual, m.Cause, m.Route)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	for _, unreadable := range report.Unreadable {
+		fmt.Printf("[WARN] not compared: %s\n", unreadable)
+	}
+	if len(report.Mismatches) == 0 {
+		fmt.Printf("all %d values match\n", report.Checked)
+	}
+
+	if len(corrections) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tTARGET\tARGS\tTX / REASON")
+		for _, c := range corrections {
+			detail := c.Reason
+			if c.TxHash != "" {
+				detail = c.TxHash
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Status, orDash(c.Target), orDash(c.Args), orDash(detail))
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
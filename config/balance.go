@@ -0,0 +1,32 @@
+package config
+
+const (
+	SetBalanceMethodAnvil   = "anvil_setBalance"
+	SetBalanceMethodHardhat = "hardhat_setBalance"
+)
+
+// BalanceMonitorConfig controls the balance checks of the signing addresses
+type BalanceMonitorConfig struct {
+	// IntervalSeconds between two checks
+	IntervalSeconds int `json:"interval_seconds"`
+	// WarnBalance in wei, a warning is logged when a signer drops below it
+	WarnBalance string `json:"warn_balance"`
+
+	// TopUp refills signers below WarnBalance up to TopUpBalance, only on dev chains.
+	// Signers are funded by FunderKeyFile if set, else with the SetBalanceMethod rpc of anvil/hardhat.
+	TopUp            bool   `json:"top_up"`
+	TopUpBalance     string `json:"top_up_balance"`
+	FunderKeyFile    string `json:"funder_key_file"`
+	SetBalanceMethod string `json:"set_balance_method"`
+}
+
+func DefaultBalanceMonitorConfig() *BalanceMonitorConfig {
+	return &BalanceMonitorConfig{
+		IntervalSeconds:  30,
+		WarnBalance:      "100000000000000000", // 0.1 ether
+		TopUp:            false,
+		TopUpBalance:     "10000000000000000000", // 10 ether
+		FunderKeyFile:    "",
+		SetBalanceMethod: SetBalanceMethodAnvil,
+	}
+}
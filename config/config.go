@@ -13,6 +13,18 @@ type Config struct {
 	ContractAddress     *ContractAddress `json:"contract_address"`
 	AutoUpdateConnector bool             `json:"auto_update_connector"`
 	DeployerKeyFile     string           `json:"deployer_key_file"`
+	Signer              *SignerConfig    `json:"signer,omitempty"`
+	JournalFile         string           `json:"journal_file"`
+	// FallbackEndpoints are tried in order when the node at RPCURL/WSURL is unreachable
+	FallbackEndpoints []Endpoint `json:"fallback_endpoints,omitempty"`
+	// Routes are the names of the routes to run, all routes when empty
+	Routes []string `json:"routes,omitempty"`
+	// ConnectorFile records the connectors replaced by the emulator, see restore-connector
+	ConnectorFile string `json:"connector_file"`
+
+	BalanceMonitor *BalanceMonitorConfig `json:"balance_monitor"`
+	Tracing        *TracingConfig        `json:"tracing,omitempty"`
+	Verify         *VerifyConfig         `json:"verify,omitempty"`
 
 	LogLevel  string `json:"log_level"`
 	LogFormat string `json:"log_format"`
@@ -28,6 +40,7 @@ func DefaultConfig() *Config {
 		LogLevel:            "debug",
 		LogFormat:           "plain",
 		DeployerKeyFile:     "",
+		BalanceMonitor:      DefaultBalanceMonitorConfig(),
 	}
 }
 
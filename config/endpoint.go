@@ -0,0 +1,13 @@
+package config
+
+// Endpoint is the rpc and websocket url of the same node
+type Endpoint struct {
+	RPCURL string `json:"rpc_url"`
+	WSURL  string `json:"ws_url"`
+}
+
+// Endpoints returns the primary endpoint followed by the fallback endpoints
+func (c *Config) Endpoints() []Endpoint {
+	endpoints := []Endpoint{{RPCURL: c.RPCURL, WSURL: c.WSURL}}
+	return append(endpoints, c.FallbackEndpoints...)
+}
@@ -1,11 +1,18 @@
 package config
 
-var globalConfig = DefaultConfig()
+import "sync/atomic"
+
+// globalConfig is replaced when the config is reloaded while it is read by running services
+var globalConfig atomic.Pointer[Config]
+
+func init() {
+	globalConfig.Store(DefaultConfig())
+}
 
 func SetGlobalConfig(cfg *Config) {
-	globalConfig = cfg
+	globalConfig.Store(cfg)
 }
 
 func GetGlobalConfig() *Config {
-	return globalConfig
+	return globalConfig.Load()
 }
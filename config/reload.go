@@ -0,0 +1,71 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// liveKeys are the config keys that can be changed without restarting the emulator
+var liveKeys = map[string]bool{
+	"contract_address":      true,
+	"rpc_url":               true,
+	"ws_url":                true,
+	"fallback_endpoints":    true,
+	"auto_update_connector": true,
+	"log_level":             true,
+	"log_format":            true,
+}
+
+// ChangedKeys returns the json keys whose value differs between c and next
+func (c *Config) ChangedKeys(next *Config) ([]string, error) {
+	current, err := configKeys(c)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := configKeys(next)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for key := range mergeKeys(current, updated) {
+		if !bytes.Equal(current[key], updated[key]) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// RestartRequiredChanges returns the changed keys that can only be applied by restarting the emulator
+func RestartRequiredChanges(changed []string) []string {
+	var res []string
+	for _, key := range changed {
+		if !liveKeys[key] {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+func configKeys(c *Config) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var keys map[string]json.RawMessage
+	err = json.Unmarshal(data, &keys)
+	return keys, err
+}
+
+func mergeKeys(a, b map[string]json.RawMessage) map[string]bool {
+	keys := make(map[string]bool, len(a))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	return keys
+}
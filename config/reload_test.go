@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedKeys(t *testing.T) {
+	var tests = map[string]struct {
+		change func(c *Config)
+		want   []string
+	}{
+		"nothing changed": {
+			change: func(c *Config) {},
+		},
+		"top level key": {
+			change: func(c *Config) { c.LogLevel = "info" },
+			want:   []string{"log_level"},
+		},
+		"nested field": {
+			change: func(c *Config) {
+				c.ContractAddress.StakingDelegationManager = "0x0000000000000000000000000000000000000001"
+			},
+			want: []string{"contract_address"},
+		},
+		"section set": {
+			change: func(c *Config) { c.Signer = &SignerConfig{Type: SignerTypeKeystore} },
+			want:   []string{"signer"},
+		},
+		"omitted empty value": {
+			change: func(c *Config) { c.Routes = []string{} },
+		},
+		"several keys sorted": {
+			change: func(c *Config) {
+				c.WSURL = "ws://localhost:8546"
+				c.Port = 9091
+				c.FallbackEndpoints = []Endpoint{{RPCURL: "http://localhost:8547", WSURL: "ws://localhost:8547"}}
+			},
+			want: []string{"fallback_endpoints", "port", "ws_url"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			next := DefaultConfig()
+			tt.change(next)
+			changed, err := DefaultConfig().ChangedKeys(next)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, changed)
+		})
+	}
+}
+
+func TestRestartRequiredChanges(t *testing.T) {
+	var tests = map[string]struct {
+		changed []string
+		want    []string
+	}{
+		"none": {},
+		"live keys": {
+			changed: []string{"auto_update_connector", "contract_address", "fallback_endpoints", "log_format",
+				"log_level", "rpc_url", "ws_url"},
+		},
+		"restart only keys": {
+			changed: []string{"balance_monitor", "journal_file", "port", "routes", "signer", "tracing"},
+			want:    []string{"balance_monitor", "journal_file", "port", "routes", "signer", "tracing"},
+		},
+		"mixed": {
+			changed: []string{"contract_address", "deployer_key_file", "log_level"},
+			want:    []string{"deployer_key_file"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RestartRequiredChanges(tt.changed))
+		})
+	}
+}
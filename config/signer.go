@@ -0,0 +1,36 @@
+package config
+
+const (
+	SignerTypePrivateKey = "private_key"
+	SignerTypeKeystore   = "keystore"
+	SignerTypeWeb3Signer = "web3signer"
+)
+
+// SignerConfig selects the backend used to sign forwards, it takes precedence over DeployerKeyFile
+type SignerConfig struct {
+	// Type is one of private_key, keystore or web3signer
+	Type string `json:"type"`
+
+	// private_key, hex encoded
+	PrivateKey string `json:"private_key,omitempty"`
+
+	// keystore, the password is read from PasswordFile or from the env var named by PasswordEnv
+	KeystoreFile string `json:"keystore_file,omitempty"`
+	PasswordFile string `json:"password_file,omitempty"`
+	PasswordEnv  string `json:"password_env,omitempty"`
+
+	// web3signer
+	Web3SignerURL string `json:"web3signer_url,omitempty"`
+	Address       string `json:"address,omitempty"`
+}
+
+// KeyFileSigner returns the keystore signer of keyFile. Key files are decrypted with the password
+// of the signer section (password_file or password_env), an empty password if it is not set.
+func (c *Config) KeyFileSigner(keyFile string) *SignerConfig {
+	sc := &SignerConfig{Type: SignerTypeKeystore, KeystoreFile: keyFile}
+	if c.Signer != nil {
+		sc.PasswordFile = c.Signer.PasswordFile
+		sc.PasswordEnv = c.Signer.PasswordEnv
+	}
+	return sc
+}
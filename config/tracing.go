@@ -0,0 +1,13 @@
+package config
+
+// TracingConfig controls the traces of the forwards, tracing is off when both are empty
+type TracingConfig struct {
+	// OTLPEndpoint of an OTLP/HTTP collector, e.g. http://localhost:4318
+	OTLPEndpoint string `json:"otlp_endpoint"`
+	// File spans are appended to as json lines, works without a collector
+	File string `json:"file"`
+}
+
+func (c *TracingConfig) Enabled() bool {
+	return c != nil && (c.OTLPEndpoint != "" || c.File != "")
+}
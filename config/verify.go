@@ -0,0 +1,44 @@
+package config
+
+// VerifyConfig controls the consistency checks of the state mirrored by the emulator, see the verify command
+type VerifyConfig struct {
+	// IntervalSeconds between two checks of the emulator, the periodic check is off when 0
+	IntervalSeconds int `json:"interval_seconds"`
+	// FromBlock is the first block scanned for the stakers, operators and strategies to check
+	FromBlock uint64 `json:"from_block"`
+	// BlockRange is the number of blocks of a single log query
+	BlockRange uint64 `json:"block_range"`
+	// Reconcile sends the corrective sync calls for the mismatches of the periodic check
+	Reconcile *ReconcileConfig `json:"reconcile,omitempty"`
+}
+
+// ReconcileConfig controls the corrective sync calls of the reconciler, it is off unless Enabled
+type ReconcileConfig struct {
+	Enabled bool `json:"enabled"`
+	// DryRun logs the corrective calls without sending them
+	DryRun bool `json:"dry_run"`
+	// MaxCallsPerRun bounds the corrective calls sent for a single check
+	MaxCallsPerRun int `json:"max_calls_per_run"`
+	// MaxCallsPerHour bounds the corrective calls sent over any hour
+	MaxCallsPerHour int `json:"max_calls_per_hour"`
+	// MinCallIntervalSeconds is the minimum time between two corrective calls
+	MinCallIntervalSeconds int `json:"min_call_interval_seconds"`
+}
+
+func DefaultVerifyConfig() *VerifyConfig {
+	return &VerifyConfig{
+		IntervalSeconds: 0,
+		FromBlock:       0,
+		BlockRange:      5000,
+	}
+}
+
+func DefaultReconcileConfig() *ReconcileConfig {
+	return &ReconcileConfig{
+		Enabled:                false,
+		DryRun:                 false,
+		MaxCallsPerRun:         10,
+		MaxCallsPerHour:        60,
+		MinCallIntervalSeconds: 5,
+	}
+}
@@ -0,0 +1,101 @@
+//go:build simulated
+
+package emulator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/events/routetest"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/simchain"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+// The contracts are emitters (see simchain.EmitterCode): the test raises the source events of
+// routetest.Cases and checks the forwards landed on the chain, the calldata of each route is
+// checked by the route tests of the events package. It covers the relaying of every route,
+// not the state of the Pell contracts.
+var e2eAddress = &config.ContractAddress{
+	PellDelegationManager:            "0x00000000000000000000000000000000000a0001",
+	PellRegistryRouter:               "0x00000000000000000000000000000000000a0002",
+	PellRegistryInteractor:           "0x00000000000000000000000000000000000a0003",
+	PellStrategyManager:              "0x00000000000000000000000000000000000a0004",
+	PellStakeRegistryRouter:          "0x00000000000000000000000000000000000a0005",
+	StakingStrategyManager:           "0x00000000000000000000000000000000000a0006",
+	StakingDelegationManager:         "0x00000000000000000000000000000000000a0007",
+	ServiceOmniOperatorSharesManager: "0x00000000000000000000000000000000000a0008",
+	DVSCentralScheduler:              "0x00000000000000000000000000000000000a0009",
+	DVSOperatorStakeManager:          "0x00000000000000000000000000000000000a000a",
+}
+
+func TestEmulatorE2E(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var chainOpts []simchain.Option
+	for _, address := range routetest.Contracts(e2eAddress) {
+		chainOpts = append(chainOpts, simchain.WithCode(address, simchain.EmitterCode))
+	}
+	chain, err := simchain.New(ctx, chainOpts...)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, chain.Close()) }()
+
+	txMgr, err := chain.TxManager(simchain.DevKeys[0], log.NewNopLogger())
+	require.NoError(t, err)
+	sourceTxMgr, err := chain.TxManager(simchain.DevKeys[1], log.NewNopLogger())
+	require.NoError(t, err)
+
+	forwards := make(chan Forward, 16)
+	emulator, err := NewFromOptions(
+		Options{ContractAddress: e2eAddress},
+		WithClients(chain.Client, chain.Client),
+		WithTxManager(txMgr),
+		WithForwardHook(func(f Forward) { forwards <- f }),
+	)
+	require.NoError(t, err)
+	require.NoError(t, emulator.Start(ctx))
+	defer emulator.Stop()
+
+	sender := crypto.PubkeyToAddress(simchain.DevKeys[0].PublicKey)
+	for _, tc := range routetest.Cases(t, e2eAddress) {
+		t.Run(tc.Route, func(t *testing.T) {
+			logs, _ := tc.Logs(t)
+			source, err := chain.Call(ctx, sourceTxMgr, gethcommon.HexToAddress(tc.Source), simchain.EmitLogsCalldata(logs...))
+			require.NoError(t, err)
+
+			var forward Forward
+			select {
+			case forward = <-forwards:
+			case <-ctx.Done():
+				t.Fatal("no forward")
+			}
+			assert.Equal(t, tc.Route, forward.Route)
+			require.Equal(t, StatusForwarded, forward.Status, forward.Reason)
+
+			// the forward was mined after the source tx, sent by the emulator to the target
+			hash := gethcommon.HexToHash(forward.TargetTxHash)
+			receipt, err := chain.Client.TransactionReceipt(ctx, hash)
+			require.NoError(t, err)
+			assert.Equal(t, gethtypes.ReceiptStatusSuccessful, receipt.Status)
+			assert.Greater(t, receipt.BlockNumber.Uint64(), source.BlockNumber.Uint64())
+
+			tx, _, err := chain.Client.TransactionByHash(ctx, hash)
+			require.NoError(t, err)
+			from, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(chain.ChainID), tx)
+			require.NoError(t, err)
+			assert.Equal(t, sender, from)
+			require.NotNil(t, tx.To())
+			assert.Equal(t, gethcommon.HexToAddress(tc.Target), *tx.To())
+			require.GreaterOrEqual(t, len(tx.Data()), 4)
+			assert.Equal(t, tc.TargetABI.Methods[tc.Method].ID, tx.Data()[:4])
+		})
+	}
+}
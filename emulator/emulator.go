@@ -0,0 +1,247 @@
+// Package emulator runs the relaying of the pell-emulator binary in process, so integration
+// tests can start an emulator next to a dev chain without a subprocess.
+package emulator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/events"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+// drainTimeout bounds the wait for the forwards in flight on Stop
+var drainTimeout = 30 * time.Second
+
+// Emulator forwards the events of the pell, staking and dvs contracts of a single chain.
+// It is started once, Stop stops it for good.
+type Emulator struct {
+	cfg       *config.Config
+	logger    log.Logger
+	rpcClient eth.Client
+	wsClient  eth.Client
+	txMgr     txmgr.TxManager
+	hooks     []ForwardHook
+
+	mu       sync.Mutex
+	started  bool
+	stopped  bool
+	cancel   context.CancelFunc
+	bindings *chains.ChainBindings
+	routes   []events.IEvents
+	journal  *hookJournal
+	ready    chan struct{}
+}
+
+// New returns an emulator for cfg, the chain is not reached before Start
+func New(cfg *config.Config, opts ...Option) (*Emulator, error) {
+	if cfg == nil {
+		return nil, errors.New("config is nil")
+	}
+	if cfg.ContractAddress == nil {
+		return nil, errors.New("contract address is not set")
+	}
+	if _, err := events.ConnectorContracts(cfg.Routes); err != nil {
+		return nil, err
+	}
+
+	e := &Emulator{
+		cfg:    cfg,
+		logger: log.NewNopLogger(),
+		ready:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if (e.rpcClient == nil) != (e.wsClient == nil) {
+		return nil, errors.New("both the rpc and the ws client must be set")
+	}
+	return e, nil
+}
+
+// NewFromOptions returns an emulator for the config of o
+func NewFromOptions(o Options, opts ...Option) (*Emulator, error) {
+	return New(o.Config(), opts...)
+}
+
+// Start connects to the chain, updates the connector if enabled and subscribes every route.
+// It returns once the routes are subscribed, they run until ctx is done or Stop is called.
+func (e *Emulator) Start(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.started {
+		return errors.New("emulator already started")
+	}
+	e.started = true
+
+	connectors, err := events.ConnectorContracts(e.cfg.Routes)
+	if err != nil {
+		return err
+	}
+	opts := []chains.Option{chains.WithConnectorContracts(connectors)}
+	if e.rpcClient != nil {
+		opts = append(opts, chains.WithClients(e.rpcClient, e.wsClient))
+	}
+	if e.txMgr != nil {
+		opts = append(opts, chains.WithTxManager(e.txMgr))
+	}
+	bindings, err := chains.NewChainBindings(ctx, e.cfg, e.logger, opts...)
+	if err != nil {
+		return errors.Wrap(err, "failed to create chain bindings")
+	}
+
+	var jn journal.Journal = journal.NewNopJournal()
+	var fileJournal *journal.FileJournal
+	if e.cfg.JournalFile != "" {
+		fileJournal, err = journal.NewFileJournal(e.cfg.JournalFile)
+		if err != nil {
+			bindings.Close()
+			return errors.Wrap(err, "failed to open journal")
+		}
+		jn = fileJournal
+	}
+	e.journal = &hookJournal{next: jn, file: fileJournal, hooks: e.hooks}
+
+	routes, err := events.FilterRoutes(events.GetAllEvents(
+		bindings.ChainID,
+		bindings.RPCClient,
+		bindings.RPCBindings,
+		bindings.WsClient,
+		bindings.WsBindings,
+		bindings.TxMgr,
+		e.logger,
+		events.WithJournal(e.journal),
+	), e.cfg.Routes)
+	if err != nil {
+		e.closeLocked(bindings)
+		return err
+	}
+
+	routesCtx, cancel := context.WithCancel(ctx)
+	for _, route := range routes {
+		if err := route.Init(routesCtx); err != nil {
+			cancel()
+			e.closeLocked(bindings)
+			return errors.Wrapf(err, "failed to subscribe route %s", events.RouteName(route))
+		}
+	}
+	for _, route := range routes {
+		if err := route.Listen(routesCtx); err != nil {
+			cancel()
+			e.drain(routes)
+			e.closeLocked(bindings)
+			return errors.Wrapf(err, "failed to start route %s", events.RouteName(route))
+		}
+	}
+
+	e.bindings, e.routes, e.cancel = bindings, routes, cancel
+	close(e.ready)
+	e.logger.Info("emulator started", "routes", len(routes), "chainID", bindings.ChainID)
+	return nil
+}
+
+// Stop stops the routes, waits for the forwards in flight and closes the journal and the
+// clients dialed by the emulator. It is safe to call more than once.
+func (e *Emulator) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped || e.cancel == nil {
+		e.stopped = true
+		return
+	}
+	e.stopped = true
+
+	e.cancel()
+	e.drain(e.routes)
+	e.closeLocked(e.bindings)
+	e.logger.Info("emulator stopped")
+}
+
+// Ready is closed once Start subscribed every route
+func (e *Emulator) Ready() <-chan struct{} {
+	return e.ready
+}
+
+// WaitReady blocks until the emulator is started or ctx is done
+func (e *Emulator) WaitReady(ctx context.Context) error {
+	select {
+	case <-e.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Routes returns the status of every route, the lag is computed against the current head
+func (e *Emulator) Routes(ctx context.Context) ([]RouteStatus, error) {
+	e.mu.Lock()
+	bindings, routes := e.bindings, e.routes
+	e.mu.Unlock()
+	if bindings == nil {
+		return nil, errors.New("emulator not started")
+	}
+
+	head, err := bindings.RPCClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get block number")
+	}
+	statuses := make([]RouteStatus, 0, len(routes))
+	for _, route := range routes {
+		statuses = append(statuses, route.Status(head))
+	}
+	return statuses, nil
+}
+
+func (e *Emulator) drain(routes []events.IEvents) {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	for _, route := range routes {
+		if err := route.Drain(ctx); err != nil {
+			e.logger.Error("forward in flight canceled", "route", events.RouteName(route), "error", err)
+		}
+	}
+}
+
+func (e *Emulator) closeLocked(bindings *chains.ChainBindings) {
+	if e.journal != nil {
+		if err := e.journal.Close(); err != nil {
+			e.logger.Error("failed to close journal", "error", err)
+		}
+	}
+	bindings.Close()
+}
+
+// hookJournal records entries to next and passes them to the forward hooks
+type hookJournal struct {
+	next  journal.Journal
+	file  *journal.FileJournal
+	hooks []ForwardHook
+}
+
+var _ journal.Journal = (*hookJournal)(nil)
+
+func (j *hookJournal) Record(entry journal.Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	err := j.next.Record(entry)
+	for _, hook := range j.hooks {
+		hook(entry)
+	}
+	return err
+}
+
+func (j *hookJournal) Close() error {
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Close()
+}
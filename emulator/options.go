@@ -0,0 +1,96 @@
+package emulator
+
+import (
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/events"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+// Forward is the outcome of a forward, as it is recorded in the journal
+type Forward = journal.Entry
+
+// Forward statuses
+const (
+	StatusForwarded = journal.StatusForwarded
+	StatusSkipped   = journal.StatusSkipped
+	StatusFailed    = journal.StatusFailed
+)
+
+// RouteStatus is the health of a route, as served on /health/routes by the binary
+type RouteStatus = events.RouteStatus
+
+// ForwardHook is called after every forward, it must not block
+type ForwardHook func(Forward)
+
+// Options are the settings of an emulator for a dev chain, see NewFromOptions.
+// Use New for every setting of config.Config.
+type Options struct {
+	RPCURL          string
+	WSURL           string
+	ContractAddress *config.ContractAddress
+	// DeployerKeyFile is the keystore of the connector, the default anvil/hardhat key when empty
+	DeployerKeyFile string
+	// AutoUpdateConnector makes the deployer the connector of the target contracts on Start
+	AutoUpdateConnector bool
+	// Routes are the names of the routes to run, all routes when empty
+	Routes []string
+	// JournalFile forwards are appended to, no journal when empty
+	JournalFile string
+}
+
+// Config returns the config of o, unset fields keep the defaults of config.DefaultConfig
+func (o Options) Config() *config.Config {
+	cfg := config.DefaultConfig()
+	if o.RPCURL != "" {
+		cfg.RPCURL = o.RPCURL
+	}
+	if o.WSURL != "" {
+		cfg.WSURL = o.WSURL
+	}
+	if o.ContractAddress != nil {
+		cfg.ContractAddress = o.ContractAddress
+	}
+	cfg.DeployerKeyFile = o.DeployerKeyFile
+	cfg.AutoUpdateConnector = o.AutoUpdateConnector
+	cfg.Routes = o.Routes
+	cfg.JournalFile = o.JournalFile
+	cfg.BalanceMonitor = nil
+	return cfg
+}
+
+// Option configures the dependencies of an Emulator
+type Option func(*Emulator)
+
+// WithLogger sets the logger of the emulator, nothing is logged by default
+func WithLogger(logger log.Logger) Option {
+	return func(e *Emulator) {
+		e.logger = logger
+	}
+}
+
+// WithClients uses rpcClient and wsClient instead of dialing the urls of the config.
+// The clients are not closed by Stop.
+func WithClients(rpcClient, wsClient eth.Client) Option {
+	return func(e *Emulator) {
+		e.rpcClient = rpcClient
+		e.wsClient = wsClient
+	}
+}
+
+// WithTxManager sends the forwards through txMgr instead of the deployer and signer keys of the config.
+// Its sender is the connector set by AutoUpdateConnector.
+func WithTxManager(txMgr txmgr.TxManager) Option {
+	return func(e *Emulator) {
+		e.txMgr = txMgr
+	}
+}
+
+// WithForwardHook calls hook after every forward, whatever its status
+func WithForwardHook(hook ForwardHook) Option {
+	return func(e *Emulator) {
+		e.hooks = append(e.hooks, hook)
+	}
+}
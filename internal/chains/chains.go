@@ -2,20 +2,19 @@ package chains
 
 import (
 	"context"
-	stdecdsa "crypto/ecdsa"
 	"math/big"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
 	"github.com/0xPellNetwork/pell-emulator/config"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	rpccalls "github.com/0xPellNetwork/pell-emulator/libs/chains/metrics/collectors/rpc_calls"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/signerv2"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
 )
 
-var (
-	deployerAddress      = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
-	defaultDeployerPkHex = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
-	deployerPrivKeyPair  *stdecdsa.PrivateKey
-)
+var defaultDeployerPkHex = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
 
 type ChainBindings struct {
 	RPCClient   eth.Client
@@ -24,22 +23,83 @@ type ChainBindings struct {
 	WsBindings  *TypesWsBindings
 	TxMgr       txmgr.TxManager
 
+	// SignerAddresses are the addresses forwards are signed with
+	SignerAddresses []gethcommon.Address
+
 	ChainID *big.Int
+	// Endpoint the clients are connected to, the primary one or a fallback
+	Endpoint config.Endpoint
 
 	Config *config.Config
+	// ContractAddress are the addresses of Config with the dependent contracts discovered on-chain
+	ContractAddress *config.ContractAddress
 
 	logger log.Logger
+
+	// deployer is the connector of the target contracts, the sender of deployerSigner
+	// or of the tx manager given by WithTxManager
+	deployer       gethcommon.Address
+	deployerSigner signerv2.Config
+
+	rpcCallsCollector *rpccalls.Collector
+	// connectors are the route targets UpdateConnector takes the connector role of
+	connectors []string
+	// injectedClients are owned by the caller, they are not dialed nor closed
+	injectedClients bool
+	// injectedTxMgr is owned by the caller, it is kept when the bindings are rebuilt
+	injectedTxMgr bool
 }
 
-func NewChainBindings(ctx context.Context, cfg *config.Config, logger log.Logger) (*ChainBindings, error) {
+// Option configures optional dependencies of the ChainBindings
+type Option func(*ChainBindings)
+
+// WithRPCCallsCollector instruments the rpc and ws clients with collector
+func WithRPCCallsCollector(collector *rpccalls.Collector) Option {
+	return func(cb *ChainBindings) {
+		cb.rpcCallsCollector = collector
+	}
+}
+
+// WithConnectorContracts sets the route targets UpdateConnector makes the deployer the connector of,
+// every target with an updatable connector by default
+func WithConnectorContracts(contracts []string) Option {
+	return func(cb *ChainBindings) {
+		cb.connectors = contracts
+	}
+}
+
+// WithClients uses the given rpc and ws clients instead of dialing the endpoints of the config,
+// they are left open by Close
+func WithClients(rpcClient, wsClient eth.Client) Option {
+	return func(cb *ChainBindings) {
+		cb.RPCClient = rpcClient
+		cb.WsClient = wsClient
+		cb.injectedClients = true
+	}
+}
+
+// WithTxManager sends the forwards through txMgr instead of the signers of the config
+func WithTxManager(txMgr txmgr.TxManager) Option {
+	return func(cb *ChainBindings) {
+		cb.TxMgr = txMgr
+		cb.injectedTxMgr = true
+	}
+}
+
+func NewChainBindings(ctx context.Context, cfg *config.Config, logger log.Logger, opts ...Option) (*ChainBindings, error) {
 	var cb = &ChainBindings{
 		Config: cfg,
 		logger: logger.With("module", "chain-bindings"),
 	}
-	err := cb.setupClient()
-	if err != nil {
-		logger.Error("failed to setup client", "error", err)
-		return nil, err
+	for _, opt := range opts {
+		opt(cb)
+	}
+	if !cb.injectedClients {
+		err := cb.setupClient(ctx)
+		if err != nil {
+			logger.Error("failed to setup client", "error", err)
+			return nil, err
+		}
 	}
 	chainID, err := cb.RPCClient.ChainID(ctx)
 	if err != nil {
@@ -49,13 +109,17 @@ func NewChainBindings(ctx context.Context, cfg *config.Config, logger log.Logger
 
 	cb.ChainID = chainID
 
-	err = cb.setupDeployerPrivateKey(cfg.DeployerKeyFile)
+	err = cb.setupDeployerSigner()
 	if err != nil {
-		logger.Error("failed to setup deployer private key", "error", err)
+		logger.Error("failed to setup deployer signer", "error", err)
 		return nil, err
 	}
 
-	err = cb.setupDefaultTxMgr()
+	if cb.TxMgr == nil {
+		err = cb.setupDefaultTxMgr()
+	} else {
+		err = cb.setupInjectedTxMgr()
+	}
 	if err != nil {
 		logger.Error("failed to setup default tx manager", "error", err)
 		return nil, err
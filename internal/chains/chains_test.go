@@ -0,0 +1,87 @@
+package chains
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var (
+	testChainID = big.NewInt(1337)
+	testSigner  = gethcommon.HexToAddress("0x00000000000000000000000000000000000f0002")
+)
+
+// testConfig returns the default config without the connector update on start
+func testConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.AutoUpdateConnector = false
+	return cfg
+}
+
+// testChain is a fake chain whose contracts answer the address views set on it, sending with a
+// recording tx manager from testSigner
+type testChain struct {
+	client *ethtest.Client
+	txMgr  *ethtest.TxManager
+
+	mu    sync.Mutex
+	views map[gethcommon.Address]map[string]gethcommon.Address
+}
+
+func newTestChain() *testChain {
+	return &testChain{
+		client: ethtest.NewClient(testChainID),
+		txMgr:  ethtest.NewTxManager(testSigner),
+		views:  make(map[gethcommon.Address]map[string]gethcommon.Address),
+	}
+}
+
+// setView makes the contract at address return value from the view with signature
+func (c *testChain) setView(address string, signature string, value gethcommon.Address) {
+	contract := gethcommon.HexToAddress(address)
+	selector := string(crypto.Keccak256([]byte(signature))[:4])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.views[contract] == nil {
+		c.views[contract] = make(map[string]gethcommon.Address)
+		c.client.OnCall(contract, func(msg ethereum.CallMsg) ([]byte, error) {
+			if len(msg.Data) < 4 {
+				return nil, nil
+			}
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			value, ok := c.views[contract][string(msg.Data[:4])]
+			if !ok {
+				return nil, nil
+			}
+			return gethcommon.LeftPadBytes(value.Bytes(), 32), nil
+		})
+	}
+	c.views[contract][selector] = value
+}
+
+// view returns the value of the view with signature of the contract at address
+func (c *testChain) view(address string, signature string) gethcommon.Address {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.views[gethcommon.HexToAddress(address)][string(crypto.Keccak256([]byte(signature))[:4])]
+}
+
+// bindings returns the bindings of cfg on c
+func (c *testChain) bindings(t *testing.T, cfg *config.Config, opts ...Option) *ChainBindings {
+	opts = append([]Option{WithClients(c.client, c.client), WithTxManager(c.txMgr)}, opts...)
+	cb, err := NewChainBindings(context.Background(), cfg, log.NewNopLogger(), opts...)
+	require.NoError(t, err)
+	return cb
+}
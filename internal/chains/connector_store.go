@@ -0,0 +1,112 @@
+package chains
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ConnectorRecord is the connector a contract had before the emulator took it over
+type ConnectorRecord struct {
+	Contract          string    `json:"contract"`
+	Address           string    `json:"address"`
+	PreviousConnector string    `json:"previous_connector"`
+	RecordedAt        time.Time `json:"recorded_at"`
+}
+
+// ConnectorStore keeps the connector records of every chain in a json file
+type ConnectorStore struct {
+	path string
+}
+
+func NewConnectorStore(path string) *ConnectorStore {
+	return &ConnectorStore{path: path}
+}
+
+// connectorFile maps a chain id to the records of its contracts, by lower case address
+type connectorFile map[string]map[string]ConnectorRecord
+
+// Load returns the records of chainID
+func (s *ConnectorStore) Load(chainID *big.Int) ([]ConnectorRecord, error) {
+	data, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	var records []ConnectorRecord
+	for _, record := range data[chainID.String()] {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Record stores record unless the contract already has one, the first record holds the
+// connector the emulator took the role from
+func (s *ConnectorStore) Record(chainID *big.Int, record ConnectorRecord) error {
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	chain := chainID.String()
+	if data[chain] == nil {
+		data[chain] = make(map[string]ConnectorRecord)
+	}
+	key := strings.ToLower(record.Address)
+	if _, ok := data[chain][key]; ok {
+		return nil
+	}
+	if record.RecordedAt.IsZero() {
+		record.RecordedAt = time.Now()
+	}
+	data[chain][key] = record
+	return s.write(data)
+}
+
+// Remove deletes the record of the contract at address
+func (s *ConnectorStore) Remove(chainID *big.Int, address string) error {
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	chain := chainID.String()
+	delete(data[chain], strings.ToLower(address))
+	if len(data[chain]) == 0 {
+		delete(data, chain)
+	}
+	return s.write(data)
+}
+
+func (s *ConnectorStore) read() (connectorFile, error) {
+	data := make(connectorFile)
+	raw, err := os.ReadFile(filepath.Clean(s.path))
+	if os.IsNotExist(err) {
+		return data, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read connector file")
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode connector file %s", s.path)
+	}
+	return data, nil
+}
+
+// write replaces the file in one rename so a crash never leaves it half written
+func (s *ConnectorStore) write(data connectorFile) error {
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return errors.Wrap(err, "failed to write connector file")
+	}
+	return os.Rename(tmp, s.path)
+}
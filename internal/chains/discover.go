@@ -0,0 +1,86 @@
+package chains
+
+import (
+	"context"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+)
+
+// dependentContract is a contract whose address is read from a root contract
+type dependentContract struct {
+	name   string
+	root   string
+	getter string
+	// fields returns the dependent and the root address of addrs
+	fields func(addrs *config.ContractAddress) (*string, string)
+}
+
+var dependentContracts = []dependentContract{
+	{
+		name:   "PellStakeRegistryRouter",
+		root:   "PellRegistryRouter",
+		getter: "stakeRegistryRouter()",
+		fields: func(addrs *config.ContractAddress) (*string, string) {
+			return &addrs.PellStakeRegistryRouter, addrs.PellRegistryRouter
+		},
+	},
+	{
+		name:   "DVSOperatorStakeManager",
+		root:   "DVSCentralScheduler",
+		getter: "operatorStakeManager()",
+		fields: func(addrs *config.ContractAddress) (*string, string) {
+			return &addrs.DVSOperatorStakeManager, addrs.DVSCentralScheduler
+		},
+	},
+}
+
+// discoverContractAddress returns a copy of addrs with the dependent contracts read from their
+// root contract. An address set in the config must match the discovered one. If a root does not
+// answer, a configured address is used as is, a missing one is an error.
+func (cb *ChainBindings) discoverContractAddress(ctx context.Context, addrs *config.ContractAddress) (*config.ContractAddress, error) {
+	if addrs == nil {
+		return nil, errors.New("contract_address is not set")
+	}
+	resolved := *addrs
+
+	var mismatches []string
+	for _, dep := range dependentContracts {
+		field, root := dep.fields(&resolved)
+		if !gethcommon.IsHexAddress(root) {
+			return nil, errors.Errorf("invalid %s address %q", dep.root, root)
+		}
+
+		out, err := callView(ctx, cb.RPCClient, gethcommon.HexToAddress(root), dep.getter)
+		if err == nil && len(out) < 32 {
+			err = errors.Errorf("%s returned %d bytes", dep.getter, len(out))
+		}
+		if err != nil {
+			if *field == "" {
+				cb.logger.Error("failed to discover contract", "contract", dep.name, "from", dep.root, "error", err)
+				return nil, errors.Wrapf(err, "failed to discover %s from %s %s", dep.name, dep.root, root)
+			}
+			cb.logger.Error("failed to verify contract, using the configured address",
+				"contract", dep.name, "address", *field, "from", dep.root, "error", err)
+			continue
+		}
+
+		discovered := gethcommon.BytesToAddress(out[:32])
+		if *field != "" && !strings.EqualFold(*field, discovered.Hex()) {
+			mismatches = append(mismatches, dep.name+" is "+*field+" in the config but "+
+				dep.root+" "+root+" reports "+discovered.Hex())
+			continue
+		}
+		*field = discovered.Hex()
+		cb.logger.Info("contract discovered", "contract", dep.name, "address", *field, "from", dep.root)
+	}
+
+	if len(mismatches) > 0 {
+		cb.logger.Error("configured contracts do not match the chain", "mismatches", mismatches)
+		return nil, errors.Errorf("configured contracts do not match the chain: %s", strings.Join(mismatches, "; "))
+	}
+	return &resolved, nil
+}
@@ -0,0 +1,94 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+func TestDiscoverContractAddress(t *testing.T) {
+	stakeRegistryRouter := gethcommon.HexToAddress("0x00000000000000000000000000000000000C0001")
+	operatorStakeManager := gethcommon.HexToAddress("0x00000000000000000000000000000000000C0002")
+	other := "0x00000000000000000000000000000000000C0003"
+
+	var tests = map[string]struct {
+		// answer makes the root contracts report the dependent contracts
+		answer bool
+		// configured stake registry router and operator stake manager
+		stakeRegistryRouter  string
+		operatorStakeManager string
+		wantErr              string
+	}{
+		"discovered": {
+			answer: true,
+		},
+		"configured and matching": {
+			answer:               true,
+			stakeRegistryRouter:  "0x00000000000000000000000000000000000c0001",
+			operatorStakeManager: operatorStakeManager.Hex(),
+		},
+		"configured and not matching": {
+			answer:               true,
+			stakeRegistryRouter:  other,
+			operatorStakeManager: operatorStakeManager.Hex(),
+			wantErr: "configured contracts do not match the chain: PellStakeRegistryRouter is " + other +
+				" in the config but PellRegistryRouter",
+		},
+		"missing": {
+			wantErr: "failed to discover PellStakeRegistryRouter from PellRegistryRouter",
+		},
+		"configured and root not answering": {
+			stakeRegistryRouter:  stakeRegistryRouter.Hex(),
+			operatorStakeManager: operatorStakeManager.Hex(),
+		},
+		"one configured and root not answering": {
+			stakeRegistryRouter: stakeRegistryRouter.Hex(),
+			wantErr:             "failed to discover DVSOperatorStakeManager from DVSCentralScheduler",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.ContractAddress.PellStakeRegistryRouter = tt.stakeRegistryRouter
+			cfg.ContractAddress.DVSOperatorStakeManager = tt.operatorStakeManager
+			configured := *cfg.ContractAddress
+
+			c := newTestChain()
+			if tt.answer {
+				c.setView(cfg.ContractAddress.PellRegistryRouter, "stakeRegistryRouter()", stakeRegistryRouter)
+				c.setView(cfg.ContractAddress.DVSCentralScheduler, "operatorStakeManager()", operatorStakeManager)
+			}
+			cb := &ChainBindings{RPCClient: c.client, Config: cfg, logger: log.NewNopLogger()}
+
+			resolved, err := cb.discoverContractAddress(context.Background(), cfg.ContractAddress)
+			// the config is never written, a discovered address stays out of the saved file
+			assert.Equal(t, configured, *cfg.ContractAddress)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, stakeRegistryRouter, gethcommon.HexToAddress(resolved.PellStakeRegistryRouter))
+			assert.Equal(t, operatorStakeManager, gethcommon.HexToAddress(resolved.DVSOperatorStakeManager))
+		})
+	}
+
+	t.Run("bindings", func(t *testing.T) {
+		cfg := testConfig()
+		c := newTestChain()
+		c.setView(cfg.ContractAddress.PellRegistryRouter, "stakeRegistryRouter()", stakeRegistryRouter)
+		c.setView(cfg.ContractAddress.DVSCentralScheduler, "operatorStakeManager()", operatorStakeManager)
+
+		cb := c.bindings(t, cfg)
+		assert.Equal(t, stakeRegistryRouter.Hex(), cb.ContractAddress.PellStakeRegistryRouter)
+		assert.Equal(t, operatorStakeManager.Hex(), cb.ContractAddress.DVSOperatorStakeManager)
+		assert.Empty(t, cfg.ContractAddress.PellStakeRegistryRouter)
+		assert.Empty(t, cfg.ContractAddress.DVSOperatorStakeManager)
+	})
+}
@@ -0,0 +1,420 @@
+package chains
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/service_evm/registryinteractor.sol"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "PASS"
+	CheckWarn CheckStatus = "WARN"
+	CheckFail CheckStatus = "FAIL"
+)
+
+// Check is the result of a single doctor check
+type Check struct {
+	Name   string      `json:"name"`
+	Status CheckStatus `json:"status"`
+	Detail string      `json:"detail"`
+}
+
+// DoctorReport is the list of checks run by Doctor, in order
+type DoctorReport struct {
+	Checks []Check `json:"checks"`
+}
+
+func (r *DoctorReport) pass(name, format string, args ...any) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: CheckPass, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (r *DoctorReport) warn(name, format string, args ...any) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: CheckWarn, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (r *DoctorReport) fail(name, format string, args ...any) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: CheckFail, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Failed returns the number of failed checks
+func (r *DoctorReport) Failed() int {
+	var n int
+	for _, c := range r.Checks {
+		if c.Status == CheckFail {
+			n++
+		}
+	}
+	return n
+}
+
+// contractProbe tells a contract of the expected type apart from the other contracts of the config:
+// it answers every view function of signatures without reverting and its code emits event
+type contractProbe struct {
+	name       string
+	address    string
+	signatures []string
+	event      *abi.Event
+}
+
+// describe returns what the probe checked, for the report
+func (p contractProbe) describe() string {
+	var parts []string
+	if len(p.signatures) > 0 {
+		parts = append(parts, "answers "+strings.Join(p.signatures, ", "))
+	}
+	if p.event != nil {
+		parts = append(parts, "emits "+p.event.Sig)
+	}
+	if len(parts) == 0 {
+		return "type not probed"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// connectorTargets are the route targets whose connector is set to the deployer by UpdateConnector
+func (cb *ChainBindings) connectorTargets(addrs *config.ContractAddress) []contractProbe {
+	all := []contractProbe{
+		{name: "DVSCentralScheduler", address: addrs.DVSCentralScheduler},
+		{name: "ServiceOmniOperatorShareManager", address: addrs.ServiceOmniOperatorSharesManager},
+		{name: "StakingDelegationManager", address: addrs.StakingDelegationManager},
+	}
+	if cb.connectors == nil {
+		return all
+	}
+	var targets []contractProbe
+	for _, target := range all {
+		if slices.Contains(cb.connectors, target.name) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// Doctor runs the preflight checks of cfg: the endpoints are reachable and on the same chain,
+// every contract has code and answers a view function of its type, the signers are funded
+// and the deployer is the connector of the target contracts. The bindings are not set up
+// so it works on a config that NewChainBindings would reject.
+func Doctor(ctx context.Context, cfg *config.Config, logger log.Logger, opts ...Option) *DoctorReport {
+	report := &DoctorReport{}
+	cb := &ChainBindings{
+		Config: cfg,
+		logger: logger.With("module", "doctor"),
+	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+
+	cb.checkEndpoints(ctx, report)
+	if cb.RPCClient == nil {
+		report.fail("contracts", "skipped, no endpoint is reachable")
+		return report
+	}
+	defer cb.Close()
+
+	if cfg.ContractAddress == nil {
+		report.fail("contracts", "contract_address is not set")
+	} else {
+		cb.checkContracts(ctx, report)
+	}
+
+	if err := cb.setupDeployerSigner(); err != nil {
+		report.fail("signer", "%v", err)
+		return report
+	}
+	if err := cb.setupDefaultTxMgr(); err != nil {
+		report.fail("signer", "%v", err)
+		return report
+	}
+	cb.checkSignerBalances(ctx, report)
+
+	if cfg.ContractAddress != nil {
+		cb.checkConnectors(ctx, report)
+	}
+	return report
+}
+
+// checkEndpoints dials every endpoint and keeps the first reachable one in cb
+func (cb *ChainBindings) checkEndpoints(ctx context.Context, report *DoctorReport) {
+	for i, endpoint := range cb.Config.Endpoints() {
+		name := "endpoint"
+		if i > 0 {
+			name = fmt.Sprintf("fallback endpoint %d", i)
+		}
+
+		var chainIDs []*big.Int
+		var clients []eth.Client
+		for _, url := range []string{endpoint.RPCURL, endpoint.WSURL} {
+			client, chainID, err := dialChainID(ctx, url)
+			if err != nil {
+				report.fail(name, "%s: %v", url, err)
+				continue
+			}
+			clients = append(clients, client)
+			chainIDs = append(chainIDs, chainID)
+		}
+		if len(clients) != 2 {
+			for _, client := range clients {
+				closeClient(client)
+			}
+			continue
+		}
+
+		switch {
+		case chainIDs[0].Cmp(chainIDs[1]) != 0:
+			report.fail(name, "rpc %s reports chain id %s, ws %s reports chain id %s",
+				endpoint.RPCURL, chainIDs[0], endpoint.WSURL, chainIDs[1])
+		case cb.ChainID != nil && chainIDs[0].Cmp(cb.ChainID) != 0:
+			report.fail(name, "chain id %s does not match the primary endpoint chain id %s", chainIDs[0], cb.ChainID)
+		default:
+			report.pass(name, "rpc %s and ws %s on chain id %s", endpoint.RPCURL, endpoint.WSURL, chainIDs[0])
+		}
+		if cb.RPCClient == nil && chainIDs[0].Cmp(chainIDs[1]) == 0 {
+			cb.RPCClient, cb.WsClient, cb.ChainID, cb.Endpoint = clients[0], clients[1], chainIDs[0], endpoint
+			continue
+		}
+		closeClient(clients[0])
+		closeClient(clients[1])
+	}
+}
+
+func dialChainID(ctx context.Context, url string) (eth.Client, *big.Int, error) {
+	client, err := eth.NewClient(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		closeClient(client)
+		return nil, nil, err
+	}
+	return client, chainID, nil
+}
+
+// contractProbes are the probes of the configured contracts. The delegation managers and the
+// omni shares manager have views of the same name, a second view with another signature
+// tells them apart. The registry interactor has no view the emulator relies on, it is
+// recognized by the event its route listens to.
+func contractProbes(addrs *config.ContractAddress) ([]contractProbe, error) {
+	interactorABI, err := registryinteractor.RegistryInteractorMetaData.GetAbi()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the RegistryInteractor abi")
+	}
+	registerCentralScheduler, ok := interactorABI.Events["RegisterCentralSchedulerToPell"]
+	if !ok {
+		return nil, errors.New("RegistryInteractor abi has no RegisterCentralSchedulerToPell event")
+	}
+
+	return []contractProbe{
+		{
+			name: "PellDelegationManager", address: addrs.PellDelegationManager,
+			signatures: []string{"isOperator(address)", "getOperatorShares(address,(uint256,address)[])"},
+		},
+		{
+			name: "PellRegistryRouter", address: addrs.PellRegistryRouter,
+			signatures: []string{"stakeRegistryRouter()"},
+		},
+		{
+			name: "PellRegistryInteractor", address: addrs.PellRegistryInteractor,
+			event: &registerCentralScheduler,
+		},
+		{
+			name: "PellStrategyManager", address: addrs.PellStrategyManager,
+			signatures: []string{"stakerStrategyShares(uint256,address,address)"},
+		},
+		{
+			name: "StakingStrategyManager", address: addrs.StakingStrategyManager,
+			signatures: []string{"delegation()", "stakerStrategyShares(address,address)"},
+		},
+		{
+			name: "StakingDelegationManager", address: addrs.StakingDelegationManager,
+			signatures: []string{"isOperator(address)", "getOperatorShares(address,address[])"},
+		},
+		{
+			name: "ServiceOmniOperatorSharesManager", address: addrs.ServiceOmniOperatorSharesManager,
+			signatures: []string{"connector()", "getOperatorShares(address,(uint256,address)[])"},
+		},
+		{
+			name: "DVSCentralScheduler", address: addrs.DVSCentralScheduler,
+			signatures: []string{"connector()", "operatorStakeManager()"},
+		},
+	}, nil
+}
+
+// checkContracts checks every configured contract has code and answers the probe of its type,
+// then that the dependent contracts discovered from their roots match the config and have code
+func (cb *ChainBindings) checkContracts(ctx context.Context, report *DoctorReport) {
+	addrs := cb.Config.ContractAddress
+	probes, err := contractProbes(addrs)
+	if err != nil {
+		report.fail("contracts", "%v", err)
+		return
+	}
+
+	for _, probe := range probes {
+		if err := cb.probeContract(ctx, probe); err != nil {
+			report.fail(probe.name, "%v", err)
+		} else {
+			report.pass(probe.name, "%s has code and %s", probe.address, probe.describe())
+		}
+	}
+
+	resolved, err := cb.discoverContractAddress(ctx, addrs)
+	if err != nil {
+		report.fail("discovery", "%v", err)
+		return
+	}
+	for _, dep := range dependentContracts {
+		address, _ := dep.fields(resolved)
+		if err := cb.probeContract(ctx, contractProbe{name: dep.name, address: *address}); err != nil {
+			report.fail(dep.name, "%v", err)
+		} else {
+			report.pass(dep.name, "%s from %s has code", *address, dep.root)
+		}
+	}
+}
+
+// probeContract checks probe.address has code, answers every view of the probe called with
+// zero arguments and, behind its proxy if any, has the code emitting the event of the probe
+func (cb *ChainBindings) probeContract(ctx context.Context, probe contractProbe) error {
+	if !gethcommon.IsHexAddress(probe.address) {
+		return errors.Errorf("invalid address %q", probe.address)
+	}
+	address := gethcommon.HexToAddress(probe.address)
+	code, err := cb.RPCClient.CodeAt(ctx, address, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to get code")
+	}
+	if len(code) == 0 {
+		return errors.Errorf("no code at %s", probe.address)
+	}
+
+	for _, signature := range probe.signatures {
+		out, err := callView(ctx, cb.RPCClient, address, signature)
+		if err != nil {
+			return errors.Wrapf(err, "%s does not answer %s, wrong contract?", probe.address, signature)
+		}
+		if len(out) < 32 {
+			return errors.Errorf("%s returned %d bytes from %s, wrong contract?", probe.address, len(out), signature)
+		}
+	}
+
+	if probe.event != nil {
+		code, err = cb.implementationCode(ctx, address, code)
+		if err != nil {
+			return err
+		}
+		// non-anonymous events push their topic as a constant
+		if !bytes.Contains(code, probe.event.ID.Bytes()) {
+			return errors.Errorf("%s does not emit %s, wrong contract?", probe.address, probe.event.Sig)
+		}
+	}
+	return nil
+}
+
+// eip1967ImplementationSlot is the storage slot of the implementation of an EIP-1967 proxy
+var eip1967ImplementationSlot = gethcommon.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// implementationCode returns the code of the implementation when address is an EIP-1967 proxy, code otherwise
+func (cb *ChainBindings) implementationCode(ctx context.Context, address gethcommon.Address, code []byte) ([]byte, error) {
+	slot, err := cb.RPCClient.StorageAt(ctx, address, eip1967ImplementationSlot, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the implementation slot")
+	}
+	implementation := gethcommon.BytesToAddress(slot)
+	if implementation == (gethcommon.Address{}) {
+		return code, nil
+	}
+	implCode, err := cb.RPCClient.CodeAt(ctx, implementation, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get code of implementation %s", implementation.Hex())
+	}
+	return implCode, nil
+}
+
+// callView calls a view function by its signature, every argument is passed as a zero word
+func callView(ctx context.Context, client eth.Client, to gethcommon.Address, signature string) ([]byte, error) {
+	data := crypto.Keccak256([]byte(signature))[:4]
+	args := strings.TrimSuffix(signature[strings.Index(signature, "(")+1:], ")")
+	if args != "" {
+		data = append(data, make([]byte, 32*len(strings.Split(args, ",")))...)
+	}
+	return client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+}
+
+// ReadConnector returns the connector of the contract at address
+func ReadConnector(ctx context.Context, client eth.Client, address gethcommon.Address) (gethcommon.Address, error) {
+	out, err := callView(ctx, client, address, "connector()")
+	if err != nil {
+		return gethcommon.Address{}, errors.Wrap(err, "failed to read connector")
+	}
+	if len(out) < 32 {
+		return gethcommon.Address{}, errors.Errorf("connector() returned %d bytes", len(out))
+	}
+	return gethcommon.BytesToAddress(out[:32]), nil
+}
+
+func (cb *ChainBindings) checkSignerBalances(ctx context.Context, report *DoctorReport) {
+	var warnBalance *big.Int
+	if cb.Config.BalanceMonitor != nil {
+		warnBalance, _ = new(big.Int).SetString(cb.Config.BalanceMonitor.WarnBalance, 10)
+	}
+
+	for _, signer := range cb.SignerAddresses {
+		name := "signer " + signer.Hex()
+		balance, err := cb.RPCClient.BalanceAt(ctx, signer, nil)
+		if err != nil {
+			report.fail(name, "failed to get balance: %v", err)
+			continue
+		}
+		switch {
+		case balance.Sign() == 0:
+			report.fail(name, "no balance to pay for the forwards")
+		case warnBalance != nil && balance.Cmp(warnBalance) < 0:
+			report.warn(name, "balance %s wei is below balance_monitor.warn_balance %s wei", balance, warnBalance)
+		default:
+			report.pass(name, "balance %s wei", balance)
+		}
+	}
+}
+
+// checkConnectors checks the signer is the connector of the route targets
+func (cb *ChainBindings) checkConnectors(ctx context.Context, report *DoctorReport) {
+	deployer := cb.deployer
+	for _, target := range cb.connectorTargets(cb.Config.ContractAddress) {
+		check := "connector of " + target.name
+		if !gethcommon.IsHexAddress(target.address) {
+			report.fail(check, "invalid address %q", target.address)
+			continue
+		}
+		connector, err := ReadConnector(ctx, cb.RPCClient, gethcommon.HexToAddress(target.address))
+		if err != nil {
+			report.fail(check, "%v", err)
+			continue
+		}
+
+		switch {
+		case connector != deployer && cb.Config.AutoUpdateConnector:
+			report.warn(check, "%s is not the signer %s, it is updated on start", connector.Hex(), deployer.Hex())
+		case connector != deployer:
+			report.fail(check, "%s is not the signer %s, run update-connector", connector.Hex(), deployer.Hex())
+		default:
+			report.pass(check, "signer %s", deployer.Hex())
+		}
+	}
+}
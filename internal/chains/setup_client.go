@@ -1,24 +1,154 @@
 package chains
 
-import "github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+import (
+	"context"
+	"slices"
 
-func (cb *ChainBindings) setupClient() error {
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+)
+
+// setupClient connects to the first reachable endpoint, the primary endpoint is tried first
+func (cb *ChainBindings) setupClient(ctx context.Context) error {
+	var lastErr error
+	for _, endpoint := range cb.Config.Endpoints() {
+		err := cb.connect(ctx, endpoint)
+		if err == nil {
+			return nil
+		}
+		cb.logger.Error("endpoint unreachable", "rpcURL", endpoint.RPCURL, "wsURL", endpoint.WSURL, "error", err)
+		lastErr = err
+	}
+	return lastErr
+}
+
+func (cb *ChainBindings) connect(ctx context.Context, endpoint config.Endpoint) error {
 	var err error
-	wsClient, err := eth.NewClient(cb.Config.WSURL)
+	wsClient, err := cb.newClient(endpoint.WSURL)
 	if err != nil {
 		cb.logger.Error("Failed to connect to the Ethereum wsClient", "error", err)
 		return err
 
 	}
-	cb.WsClient = wsClient
 
-	rpcClient, err := eth.NewClient(cb.Config.RPCURL)
+	rpcClient, err := cb.newClient(endpoint.RPCURL)
 	if err != nil {
 		cb.logger.Error("Failed to connect to the Ethereum rpcClient", "error", err)
+		closeClient(wsClient)
 		return err
 	}
 
+	// the http client dials lazily, probe both so an unreachable node is skipped
+	for _, client := range []eth.Client{rpcClient, wsClient} {
+		chainID, err := client.ChainID(ctx)
+		if err == nil && cb.ChainID != nil && chainID.Cmp(cb.ChainID) != 0 {
+			err = errors.Errorf("chain id %s does not match %s", chainID, cb.ChainID)
+		}
+		if err != nil {
+			closeClient(wsClient)
+			closeClient(rpcClient)
+			return err
+		}
+	}
+
+	cb.WsClient = wsClient
 	cb.RPCClient = rpcClient
+	cb.Endpoint = endpoint
 
 	return nil
 }
+
+// newClient dials url, the client is instrumented when a rpc calls collector is set
+func (cb *ChainBindings) newClient(url string) (eth.Client, error) {
+	if cb.rpcCallsCollector != nil {
+		return eth.NewInstrumentedClient(url, cb.rpcCallsCollector)
+	}
+	return eth.NewClient(url)
+}
+
+// Reconnect returns new bindings on the first reachable endpoint, with new clients, tx manager
+// and contract bindings. The connector is not updated again. Clients and tx manager given by
+// WithClients and WithTxManager are kept, the clients are only probed again.
+func (cb *ChainBindings) Reconnect(ctx context.Context) (*ChainBindings, error) {
+	return cb.Rebuild(ctx, cb.Config)
+}
+
+// Rebuild returns new bindings for cfg on the same chain, cb is left untouched. Clients and
+// tx manager given by WithClients and WithTxManager are kept, so cfg must not change the
+// endpoints or the signers they replace.
+func (cb *ChainBindings) Rebuild(ctx context.Context, cfg *config.Config) (*ChainBindings, error) {
+	next := &ChainBindings{
+		ChainID:           cb.ChainID,
+		Config:            cfg,
+		logger:            cb.logger,
+		rpcCallsCollector: cb.rpcCallsCollector,
+		connectors:        cb.connectors,
+		injectedClients:   cb.injectedClients,
+		injectedTxMgr:     cb.injectedTxMgr,
+	}
+	if err := next.setupRebuiltClient(ctx, cb); err != nil {
+		return nil, err
+	}
+	if err := next.setupRebuiltTxMgr(cb); err != nil {
+		next.Close()
+		return nil, err
+	}
+	if err := next.setupBindings(ctx); err != nil {
+		next.Close()
+		return nil, errors.Wrap(err, "failed to setup bindings")
+	}
+	return next, nil
+}
+
+// setupRebuiltClient dials the endpoints of the config, or keeps the clients given by WithClients
+// to prev once they answer again
+func (cb *ChainBindings) setupRebuiltClient(ctx context.Context, prev *ChainBindings) error {
+	if !cb.injectedClients {
+		return cb.setupClient(ctx)
+	}
+	if !slices.Equal(cb.Config.Endpoints(), prev.Config.Endpoints()) {
+		return errors.New("the endpoints cannot change, the clients were given by WithClients")
+	}
+	for _, client := range []eth.Client{prev.RPCClient, prev.WsClient} {
+		if _, err := client.ChainID(ctx); err != nil {
+			return errors.Wrap(err, "client given by WithClients is unreachable, it is not dialed again")
+		}
+	}
+	cb.RPCClient = prev.RPCClient
+	cb.WsClient = prev.WsClient
+	cb.Endpoint = prev.Endpoint
+	return nil
+}
+
+// setupRebuiltTxMgr builds the tx manager from the signers of the config, or keeps the one
+// given by WithTxManager to prev
+func (cb *ChainBindings) setupRebuiltTxMgr(prev *ChainBindings) error {
+	if !cb.injectedTxMgr {
+		if err := cb.setupDeployerSigner(); err != nil {
+			return errors.Wrap(err, "failed to setup deployer signer")
+		}
+		if err := cb.setupDefaultTxMgr(); err != nil {
+			return errors.Wrap(err, "failed to setup tx manager")
+		}
+		return nil
+	}
+	cb.TxMgr = prev.TxMgr
+	return cb.setupInjectedTxMgr()
+}
+
+// Close closes the rpc and ws clients, unless they were given by WithClients
+func (cb *ChainBindings) Close() {
+	if cb.injectedClients {
+		return
+	}
+	closeClient(cb.RPCClient)
+	closeClient(cb.WsClient)
+}
+
+func closeClient(client eth.Client) {
+	if closer, ok := client.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
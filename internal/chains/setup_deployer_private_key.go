@@ -7,7 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 
-	"github.com/0xPellNetwork/pell-emulator/libs/chains/crypto/ecdsa"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/signerv2"
 )
 
 // setupDeployerPrivateKey if keyFilePath is empty, use defaultDeployerPkHex
@@ -18,25 +18,27 @@ func (cb *ChainBindings) setupDeployerPrivateKey(keyFilePath string) error {
 		if err != nil {
 			return errors.Wrap(err, "failed to decode deployer private key")
 		}
-		deployerAddress = crypto.PubkeyToAddress(privateKeyPair.PublicKey).Hex()
-		deployerPrivKeyPair = privateKeyPair
+		cb.deployer = crypto.PubkeyToAddress(privateKeyPair.PublicKey)
+		cb.deployerSigner = signerv2.Config{PrivateKey: privateKeyPair}
 		return nil
 	}
 
-	pk, err := ecdsa.ReadKey(keyFilePath, "")
+	pk, err := cb.ReadKeyFile(keyFilePath)
 	if err != nil {
 		return errors.Wrap(err, "failed to read deployer private key")
 	}
 
-	publicKey := pk.Public()
-	publicKeyECDSA, ok := publicKey.(*stdecdsa.PublicKey)
-	if !ok {
-		return errors.New("error casting public key to ECDSA public key")
-	}
-
-	deployerAddress = crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
-	//privateKeyHex := hex.EncodeToString(pk.D.Bytes())
-	deployerPrivKeyPair = pk
+	cb.deployer = crypto.PubkeyToAddress(pk.PublicKey)
+	cb.deployerSigner = signerv2.Config{PrivateKey: pk}
 
 	return nil
 }
+
+// ReadKeyFile decrypts the keystore keyFile with the password of the signer section of the config
+func (cb *ChainBindings) ReadKeyFile(keyFile string) (*stdecdsa.PrivateKey, error) {
+	signerCfg, err := newSignerV2Config(cb.Config.KeyFileSigner(keyFile))
+	if err != nil {
+		return nil, err
+	}
+	return signerCfg.PrivateKey, nil
+}
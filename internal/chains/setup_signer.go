@@ -0,0 +1,98 @@
+package chains
+
+import (
+	"os"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/crypto/ecdsa"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/signerv2"
+)
+
+// setupDeployerSigner resolves the signer of the deployer from the `signer` section of the config,
+// if it is not set, the deployer key file is used.
+func (cb *ChainBindings) setupDeployerSigner() error {
+	if cb.Config.Signer == nil {
+		return cb.setupDeployerPrivateKey(cb.Config.DeployerKeyFile)
+	}
+
+	signerCfg, err := newSignerV2Config(cb.Config.Signer)
+	if err != nil {
+		return err
+	}
+
+	_, sender, err := signerv2.SignerFromConfig(signerCfg, cb.ChainID)
+	if err != nil {
+		return errors.Wrap(err, "failed to create signer")
+	}
+
+	cb.deployer = sender
+	cb.deployerSigner = signerCfg
+
+	cb.logger.Info("deployer signer", "type", cb.Config.Signer.Type, "address", cb.deployer)
+	return nil
+}
+
+func newSignerV2Config(sc *config.SignerConfig) (signerv2.Config, error) {
+	switch sc.Type {
+	case config.SignerTypePrivateKey:
+		if sc.PrivateKey == "" {
+			return signerv2.Config{}, errors.New("signer: private_key is required")
+		}
+		pk, err := crypto.HexToECDSA(strings.TrimPrefix(sc.PrivateKey, "0x"))
+		if err != nil {
+			return signerv2.Config{}, errors.Wrap(err, "signer: failed to decode private key")
+		}
+		return signerv2.Config{PrivateKey: pk}, nil
+	case config.SignerTypeKeystore:
+		if sc.KeystoreFile == "" {
+			return signerv2.Config{}, errors.New("signer: keystore_file is required")
+		}
+		password, err := readSignerPassword(sc)
+		if err != nil {
+			return signerv2.Config{}, err
+		}
+		// decrypt once to fail early on a wrong password, the keystore signer would
+		// otherwise decrypt the key (scrypt) for every tx
+		pk, err := ecdsa.ReadKey(sc.KeystoreFile, password)
+		if err != nil {
+			return signerv2.Config{}, errors.Wrap(err, "signer: failed to decrypt keystore")
+		}
+		return signerv2.Config{PrivateKey: pk}, nil
+	case config.SignerTypeWeb3Signer:
+		if sc.Web3SignerURL == "" || sc.Address == "" {
+			return signerv2.Config{}, errors.New("signer: web3signer_url and address are required")
+		}
+		if !gethcommon.IsHexAddress(sc.Address) {
+			return signerv2.Config{}, errors.Errorf("signer: invalid address %s", sc.Address)
+		}
+		return signerv2.Config{Endpoint: sc.Web3SignerURL, Address: sc.Address}, nil
+	default:
+		return signerv2.Config{}, errors.Errorf("signer: unknown type %q, expected one of %s, %s, %s",
+			sc.Type, config.SignerTypePrivateKey, config.SignerTypeKeystore, config.SignerTypeWeb3Signer)
+	}
+}
+
+// readSignerPassword reads the keystore password from the password file or the env var,
+// an empty password is used if neither is set
+func readSignerPassword(sc *config.SignerConfig) (string, error) {
+	if sc.PasswordFile != "" {
+		b, err := os.ReadFile(sc.PasswordFile)
+		if err != nil {
+			return "", errors.Wrap(err, "signer: failed to read password file")
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	if sc.PasswordEnv != "" {
+		password, ok := os.LookupEnv(sc.PasswordEnv)
+		if !ok {
+			return "", errors.Errorf("signer: password env var %s is not set", sc.PasswordEnv)
+		}
+		return password, nil
+	}
+	return "", nil
+}
@@ -1,22 +1,46 @@
 package chains
 
 import (
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/signerv2"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/wallet"
 )
 
 func (cb *ChainBindings) setupDefaultTxMgr() error {
-	keyWallet, sender, err := wallet.GetLocalGetWalletByPrivateKey(
-		deployerPrivKeyPair,
+	txMgr, sender, err := cb.newSimpleTxMgr(cb.deployerSigner)
+	if err != nil {
+		return err
+	}
+	cb.SignerAddresses = []gethcommon.Address{sender}
+	cb.TxMgr = txMgr
+	return nil
+}
+
+// setupInjectedTxMgr sets the signer addresses from the tx manager given by WithTxManager,
+// its sender takes the place of the deployer as the connector of the target contracts
+func (cb *ChainBindings) setupInjectedTxMgr() error {
+	opts, err := cb.TxMgr.GetNoSendTxOpts()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the sender of the tx manager")
+	}
+	cb.deployer = opts.From
+	cb.SignerAddresses = []gethcommon.Address{opts.From}
+	return nil
+}
+
+func (cb *ChainBindings) newSimpleTxMgr(signerCfg signerv2.Config) (*txmgr.SimpleTxManager, gethcommon.Address, error) {
+	keyWallet, sender, err := wallet.GetWalletFromSignerConfig(
+		signerCfg,
 		cb.RPCClient,
 		cb.ChainID,
 		cb.logger,
 	)
 	if err != nil {
-		return err
+		return nil, gethcommon.Address{}, err
 	}
 
-	txMgr := txmgr.NewSimpleTxManager(keyWallet, cb.RPCClient, cb.logger, sender)
-	cb.TxMgr = txMgr
-	return nil
+	return txmgr.NewSimpleTxManager(keyWallet, cb.RPCClient, cb.logger, sender), sender, nil
 }
@@ -0,0 +1,98 @@
+package chains
+
+import (
+	"context"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+)
+
+// devChainIDs are the chain ids of local anvil/hardhat/geth --dev nodes, signers are only topped up on these
+var devChainIDs = map[uint64]bool{
+	1337:  true,
+	31337: true,
+}
+
+func (cb *ChainBindings) IsDevChain() bool {
+	return cb.ChainID.IsUint64() && devChainIDs[cb.ChainID.Uint64()]
+}
+
+// TopUp raises the balance of address from current to target, either with a transfer
+// from the funder key or with the set balance rpc of anvil/hardhat
+func (cb *ChainBindings) TopUp(
+	ctx context.Context,
+	monitorCfg *config.BalanceMonitorConfig,
+	address gethcommon.Address,
+	current, target *big.Int,
+) error {
+	if !cb.IsDevChain() {
+		return errors.Errorf("refusing to top up on chain %s, not a dev chain", cb.ChainID)
+	}
+	if current.Cmp(target) >= 0 {
+		return nil
+	}
+
+	if monitorCfg.FunderKeyFile != "" {
+		return cb.topUpFromFunder(ctx, monitorCfg.FunderKeyFile, address, new(big.Int).Sub(target, current))
+	}
+	return cb.topUpWithSetBalance(ctx, monitorCfg.SetBalanceMethod, address, target)
+}
+
+func (cb *ChainBindings) topUpFromFunder(ctx context.Context, funderKeyFile string, address gethcommon.Address, amount *big.Int) error {
+	signerCfg, err := newSignerV2Config(cb.Config.KeyFileSigner(funderKeyFile))
+	if err != nil {
+		return errors.Wrap(err, "failed to read funder key")
+	}
+	funderTxMgr, funder, err := cb.newSimpleTxMgr(signerCfg)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := cb.RPCClient.PendingNonceAt(ctx, funder)
+	if err != nil {
+		return errors.Wrap(err, "failed to get funder nonce")
+	}
+	receipt, err := funderTxMgr.Send(ctx, gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+		ChainID: cb.ChainID,
+		To:      &address,
+		Value:   amount,
+		Nonce:   nonce,
+	}))
+	if err != nil {
+		return errors.Wrap(err, "failed to send top up tx")
+	}
+
+	cb.logger.Info("topped up signer from funder",
+		"signer", address,
+		"funder", funder,
+		"amount", amount,
+		"txHash", receipt.TxHash.String(),
+	)
+	return nil
+}
+
+func (cb *ChainBindings) topUpWithSetBalance(ctx context.Context, method string, address gethcommon.Address, target *big.Int) error {
+	if method != config.SetBalanceMethodAnvil && method != config.SetBalanceMethodHardhat {
+		return errors.Errorf("unsupported set balance method %q", method)
+	}
+
+	client, err := rpc.DialContext(ctx, cb.Endpoint.RPCURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to dial rpc")
+	}
+	defer client.Close()
+
+	err = client.CallContext(ctx, nil, method, address, hexutil.EncodeBig(target))
+	if err != nil {
+		return errors.Wrapf(err, "failed to call %s", method)
+	}
+
+	cb.logger.Info("topped up signer", "signer", address, "method", method, "balance", target)
+	return nil
+}
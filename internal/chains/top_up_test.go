@@ -0,0 +1,122 @@
+package chains
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/crypto/ecdsa"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+func TestTopUp(t *testing.T) {
+	target := big.NewInt(1000)
+
+	var tests = map[string]struct {
+		chainID *big.Int
+		method  string
+		current *big.Int
+		want    *big.Int
+		wantErr string
+	}{
+		"anvil": {
+			chainID: testChainID,
+			method:  config.SetBalanceMethodAnvil,
+			current: big.NewInt(10),
+			want:    target,
+		},
+		"hardhat": {
+			chainID: big.NewInt(31337),
+			method:  config.SetBalanceMethodHardhat,
+			current: big.NewInt(10),
+			want:    target,
+		},
+		"above the target": {
+			chainID: testChainID,
+			method:  config.SetBalanceMethodAnvil,
+			current: big.NewInt(2000),
+			want:    big.NewInt(2000),
+		},
+		"not a dev chain": {
+			chainID: big.NewInt(1),
+			method:  config.SetBalanceMethodAnvil,
+			current: big.NewInt(10),
+			want:    big.NewInt(10),
+			wantErr: "refusing to top up on chain 1, not a dev chain",
+		},
+		"unsupported method": {
+			chainID: testChainID,
+			method:  "geth_setBalance",
+			current: big.NewInt(10),
+			want:    big.NewInt(10),
+			wantErr: `unsupported set balance method "geth_setBalance"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := ethtest.NewClient(tt.chainID)
+			client.SetBalance(testSigner, tt.current)
+			node := ethtest.NewDevNodeServer(client)
+			defer node.Close()
+
+			cb := &ChainBindings{
+				RPCClient: client,
+				ChainID:   tt.chainID,
+				Endpoint:  config.Endpoint{RPCURL: node.URL},
+				Config:    testConfig(),
+				logger:    log.NewNopLogger(),
+			}
+			err := cb.TopUp(context.Background(), &config.BalanceMonitorConfig{SetBalanceMethod: tt.method},
+				testSigner, tt.current, target)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			balance, err := client.BalanceAt(context.Background(), testSigner, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, balance)
+		})
+	}
+}
+
+func TestTopUpFromFunder(t *testing.T) {
+	funderKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	funderKeyFile := filepath.Join(t.TempDir(), "funder.json")
+	require.NoError(t, ecdsa.WriteKey(funderKeyFile, funderKey, ""))
+
+	client := ethtest.NewClient(testChainID)
+	cb := &ChainBindings{
+		RPCClient: client,
+		ChainID:   testChainID,
+		Config:    testConfig(),
+		logger:    log.NewNopLogger(),
+	}
+
+	// the funder tx manager polls the receipt every 2s
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err = cb.TopUp(ctx, &config.BalanceMonitorConfig{FunderKeyFile: funderKeyFile},
+		testSigner, big.NewInt(400), big.NewInt(1000))
+	require.NoError(t, err)
+
+	require.Len(t, client.Sent(), 1)
+	tx := client.Sent()[0]
+	assert.Equal(t, testSigner, *tx.To())
+	assert.Equal(t, big.NewInt(600), tx.Value())
+	from, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(testChainID), tx)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(funderKey.PublicKey), from)
+}
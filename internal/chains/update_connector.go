@@ -2,90 +2,197 @@ package chains
 
 import (
 	"context"
-	"fmt"
+	"sort"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 )
 
-func (cb *ChainBindings) UpdateConnector(ctx context.Context) error {
-	var contractName = "DVSCentralScheduler"
-	cb.logger.Info("start update connector for ", "contract", contractName)
-	noSendTxOpts, err := cb.TxMgr.GetNoSendTxOpts()
-	if err != nil {
-		return err
+type connectorUpdater func(opts *bind.TransactOpts, connector gethcommon.Address) (*gethtypes.Transaction, error)
+
+// managedConnector is a route target whose connector the emulator can take over
+type managedConnector struct {
+	address gethcommon.Address
+	update  connectorUpdater
+}
+
+// managedConnectors are the route targets with an updatable connector, by route target name
+func (cb *ChainBindings) managedConnectors() map[string]managedConnector {
+	return map[string]managedConnector{
+		"DVSCentralScheduler": {
+			gethcommon.HexToAddress(cb.ContractAddress.DVSCentralScheduler),
+			cb.RPCBindings.DVSCentralScheduler.UpdateConnector,
+		},
+		"ServiceOmniOperatorShareManager": {
+			gethcommon.HexToAddress(cb.ContractAddress.ServiceOmniOperatorSharesManager),
+			cb.RPCBindings.ServiceOmniOperatorShareManager.UpdateConnector,
+		},
+		"StakingDelegationManager": {
+			gethcommon.HexToAddress(cb.ContractAddress.StakingDelegationManager),
+			cb.RPCBindings.StakingDelegationManager.UpdateConnector,
+		},
 	}
-	tx, err := cb.RPCBindings.DVSCentralScheduler.UpdateConnector(
-		noSendTxOpts,
-		gethcommon.HexToAddress(deployerAddress),
-	)
-	if err != nil {
-		cb.logger.Error("failed to update connector for ", "contract", contractName, "error", err)
-		return errors.Wrap(err, fmt.Sprintf("failed to update connector for %s", contractName))
+}
+
+// connectorContracts returns the contracts set by WithConnectorContracts, every managed one by default
+func (cb *ChainBindings) connectorContracts() []string {
+	if cb.connectors != nil {
+		return cb.connectors
 	}
-	receipt, err := cb.TxMgr.Send(context.Background(), tx)
-	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("failed to send tx for %s", contractName))
+	var contracts []string
+	for name := range cb.managedConnectors() {
+		contracts = append(contracts, name)
 	}
+	sort.Strings(contracts)
+	return contracts
+}
+
+// UpdateConnector makes the deployer the connector of the contracts targeted by the enabled routes.
+// Contracts that already have it are skipped, the connector it replaces is recorded to the
+// connector file so RestoreConnector can hand the role back.
+func (cb *ChainBindings) UpdateConnector(ctx context.Context) error {
+	deployer := cb.deployer
+	managed := cb.managedConnectors()
+	for _, contractName := range cb.connectorContracts() {
+		target, ok := managed[contractName]
+		if !ok {
+			cb.logger.Debug("connector is not managed by the emulator, skip", "contract", contractName)
+			continue
+		}
+
+		current, err := ReadConnector(ctx, cb.RPCClient, target.address)
+		if err != nil {
+			cb.logger.Error("failed to read connector", "contract", contractName, "error", err)
+			return errors.Wrapf(err, "failed to read connector of %s", contractName)
+		}
+		if current == deployer {
+			cb.logger.Info("connector already set, skip", "contract", contractName, "connector", current.Hex())
+			continue
+		}
+
+		// recorded before the update, a failed update leaves a record that restores a no-op
+		err = cb.recordConnector(ConnectorRecord{
+			Contract:          contractName,
+			Address:           target.address.Hex(),
+			PreviousConnector: current.Hex(),
+		})
+		if err != nil {
+			cb.logger.Error("failed to record previous connector", "contract", contractName,
+				"previous", current.Hex(), "error", err)
+			return errors.Wrapf(err, "failed to record previous connector %s of %s", current.Hex(), contractName)
+		}
 
-	cb.logger.Info("update connector successfully for ",
-		"contract", contractName,
-		"txHash", receipt.TxHash.String(),
-	)
-
-	/*
-		====
-	*/
-	contractName = "ServiceOmniOperatorShareManager"
-	cb.logger.Info("start update connector for ", "contract", contractName)
-	noSendTxOpts, err = cb.TxMgr.GetNoSendTxOpts()
+		cb.logger.Info("start update connector for ", "contract", contractName, "previous", current.Hex())
+		receipt, err := cb.sendConnectorUpdate(ctx, contractName, target, deployer)
+		if err != nil {
+			return err
+		}
+
+		cb.logger.Info("update connector successfully for ",
+			"contract", contractName,
+			"txHash", receipt.TxHash.String(),
+		)
+	}
+
+	return nil
+}
+
+// RestoreConnector hands the connector role back to the connectors recorded by UpdateConnector.
+// A contract whose connector was changed by someone else since is left untouched.
+func (cb *ChainBindings) RestoreConnector(ctx context.Context) error {
+	store, err := cb.connectorStore()
 	if err != nil {
 		return err
 	}
-	tx, err = cb.RPCBindings.ServiceOmniOperatorShareManager.UpdateConnector(
-		noSendTxOpts,
-		gethcommon.HexToAddress(deployerAddress),
-	)
+	records, err := store.Load(cb.ChainID)
 	if err != nil {
-		cb.logger.Error("failed to update connector for ", "contract", contractName, "error", err)
-		return errors.Wrap(err, fmt.Sprintf("failed to update connector for %s", contractName))
+		return err
 	}
-	receipt, err = cb.TxMgr.Send(context.Background(), tx)
-	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("failed to send tx for %s", contractName))
+	if len(records) == 0 {
+		cb.logger.Info("no connector to restore", "file", cb.Config.ConnectorFile, "chainID", cb.ChainID)
+		return nil
 	}
 
-	cb.logger.Info("update connector successfully for ",
-		"contract", contractName,
-		"txHash", receipt.TxHash.String(),
-	)
-
-	/*
-		====
-	*/
-	contractName = "StakingDelegationManager"
-	cb.logger.Info("start update connector for ", "contract", contractName)
-	noSendTxOpts, err = cb.TxMgr.GetNoSendTxOpts()
+	deployer := cb.deployer
+	managed := cb.managedConnectors()
+	for _, record := range records {
+		target, ok := managed[record.Contract]
+		if !ok || target.address != gethcommon.HexToAddress(record.Address) {
+			cb.logger.Info("contract is not configured anymore, skip", "contract", record.Contract, "address", record.Address)
+			continue
+		}
+
+		previous := gethcommon.HexToAddress(record.PreviousConnector)
+		current, err := ReadConnector(ctx, cb.RPCClient, target.address)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read connector of %s", record.Contract)
+		}
+		switch current {
+		case previous:
+			cb.logger.Info("connector already restored", "contract", record.Contract, "connector", current.Hex())
+		case deployer:
+			cb.logger.Info("start restore connector for ", "contract", record.Contract, "connector", previous.Hex())
+			receipt, err := cb.sendConnectorUpdate(ctx, record.Contract, target, previous)
+			if err != nil {
+				return err
+			}
+			cb.logger.Info("restore connector successfully for ",
+				"contract", record.Contract,
+				"txHash", receipt.TxHash.String(),
+			)
+		default:
+			cb.logger.Error("connector was changed since it was updated, skip", "contract", record.Contract,
+				"connector", current.Hex(), "recorded", previous.Hex())
+			continue
+		}
+
+		if err := store.Remove(cb.ChainID, record.Address); err != nil {
+			return errors.Wrapf(err, "failed to remove the restored connector of %s", record.Contract)
+		}
+	}
+	return nil
+}
+
+func (cb *ChainBindings) sendConnectorUpdate(
+	ctx context.Context, contractName string, target managedConnector, connector gethcommon.Address,
+) (*gethtypes.Receipt, error) {
+	noSendTxOpts, err := cb.TxMgr.GetNoSendTxOpts()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	tx, err = cb.RPCBindings.StakingDelegationManager.UpdateConnector(
-		noSendTxOpts,
-		gethcommon.HexToAddress(deployerAddress),
-	)
+	tx, err := target.update(noSendTxOpts, connector)
 	if err != nil {
 		cb.logger.Error("failed to update connector for ", "contract", contractName, "error", err)
-		return errors.Wrap(err, fmt.Sprintf("failed to update connector for %s", contractName))
+		return nil, errors.Wrapf(err, "failed to update connector for %s", contractName)
 	}
-	receipt, err = cb.TxMgr.Send(context.Background(), tx)
+	receipt, err := cb.TxMgr.Send(ctx, tx)
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("failed to send tx for %s", contractName))
+		return nil, errors.Wrapf(err, "failed to send tx for %s", contractName)
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return nil, errors.Errorf("connector update of %s reverted in tx %s", contractName, receipt.TxHash.Hex())
 	}
+	return receipt, nil
+}
 
-	cb.logger.Info("update connector successfully for ",
-		"contract", contractName,
-		"txHash", receipt.TxHash.String(),
-	)
+func (cb *ChainBindings) recordConnector(record ConnectorRecord) error {
+	if cb.Config.ConnectorFile == "" {
+		cb.logger.Info("connector_file is not set, the previous connector is not recorded",
+			"contract", record.Contract, "previous", record.PreviousConnector)
+		return nil
+	}
+	store, err := cb.connectorStore()
+	if err != nil {
+		return err
+	}
+	return store.Record(cb.ChainID, record)
+}
 
-	return nil
+func (cb *ChainBindings) connectorStore() (*ConnectorStore, error) {
+	if cb.Config.ConnectorFile == "" {
+		return nil, errors.New("connector_file is not set")
+	}
+	return NewConnectorStore(cb.Config.ConnectorFile), nil
 }
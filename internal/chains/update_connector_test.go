@@ -0,0 +1,194 @@
+package chains
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+)
+
+var (
+	previousConnector = gethcommon.HexToAddress("0x00000000000000000000000000000000000e0001")
+	otherConnector    = gethcommon.HexToAddress("0x00000000000000000000000000000000000e0002")
+)
+
+// connectorConfig returns the test config with the discovered contracts pinned and the
+// connectors recorded to a temp file
+func connectorConfig(t *testing.T) *config.Config {
+	cfg := testConfig()
+	cfg.ContractAddress.PellStakeRegistryRouter = "0x00000000000000000000000000000000000C0001"
+	cfg.ContractAddress.DVSOperatorStakeManager = "0x00000000000000000000000000000000000C0002"
+	cfg.ConnectorFile = filepath.Join(t.TempDir(), "connectors.json")
+	return cfg
+}
+
+// applyConnectorUpdates makes the updateConnector txs sent on c change the connector view
+func (c *testChain) applyConnectorUpdates() {
+	c.txMgr.OnSend(func(tx *gethtypes.Transaction) (*gethtypes.Receipt, error) {
+		c.setView(tx.To().Hex(), "connector()", gethcommon.BytesToAddress(tx.Data()[4:36]))
+		return &gethtypes.Receipt{Status: gethtypes.ReceiptStatusSuccessful, TxHash: tx.Hash()}, nil
+	})
+}
+
+// setConnector sets the connector of the managed contract name of cb
+func (c *testChain) setConnector(cb *ChainBindings, name string, connector gethcommon.Address) {
+	c.setView(cb.managedConnectors()[name].address.Hex(), "connector()", connector)
+}
+
+// connector returns the connector of the managed contract name of cb
+func (c *testChain) connector(cb *ChainBindings, name string) gethcommon.Address {
+	return c.view(cb.managedConnectors()[name].address.Hex(), "connector()")
+}
+
+// recordedContracts returns the sorted contracts with a connector record in the file of cb
+func recordedContracts(t *testing.T, cb *ChainBindings) []string {
+	records, err := NewConnectorStore(cb.Config.ConnectorFile).Load(cb.ChainID)
+	require.NoError(t, err)
+	var contracts []string
+	for _, record := range records {
+		assert.Equal(t, previousConnector.Hex(), record.PreviousConnector, record.Contract)
+		contracts = append(contracts, record.Contract)
+	}
+	sort.Strings(contracts)
+	return contracts
+}
+
+func TestUpdateConnector(t *testing.T) {
+	var tests = map[string]struct {
+		// current are the connectors before the update, the previous connector otherwise
+		current     map[string]gethcommon.Address
+		contracts   []string
+		noRecord    bool
+		wantUpdated []string
+		wantRecords []string
+	}{
+		"every managed contract": {
+			wantUpdated: []string{"DVSCentralScheduler", "ServiceOmniOperatorShareManager", "StakingDelegationManager"},
+			wantRecords: []string{"DVSCentralScheduler", "ServiceOmniOperatorShareManager", "StakingDelegationManager"},
+		},
+		"already the connector": {
+			current: map[string]gethcommon.Address{
+				"DVSCentralScheduler":             testSigner,
+				"ServiceOmniOperatorShareManager": testSigner,
+			},
+			wantUpdated: []string{"StakingDelegationManager"},
+			wantRecords: []string{"StakingDelegationManager"},
+		},
+		"contracts of the routes": {
+			contracts:   []string{"PellDelegationManager", "StakingDelegationManager"},
+			wantUpdated: []string{"StakingDelegationManager"},
+			wantRecords: []string{"StakingDelegationManager"},
+		},
+		"no connector file": {
+			noRecord:    true,
+			wantUpdated: []string{"DVSCentralScheduler", "ServiceOmniOperatorShareManager", "StakingDelegationManager"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := connectorConfig(t)
+			if tt.noRecord {
+				cfg.ConnectorFile = ""
+			}
+			c := newTestChain()
+			c.applyConnectorUpdates()
+			var opts []Option
+			if tt.contracts != nil {
+				opts = append(opts, WithConnectorContracts(tt.contracts))
+			}
+			cb := c.bindings(t, cfg, opts...)
+			for contract := range cb.managedConnectors() {
+				connector, ok := tt.current[contract]
+				if !ok {
+					connector = previousConnector
+				}
+				c.setConnector(cb, contract, connector)
+			}
+
+			require.NoError(t, cb.UpdateConnector(context.Background()))
+
+			sent := c.txMgr.Sent()
+			require.Len(t, sent, len(tt.wantUpdated))
+			for i, contract := range tt.wantUpdated {
+				assert.Equal(t, cb.managedConnectors()[contract].address, *sent[i].To(), contract)
+				assert.Equal(t, testSigner, c.connector(cb, contract), contract)
+			}
+			if !tt.noRecord {
+				assert.Equal(t, tt.wantRecords, recordedContracts(t, cb))
+			}
+
+			// a second update is a no-op
+			require.NoError(t, cb.UpdateConnector(context.Background()))
+			assert.Len(t, c.txMgr.Sent(), len(sent))
+		})
+	}
+}
+
+func TestRestoreConnector(t *testing.T) {
+	var tests = map[string]struct {
+		// changed are the connectors set after the update
+		changed      map[string]gethcommon.Address
+		wantRestored []string
+		wantRecords  []string
+	}{
+		"restored": {
+			wantRestored: []string{"DVSCentralScheduler", "ServiceOmniOperatorShareManager", "StakingDelegationManager"},
+		},
+		"changed by someone else": {
+			changed:      map[string]gethcommon.Address{"StakingDelegationManager": otherConnector},
+			wantRestored: []string{"DVSCentralScheduler", "ServiceOmniOperatorShareManager"},
+			wantRecords:  []string{"StakingDelegationManager"},
+		},
+		"already restored": {
+			changed:      map[string]gethcommon.Address{"DVSCentralScheduler": previousConnector},
+			wantRestored: []string{"ServiceOmniOperatorShareManager", "StakingDelegationManager"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestChain()
+			c.applyConnectorUpdates()
+			cb := c.bindings(t, connectorConfig(t))
+			for contract := range cb.managedConnectors() {
+				c.setConnector(cb, contract, previousConnector)
+			}
+			require.NoError(t, cb.UpdateConnector(context.Background()))
+			for contract, connector := range tt.changed {
+				c.setConnector(cb, contract, connector)
+			}
+			updates := len(c.txMgr.Sent())
+
+			require.NoError(t, cb.RestoreConnector(context.Background()))
+
+			sent := c.txMgr.Sent()[updates:]
+			require.Len(t, sent, len(tt.wantRestored))
+			for _, contract := range tt.wantRestored {
+				assert.Equal(t, previousConnector, c.connector(cb, contract), contract)
+			}
+			for contract, connector := range tt.changed {
+				assert.Equal(t, connector, c.connector(cb, contract), contract)
+			}
+			assert.Equal(t, tt.wantRecords, recordedContracts(t, cb))
+
+			// nothing left to restore but the skipped contracts
+			require.NoError(t, cb.RestoreConnector(context.Background()))
+			assert.Len(t, c.txMgr.Sent(), updates+len(tt.wantRestored))
+		})
+	}
+
+	t.Run("no connector file", func(t *testing.T) {
+		cfg := connectorConfig(t)
+		cfg.ConnectorFile = ""
+		cb := newTestChain().bindings(t, cfg)
+		assert.ErrorContains(t, cb.RestoreConnector(context.Background()), "connector_file is not set")
+	})
+}
@@ -0,0 +1,472 @@
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+// Kind is the kind of state a mismatch is about
+type Kind string
+
+const (
+	// KindStakerShares are the shares of a staker in a strategy, staking vs PellStrategyManager
+	KindStakerShares Kind = "staker-shares"
+	// KindDelegation is the operator a staker is delegated to, staking vs PellDelegationManager
+	KindDelegation Kind = "delegation"
+	// KindOperatorShares are the shares of an operator in a strategy, staking vs pell and pell vs service
+	KindOperatorShares Kind = "operator-shares"
+	// KindGroupCount is the number of groups, PellRegistryRouter vs DVSCentralScheduler
+	KindGroupCount Kind = "group-count"
+	// KindOperatorWeight is the weight of an operator in a group, PellStakeRegistryRouter vs DVSOperatorStakeManager
+	KindOperatorWeight Kind = "operator-weight"
+)
+
+// Cause is what most likely happened to the forwards of the responsible route
+type Cause string
+
+const (
+	CauseLost       Cause = "lost"
+	CauseDuplicated Cause = "duplicated"
+)
+
+// routes responsible for the mirrored state, named like the routes of the events package
+const (
+	RouteDeposit                 = "Deposit"
+	RouteStakerDelegated         = "StakerDelegated"
+	RouteStakerUndelegated       = "StakerUndelegated"
+	RouteStakingWithdrawalQueued = "StakingWithdrawalQueued"
+	RouteOperatorSharesIncreased = "OperatorSharesIncreased"
+	RouteOperatorSharesDecreased = "OperatorSharesDecreased"
+	RouteSyncCreateGroup         = "SyncCreateGroup"
+	RouteSyncAddPools            = "SyncAddPools"
+)
+
+// Mismatch is a value of a mirror contract that differs from the value of its source contract
+type Mismatch struct {
+	Kind     Kind   `json:"kind"`
+	Staker   string `json:"staker,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Strategy string `json:"strategy,omitempty"`
+	Group    string `json:"group,omitempty"`
+	Source   string `json:"source"`
+	Mirror   string `json:"mirror"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	// Route is the route most likely responsible for the mismatch
+	Route string `json:"route"`
+	Cause Cause  `json:"cause"`
+}
+
+func (m Mismatch) String() string {
+	var group string
+	if m.Group != "" {
+		group = " group=" + m.Group
+	}
+	return fmt.Sprintf("%s staker=%s operator=%s strategy=%s%s: %s has %s, %s has %s, likely a %s %s forward",
+		m.Kind, m.Staker, m.Operator, m.Strategy, group, m.Source, m.Expected, m.Mirror, m.Actual, m.Cause, m.Route)
+}
+
+// Report is the result of a Check
+type Report struct {
+	Time       time.Time `json:"time"`
+	FromBlock  uint64    `json:"from_block"`
+	ToBlock    uint64    `json:"to_block"`
+	Stakers    int       `json:"stakers"`
+	Operators  int       `json:"operators"`
+	Strategies int       `json:"strategies"`
+	// Checked is the number of values compared
+	Checked    int        `json:"checked"`
+	Mismatches []Mismatch `json:"mismatches"`
+	// Unreadable are the values that could not be read and were not compared
+	Unreadable []string `json:"unreadable,omitempty"`
+}
+
+// Checker compares the state of the staking contracts with the state the emulator mirrors
+// on the pell contracts, the pell operator shares with the service contracts and the pell
+// groups and operator weights with the DVS contracts
+type Checker struct {
+	client   eth.Client
+	chainID  *big.Int
+	bindings *chains.TypesRPCBindings
+	addrs    *config.ContractAddress
+	cfg      *config.VerifyConfig
+	logger   log.Logger
+}
+
+func NewChecker(cb *chains.ChainBindings, cfg *config.VerifyConfig, logger log.Logger) *Checker {
+	if cfg == nil {
+		cfg = config.DefaultVerifyConfig()
+	}
+	addrs := cb.ContractAddress
+	if addrs == nil {
+		addrs = cb.Config.ContractAddress
+	}
+	return &Checker{
+		client:   cb.RPCClient,
+		chainID:  cb.ChainID,
+		bindings: cb.RPCBindings,
+		addrs:    addrs,
+		cfg:      cfg,
+		logger:   logger.With("module", "consistency"),
+	}
+}
+
+// Check discovers the stakers, operators and strategies from the staking logs and compares
+// every value they have on the staking chain with its mirrors
+func (c *Checker) Check(ctx context.Context) (*Report, error) {
+	head, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get block number")
+	}
+	report := &Report{Time: time.Now(), FromBlock: c.cfg.FromBlock, ToBlock: head}
+	if c.cfg.FromBlock > head {
+		return report, nil
+	}
+
+	p, err := c.discover(ctx, c.cfg.FromBlock, head)
+	if err != nil {
+		return nil, err
+	}
+	report.Stakers, report.Operators, report.Strategies = len(p.stakers), len(p.operators), len(p.strategies)
+	c.logger.Info("checking mirrored state",
+		"fromBlock", c.cfg.FromBlock, "toBlock", head,
+		"stakers", report.Stakers, "operators", report.Operators, "strategies", report.Strategies,
+	)
+
+	c.checkStakerShares(ctx, p, report)
+	delegated, undelegated := c.checkDelegations(ctx, p, report)
+	c.checkOperatorShares(ctx, p, delegated, undelegated, report)
+	c.checkOperatorWeights(ctx, p, report)
+	return report, nil
+}
+
+func (c *Checker) unreadable(report *Report, err error) {
+	c.logger.Error("Failed to read mirrored state", "error", err)
+	report.Unreadable = append(report.Unreadable, err.Error())
+}
+
+func (c *Checker) checkStakerShares(ctx context.Context, p *participants, report *Report) {
+	for _, address := range sortedAddresses(stakerSet(p)) {
+		s := p.stakers[address]
+		for _, strategy := range sortedAddresses(s.strategies) {
+			expected, err := c.callUint(ctx, "StakingStrategyManager", viewStakingStakerShares, address, strategy)
+			if err != nil {
+				c.unreadable(report, err)
+				continue
+			}
+			actual, err := c.callUint(ctx, "PellStrategyManager", viewPellStakerShares, c.chainID, address, strategy)
+			if err != nil {
+				c.unreadable(report, err)
+				continue
+			}
+			report.Checked++
+			if expected.Cmp(actual) == 0 {
+				continue
+			}
+
+			m := Mismatch{
+				Kind:     KindStakerShares,
+				Staker:   address.Hex(),
+				Strategy: strategy.Hex(),
+				Source:   "StakingStrategyManager",
+				Mirror:   "PellStrategyManager",
+				Expected: expected.String(),
+				Actual:   actual.String(),
+			}
+			m.Route, m.Cause = stakerSharesRoute(actual.Cmp(expected), s.withdrawals)
+			report.Mismatches = append(report.Mismatches, m)
+		}
+	}
+}
+
+// checkDelegations compares the delegation of every staker, it returns the operators a lost
+// delegation or undelegation is likely to have skewed the shares of
+func (c *Checker) checkDelegations(
+	ctx context.Context, p *participants, report *Report,
+) (delegated, undelegated map[gethcommon.Address]bool) {
+	delegated = make(map[gethcommon.Address]bool)
+	undelegated = make(map[gethcommon.Address]bool)
+
+	for _, address := range sortedAddresses(stakerSet(p)) {
+		expected, err := c.callAddress(ctx, "StakingDelegationManager", viewDelegatedTo, address)
+		if err != nil {
+			c.unreadable(report, err)
+			continue
+		}
+		actual, err := c.callAddress(ctx, "PellDelegationManager", viewDelegatedTo, address)
+		if err != nil {
+			c.unreadable(report, err)
+			continue
+		}
+		report.Checked++
+		if expected == actual {
+			continue
+		}
+
+		m := Mismatch{
+			Kind:     KindDelegation,
+			Staker:   address.Hex(),
+			Operator: expected.Hex(),
+			Source:   "StakingDelegationManager",
+			Mirror:   "PellDelegationManager",
+			Expected: expected.Hex(),
+			Actual:   actual.Hex(),
+			Route:    delegationRoute(expected),
+			Cause:    CauseLost,
+		}
+		if expected == (gethcommon.Address{}) {
+			m.Operator = actual.Hex()
+			undelegated[actual] = true
+		} else {
+			delegated[expected] = true
+			if actual != (gethcommon.Address{}) {
+				undelegated[actual] = true
+			}
+		}
+		report.Mismatches = append(report.Mismatches, m)
+	}
+	return delegated, undelegated
+}
+
+func (c *Checker) checkOperatorShares(
+	ctx context.Context, p *participants, delegated, undelegated map[gethcommon.Address]bool, report *Report,
+) {
+	strategies := sortedAddresses(p.strategies)
+	if len(strategies) == 0 {
+		return
+	}
+	omniStrategies := make([]omniStrategy, len(strategies))
+	for i, strategy := range strategies {
+		omniStrategies[i] = omniStrategy{ChainId: c.chainID, Strategy: strategy}
+	}
+
+	for _, operator := range sortedAddresses(p.operators) {
+		staking, err := c.callUints(ctx, "StakingDelegationManager", viewStakingOperatorShares, len(strategies),
+			operator, strategies)
+		if err != nil {
+			c.unreadable(report, err)
+			continue
+		}
+		pell, err := c.callUints(ctx, "PellDelegationManager", viewOmniOperatorShares, len(strategies),
+			operator, omniStrategies)
+		if err != nil {
+			c.unreadable(report, err)
+			continue
+		}
+		service, serviceErr := c.callUints(ctx, "ServiceOmniOperatorSharesManager", viewOmniOperatorShares,
+			len(strategies), operator, omniStrategies)
+		if serviceErr != nil {
+			c.unreadable(report, serviceErr)
+		}
+
+		for i, strategy := range strategies {
+			report.Checked++
+			if cmp := pell[i].Cmp(staking[i]); cmp != 0 {
+				m := c.operatorSharesMismatch(operator, strategy, "StakingDelegationManager", "PellDelegationManager",
+					staking[i], pell[i])
+				m.Route = pellOperatorSharesRoute(cmp, delegated[operator], undelegated[operator])
+				report.Mismatches = append(report.Mismatches, m)
+			}
+
+			if serviceErr != nil {
+				continue
+			}
+			report.Checked++
+			if cmp := service[i].Cmp(pell[i]); cmp != 0 {
+				m := c.operatorSharesMismatch(operator, strategy, "PellDelegationManager", "ServiceOmniOperatorSharesManager",
+					pell[i], service[i])
+				m.Route = serviceOperatorSharesRoute(cmp)
+				report.Mismatches = append(report.Mismatches, m)
+			}
+		}
+	}
+}
+
+// checkOperatorWeights compares the groups of the pell registry with the DVS, then the weight
+// of every operator in each group. The DVS weights are computed from the service operator
+// shares and the pools of the group, a weight is attributed to the operator shares mismatch
+// of the operator if there is one, to a lost pool sync otherwise.
+func (c *Checker) checkOperatorWeights(ctx context.Context, p *participants, report *Report) {
+	pellGroups, err := c.callUint8(ctx, "PellRegistryRouter", viewGroupCount)
+	if err != nil {
+		c.unreadable(report, err)
+		return
+	}
+	dvsGroups, err := c.callUint8(ctx, "DVSCentralScheduler", viewGroupCount)
+	if err != nil {
+		c.unreadable(report, err)
+		return
+	}
+	report.Checked++
+	if pellGroups != dvsGroups {
+		cmp := 1
+		if dvsGroups < pellGroups {
+			cmp = -1
+		}
+		report.Mismatches = append(report.Mismatches, Mismatch{
+			Kind:     KindGroupCount,
+			Source:   "PellRegistryRouter",
+			Mirror:   "DVSCentralScheduler",
+			Expected: fmt.Sprint(pellGroups),
+			Actual:   fmt.Sprint(dvsGroups),
+			Route:    RouteSyncCreateGroup,
+			Cause:    countCause(cmp),
+		})
+	}
+
+	groups := min(pellGroups, dvsGroups)
+	for _, operator := range sortedAddresses(p.operators) {
+		route := operatorWeightRoute(operator.Hex(), report.Mismatches)
+		for group := uint8(0); group < groups; group++ {
+			expected, err := c.callUint(ctx, "PellStakeRegistryRouter", viewOperatorWeight, group, operator)
+			if err != nil {
+				c.unreadable(report, err)
+				continue
+			}
+			actual, err := c.callUint(ctx, "DVSOperatorStakeManager", viewOperatorWeight, group, operator)
+			if err != nil {
+				c.unreadable(report, err)
+				continue
+			}
+			report.Checked++
+			if expected.Cmp(actual) == 0 {
+				continue
+			}
+			report.Mismatches = append(report.Mismatches, Mismatch{
+				Kind:     KindOperatorWeight,
+				Operator: operator.Hex(),
+				Group:    fmt.Sprint(group),
+				Source:   "PellStakeRegistryRouter",
+				Mirror:   "DVSOperatorStakeManager",
+				Expected: expected.String(),
+				Actual:   actual.String(),
+				Route:    route,
+				Cause:    CauseLost,
+			})
+		}
+	}
+}
+
+// stakerSharesRoute attributes a staker shares mismatch, cmp compares the mirror with the source.
+// An excess is a duplicated deposit, unless the staker queued withdrawals that were not mirrored.
+func stakerSharesRoute(cmp int, withdrawals int) (string, Cause) {
+	switch {
+	case cmp < 0:
+		return RouteDeposit, CauseLost
+	case withdrawals > 0:
+		return RouteStakingWithdrawalQueued, CauseLost
+	default:
+		return RouteDeposit, CauseDuplicated
+	}
+}
+
+// delegationRoute attributes a delegation mismatch to the route that should have set expected
+func delegationRoute(expected gethcommon.Address) string {
+	if expected == (gethcommon.Address{}) {
+		return RouteStakerUndelegated
+	}
+	return RouteStakerDelegated
+}
+
+// pellOperatorSharesRoute attributes a pell operator shares mismatch, cmp compares the mirror with
+// the source. A delegation mismatch of a staker of the operator is the likely cause if there is one.
+func pellOperatorSharesRoute(cmp int, delegated, undelegated bool) string {
+	switch {
+	case cmp < 0 && delegated:
+		return RouteStakerDelegated
+	case cmp < 0:
+		return RouteDeposit
+	case undelegated:
+		return RouteStakerUndelegated
+	default:
+		return RouteStakingWithdrawalQueued
+	}
+}
+
+// serviceOperatorSharesRoute attributes a service operator shares mismatch, cmp compares the mirror with the source
+func serviceOperatorSharesRoute(cmp int) string {
+	if cmp > 0 {
+		return RouteOperatorSharesDecreased
+	}
+	return RouteOperatorSharesIncreased
+}
+
+// operatorWeightRoute attributes the weight mismatches of operator to the route of its first
+// operator shares mismatch, a weight off with matching shares comes from the pools of the group
+func operatorWeightRoute(operator string, mismatches []Mismatch) string {
+	for _, m := range mismatches {
+		if m.Kind == KindOperatorShares && m.Operator == operator {
+			return m.Route
+		}
+	}
+	return RouteSyncAddPools
+}
+
+// countCause is the cause of a count mismatch, cmp compares the mirror with the source
+func countCause(cmp int) Cause {
+	if cmp > 0 {
+		return CauseDuplicated
+	}
+	return CauseLost
+}
+
+func (c *Checker) operatorSharesMismatch(
+	operator, strategy gethcommon.Address, source, mirror string, expected, actual *big.Int,
+) Mismatch {
+	return Mismatch{
+		Kind:     KindOperatorShares,
+		Operator: operator.Hex(),
+		Strategy: strategy.Hex(),
+		Source:   source,
+		Mirror:   mirror,
+		Expected: expected.String(),
+		Actual:   actual.String(),
+		Cause:    CauseLost,
+	}
+}
+
+func (c *Checker) address(contract string) (gethcommon.Address, error) {
+	var address string
+	switch contract {
+	case "StakingStrategyManager":
+		address = c.addrs.StakingStrategyManager
+	case "StakingDelegationManager":
+		address = c.addrs.StakingDelegationManager
+	case "PellStrategyManager":
+		address = c.addrs.PellStrategyManager
+	case "PellDelegationManager":
+		address = c.addrs.PellDelegationManager
+	case "ServiceOmniOperatorSharesManager":
+		address = c.addrs.ServiceOmniOperatorSharesManager
+	case "PellRegistryRouter":
+		address = c.addrs.PellRegistryRouter
+	case "PellStakeRegistryRouter":
+		address = c.addrs.PellStakeRegistryRouter
+	case "DVSCentralScheduler":
+		address = c.addrs.DVSCentralScheduler
+	case "DVSOperatorStakeManager":
+		address = c.addrs.DVSOperatorStakeManager
+	}
+	if !gethcommon.IsHexAddress(address) {
+		return gethcommon.Address{}, errors.Errorf("no address configured for %s", contract)
+	}
+	return gethcommon.HexToAddress(address), nil
+}
+
+func stakerSet(p *participants) map[gethcommon.Address]bool {
+	set := make(map[gethcommon.Address]bool, len(p.stakers))
+	for address := range p.stakers {
+		set[address] = true
+	}
+	return set
+}
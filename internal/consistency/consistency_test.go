@@ -0,0 +1,147 @@
+package consistency
+
+import (
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStakerSharesRoute(t *testing.T) {
+	var tests = map[string]struct {
+		cmp         int
+		withdrawals int
+		wantRoute   string
+		wantCause   Cause
+	}{
+		"shortfall":                    {cmp: -1, wantRoute: RouteDeposit, wantCause: CauseLost},
+		"shortfall with withdrawals":   {cmp: -1, withdrawals: 1, wantRoute: RouteDeposit, wantCause: CauseLost},
+		"excess":                       {cmp: 1, wantRoute: RouteDeposit, wantCause: CauseDuplicated},
+		"excess after a withdrawal":    {cmp: 1, withdrawals: 1, wantRoute: RouteStakingWithdrawalQueued, wantCause: CauseLost},
+		"excess after two withdrawals": {cmp: 1, withdrawals: 2, wantRoute: RouteStakingWithdrawalQueued, wantCause: CauseLost},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			route, cause := stakerSharesRoute(tt.cmp, tt.withdrawals)
+			assert.Equal(t, tt.wantRoute, route)
+			assert.Equal(t, tt.wantCause, cause)
+		})
+	}
+}
+
+func TestDelegationRoute(t *testing.T) {
+	assert.Equal(t, RouteStakerUndelegated, delegationRoute(gethcommon.Address{}))
+	assert.Equal(t, RouteStakerDelegated, delegationRoute(gethcommon.HexToAddress("0x01")))
+}
+
+func TestPellOperatorSharesRoute(t *testing.T) {
+	var tests = map[string]struct {
+		cmp         int
+		delegated   bool
+		undelegated bool
+		want        string
+	}{
+		"shortfall":                           {cmp: -1, want: RouteDeposit},
+		"shortfall of a lost delegation":      {cmp: -1, delegated: true, want: RouteStakerDelegated},
+		"shortfall of a lost undelegation":    {cmp: -1, undelegated: true, want: RouteDeposit},
+		"excess":                              {cmp: 1, want: RouteStakingWithdrawalQueued},
+		"excess of a lost undelegation":       {cmp: 1, undelegated: true, want: RouteStakerUndelegated},
+		"excess of a delegation to another":   {cmp: 1, delegated: true, want: RouteStakingWithdrawalQueued},
+		"excess of a redelegation not synced": {cmp: 1, delegated: true, undelegated: true, want: RouteStakerUndelegated},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pellOperatorSharesRoute(tt.cmp, tt.delegated, tt.undelegated))
+		})
+	}
+}
+
+func TestServiceOperatorSharesRoute(t *testing.T) {
+	assert.Equal(t, RouteOperatorSharesIncreased, serviceOperatorSharesRoute(-1))
+	assert.Equal(t, RouteOperatorSharesDecreased, serviceOperatorSharesRoute(1))
+}
+
+func TestOperatorWeightRoute(t *testing.T) {
+	operator := gethcommon.HexToAddress("0x01").Hex()
+	other := gethcommon.HexToAddress("0x02").Hex()
+
+	var tests = map[string]struct {
+		mismatches []Mismatch
+		want       string
+	}{
+		"no mismatch": {
+			want: RouteSyncAddPools,
+		},
+		"shares of another operator": {
+			mismatches: []Mismatch{
+				{Kind: KindOperatorShares, Operator: other, Route: RouteOperatorSharesIncreased},
+			},
+			want: RouteSyncAddPools,
+		},
+		"delegation of the operator": {
+			mismatches: []Mismatch{
+				{Kind: KindDelegation, Operator: operator, Route: RouteStakerDelegated},
+			},
+			want: RouteSyncAddPools,
+		},
+		"service shares of the operator": {
+			mismatches: []Mismatch{
+				{Kind: KindOperatorShares, Operator: other, Route: RouteOperatorSharesIncreased},
+				{Kind: KindOperatorShares, Operator: operator, Route: RouteOperatorSharesDecreased},
+			},
+			want: RouteOperatorSharesDecreased,
+		},
+		"pell shares first": {
+			mismatches: []Mismatch{
+				{Kind: KindOperatorShares, Operator: operator, Route: RouteDeposit},
+				{Kind: KindOperatorShares, Operator: operator, Route: RouteOperatorSharesIncreased},
+			},
+			want: RouteDeposit,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, operatorWeightRoute(operator, tt.mismatches))
+		})
+	}
+}
+
+func TestCountCause(t *testing.T) {
+	assert.Equal(t, CauseLost, countCause(-1))
+	assert.Equal(t, CauseDuplicated, countCause(1))
+}
+
+func TestMismatchString(t *testing.T) {
+	var tests = map[string]struct {
+		mismatch Mismatch
+		want     string
+	}{
+		"staker shares": {
+			mismatch: Mismatch{
+				Kind: KindStakerShares, Staker: "0xA", Strategy: "0xS",
+				Source: "StakingStrategyManager", Mirror: "PellStrategyManager", Expected: "10", Actual: "5",
+				Route: RouteDeposit, Cause: CauseLost,
+			},
+			want: "staker-shares staker=0xA operator= strategy=0xS: StakingStrategyManager has 10, " +
+				"PellStrategyManager has 5, likely a lost Deposit forward",
+		},
+		"operator weight": {
+			mismatch: Mismatch{
+				Kind: KindOperatorWeight, Operator: "0xO", Group: "1",
+				Source: "PellStakeRegistryRouter", Mirror: "DVSOperatorStakeManager", Expected: "10", Actual: "0",
+				Route: RouteSyncAddPools, Cause: CauseLost,
+			},
+			want: "operator-weight staker= operator=0xO strategy= group=1: PellStakeRegistryRouter has 10, " +
+				"DVSOperatorStakeManager has 0, likely a lost SyncAddPools forward",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.mismatch.String())
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package consistency
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// participants are the stakers, operators and strategies seen in the logs of the staking contracts
+type participants struct {
+	stakers    map[gethcommon.Address]*staker
+	operators  map[gethcommon.Address]bool
+	strategies map[gethcommon.Address]bool
+}
+
+type staker struct {
+	strategies map[gethcommon.Address]bool
+	// withdrawals is the number of withdrawals queued by the staker
+	withdrawals int
+}
+
+func newParticipants() *participants {
+	return &participants{
+		stakers:    make(map[gethcommon.Address]*staker),
+		operators:  make(map[gethcommon.Address]bool),
+		strategies: make(map[gethcommon.Address]bool),
+	}
+}
+
+func (p *participants) staker(address gethcommon.Address) *staker {
+	s, ok := p.stakers[address]
+	if !ok {
+		s = &staker{strategies: make(map[gethcommon.Address]bool)}
+		p.stakers[address] = s
+	}
+	return s
+}
+
+// discover scans the Deposit, StakerDelegated, StakerUndelegated and WithdrawalQueued logs
+// of the staking contracts from fromBlock to toBlock, blockRange blocks per query
+func (c *Checker) discover(ctx context.Context, fromBlock, toBlock uint64) (*participants, error) {
+	p := newParticipants()
+	blockRange := c.cfg.BlockRange
+	if blockRange == 0 {
+		blockRange = 5000
+	}
+
+	for start := fromBlock; start <= toBlock; start += blockRange {
+		end := min(start+blockRange-1, toBlock)
+		opts := &bind.FilterOpts{Start: start, End: &end, Context: ctx}
+		if err := c.discoverRange(opts, p); err != nil {
+			c.logger.Error("Failed to scan staking logs", "error", err, "startBlock", start, "toBlock", end)
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (c *Checker) discoverRange(opts *bind.FilterOpts, p *participants) error {
+	deposits, err := c.bindings.StakingStrategyManager.FilterDeposit(opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter Deposit")
+	}
+	for deposits.Next() {
+		p.staker(deposits.Event.Staker).strategies[deposits.Event.Strategy] = true
+		p.strategies[deposits.Event.Strategy] = true
+	}
+	if err := closeIterator(deposits.Error(), deposits.Close()); err != nil {
+		return errors.Wrap(err, "failed to read Deposit")
+	}
+
+	delegated, err := c.bindings.StakingDelegationManager.FilterStakerDelegated(opts, nil, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter StakerDelegated")
+	}
+	for delegated.Next() {
+		p.staker(delegated.Event.Staker)
+		p.operators[delegated.Event.Operator] = true
+	}
+	if err := closeIterator(delegated.Error(), delegated.Close()); err != nil {
+		return errors.Wrap(err, "failed to read StakerDelegated")
+	}
+
+	undelegated, err := c.bindings.StakingDelegationManager.FilterStakerUndelegated(opts, nil, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter StakerUndelegated")
+	}
+	for undelegated.Next() {
+		p.staker(undelegated.Event.Staker)
+		p.operators[undelegated.Event.Operator] = true
+	}
+	if err := closeIterator(undelegated.Error(), undelegated.Close()); err != nil {
+		return errors.Wrap(err, "failed to read StakerUndelegated")
+	}
+
+	withdrawals, err := c.bindings.StakingDelegationManager.FilterWithdrawalQueued(opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter WithdrawalQueued")
+	}
+	for withdrawals.Next() {
+		s := p.staker(withdrawals.Event.Withdrawal.Staker)
+		s.withdrawals++
+		for _, strategy := range withdrawals.Event.Withdrawal.Strategies {
+			s.strategies[strategy] = true
+			p.strategies[strategy] = true
+		}
+	}
+	return errors.Wrap(closeIterator(withdrawals.Error(), withdrawals.Close()), "failed to read WithdrawalQueued")
+}
+
+func closeIterator(iterErr, closeErr error) error {
+	if iterErr != nil {
+		return iterErr
+	}
+	return closeErr
+}
+
+func sortedAddresses(set map[gethcommon.Address]bool) []gethcommon.Address {
+	res := make([]gethcommon.Address, 0, len(set))
+	for address := range set {
+		res = append(res, address)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Cmp(res[j]) < 0 })
+	return res
+}
@@ -0,0 +1,358 @@
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/pelldelegationmanager.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+// CorrectionStatus is what the reconciler did with a correction
+type CorrectionStatus string
+
+const (
+	// CorrectionPlanned the call was not sent, dry run
+	CorrectionPlanned CorrectionStatus = "planned"
+	// CorrectionSent the call was included
+	CorrectionSent CorrectionStatus = "sent"
+	// CorrectionSkipped the call reverted in simulation or the mismatch has no corrective call
+	CorrectionSkipped CorrectionStatus = "skipped"
+	// CorrectionFailed the call could not be simulated or sent
+	CorrectionFailed CorrectionStatus = "failed"
+	// CorrectionDeferred the operator shares are corrected once the staker state matches
+	CorrectionDeferred CorrectionStatus = "deferred"
+	// CorrectionRateLimited the call was over a rate limit, it is planned again by the next check
+	CorrectionRateLimited CorrectionStatus = "rate-limited"
+)
+
+type buildTxFunc func(opts *bind.TransactOpts) (*gethtypes.Transaction, error)
+
+// Correction is a sync call that corrects a mismatch, a mismatch may need more than one
+type Correction struct {
+	Mismatch Mismatch         `json:"mismatch"`
+	Target   string           `json:"target"`
+	Args     string           `json:"args"`
+	Status   CorrectionStatus `json:"status"`
+	TxHash   string           `json:"tx_hash,omitempty"`
+	Reason   string           `json:"reason,omitempty"`
+
+	build buildTxFunc
+}
+
+// Reconciler sends the sync calls that bring the mirrored state back in line with the staking chain.
+// Calls go through the tx manager of the bindings like forwards, and are journaled as reconciliations.
+type Reconciler struct {
+	cfg     *config.ReconcileConfig
+	journal journal.Journal
+	logger  log.Logger
+
+	// mu guards the rate limit state shared by the runs
+	mu       sync.Mutex
+	sent     []time.Time
+	lastSent time.Time
+}
+
+func NewReconciler(cfg *config.ReconcileConfig, jn journal.Journal, logger log.Logger) *Reconciler {
+	if cfg == nil {
+		cfg = config.DefaultReconcileConfig()
+	}
+	if jn == nil {
+		jn = journal.NewNopJournal()
+	}
+	return &Reconciler{
+		cfg:     cfg,
+		journal: jn,
+		logger:  logger.With("module", "reconciler"),
+	}
+}
+
+// Plan returns the corrections of the mismatches of report, in the order they must be sent.
+// Operator shares are deferred while a staker mismatch is left, since correcting the stakers
+// also moves the shares of their operators.
+func (r *Reconciler) Plan(ctx context.Context, cb *chains.ChainBindings, report *Report) []*Correction {
+	checker := NewChecker(cb, nil, r.logger)
+	deferOperators := false
+	for _, m := range report.Mismatches {
+		if m.Kind == KindStakerShares || m.Kind == KindDelegation {
+			deferOperators = true
+		}
+	}
+
+	var corrections []*Correction
+	for _, m := range report.Mismatches {
+		switch {
+		case m.Kind == KindOperatorShares && deferOperators:
+			corrections = append(corrections, &Correction{Mismatch: m, Status: CorrectionDeferred,
+				Reason: "staker mismatches are corrected first"})
+		default:
+			corrections = append(corrections, r.plan(ctx, checker, cb, m)...)
+		}
+	}
+	return corrections
+}
+
+func (r *Reconciler) plan(ctx context.Context, checker *Checker, cb *chains.ChainBindings, m Mismatch) []*Correction {
+	chainID := cb.ChainID
+	staker := gethcommon.HexToAddress(m.Staker)
+	operator := gethcommon.HexToAddress(m.Operator)
+	strategy := gethcommon.HexToAddress(m.Strategy)
+	bindings := cb.RPCBindings
+
+	switch m.Kind {
+	case KindStakerShares:
+		delta, increase, err := shareDelta(m)
+		if err != nil {
+			return []*Correction{skipped(m, err.Error())}
+		}
+		if increase {
+			return []*Correction{{
+				Mismatch: m,
+				Target:   "PellStrategyManager.SyncDepositState",
+				Args:     fmt.Sprintf("chainId=%s staker=%s strategy=%s shares=%s", chainID, m.Staker, m.Strategy, delta),
+				build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+					return bindings.PellStrategyManager.SyncDepositState(opts, chainID, staker, strategy, delta)
+				},
+			}}
+		}
+		// the withdrawal is taken from the operator pell has the staker delegated to
+		delegatedTo, err := checker.callAddress(ctx, "PellDelegationManager", viewDelegatedTo, staker)
+		if err != nil {
+			return []*Correction{skipped(m, err.Error())}
+		}
+		params := pelldelegationmanager.IPellDelegationManagerWithdrawalParams{
+			Strategies: []gethcommon.Address{strategy},
+			Shares:     []*big.Int{delta},
+		}
+		return []*Correction{{
+			Mismatch: m,
+			Target:   "PellDelegationManager.SyncWithdrawalState",
+			Args: fmt.Sprintf("chainId=%s staker=%s operator=%s strategy=%s shares=%s",
+				chainID, m.Staker, delegatedTo.Hex(), m.Strategy, delta),
+			build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+				return bindings.PellDelegationManager.SyncWithdrawalState(opts, chainID, staker, delegatedTo, params)
+			},
+		}}
+
+	case KindDelegation:
+		expected := gethcommon.HexToAddress(m.Expected)
+		actual := gethcommon.HexToAddress(m.Actual)
+		var corrections []*Correction
+		if actual != (gethcommon.Address{}) {
+			corrections = append(corrections, &Correction{
+				Mismatch: m,
+				Target:   "PellDelegationManager.SyncUndelegateState",
+				Args:     fmt.Sprintf("chainId=%s staker=%s", chainID, m.Staker),
+				build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+					return bindings.PellDelegationManager.SyncUndelegateState(opts, chainID, staker)
+				},
+			})
+		}
+		if expected != (gethcommon.Address{}) {
+			corrections = append(corrections, &Correction{
+				Mismatch: m,
+				Target:   "PellDelegationManager.SyncDelegateState",
+				Args:     fmt.Sprintf("chainId=%s staker=%s operator=%s", chainID, m.Staker, m.Expected),
+				build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+					return bindings.PellDelegationManager.SyncDelegateState(opts, chainID, staker, expected)
+				},
+			})
+		}
+		return corrections
+
+	case KindOperatorShares:
+		if m.Mirror != "ServiceOmniOperatorSharesManager" {
+			return []*Correction{skipped(m, "pell operator shares follow the stakers, there is no call to sync them")}
+		}
+		delta, increase, err := shareDelta(m)
+		if err != nil {
+			return []*Correction{skipped(m, err.Error())}
+		}
+		args := fmt.Sprintf("chainIds=[%s] operators=[%s] strategies=[%s] shares=[%s]", chainID, m.Operator, m.Strategy, delta)
+		if increase {
+			return []*Correction{{
+				Mismatch: m,
+				Target:   "ServiceOmniOperatorShareManager.BatchSyncIncreaseDelegatedShares",
+				Args:     args,
+				build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+					return bindings.ServiceOmniOperatorShareManager.BatchSyncIncreaseDelegatedShares(opts,
+						[]*big.Int{chainID}, []gethcommon.Address{operator}, []gethcommon.Address{strategy}, []*big.Int{delta})
+				},
+			}}
+		}
+		return []*Correction{{
+			Mismatch: m,
+			Target:   "ServiceOmniOperatorShareManager.BatchSyncDecreaseDelegatedShares",
+			Args:     args,
+			build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+				return bindings.ServiceOmniOperatorShareManager.BatchSyncDecreaseDelegatedShares(opts,
+					[]*big.Int{chainID}, []gethcommon.Address{operator}, []gethcommon.Address{strategy}, []*big.Int{delta})
+			},
+		}}
+
+	case KindGroupCount:
+		return []*Correction{skipped(m, "groups are created with their config, which is not on chain to resend")}
+
+	case KindOperatorWeight:
+		return []*Correction{skipped(m, "DVS weights follow the service operator shares and the pools of the group")}
+	}
+	return []*Correction{skipped(m, "unknown mismatch kind")}
+}
+
+// Reconcile plans the corrections of report and sends them within the rate limits, in dry run
+// they are only logged. Every correction sent or attempted is journaled as a reconciliation.
+func (r *Reconciler) Reconcile(ctx context.Context, cb *chains.ChainBindings, report *Report) []*Correction {
+	corrections := r.Plan(ctx, cb, report)
+	sentThisRun := 0
+	for _, c := range corrections {
+		if c.Status != "" {
+			r.logger.Info("mismatch not corrected", "mismatch", c.Mismatch.String(), "status", c.Status, "reason", c.Reason)
+			continue
+		}
+		lg := r.logger.With("target", c.Target, "args", c.Args)
+
+		if r.cfg.DryRun {
+			c.Status = CorrectionPlanned
+			lg.Info("dry run, corrective call not sent", "mismatch", c.Mismatch.String())
+			continue
+		}
+		if reason := r.rateLimited(sentThisRun); reason != "" {
+			c.Status, c.Reason = CorrectionRateLimited, reason
+			lg.Info("corrective call rate limited", "reason", reason)
+			continue
+		}
+		if err := r.waitCallInterval(ctx); err != nil {
+			c.Status, c.Reason = CorrectionFailed, err.Error()
+			continue
+		}
+
+		sentThisRun++
+		r.send(ctx, cb, c)
+		lg.Info("corrective call done", "status", c.Status, "txHash", c.TxHash, "reason", c.Reason)
+	}
+	return corrections
+}
+
+// send simulates the call of c and sends it through the tx manager of cb
+func (r *Reconciler) send(ctx context.Context, cb *chains.ChainBindings, c *Correction) {
+	r.markSent()
+	entry := journal.Entry{
+		Route:          c.Mismatch.Route,
+		Target:         c.Target,
+		Reconciliation: true,
+	}
+
+	tx, sim := simulate(ctx, cb, c.build)
+	switch sim.Status {
+	case txmgr.SimulationReverted:
+		c.Status, c.Reason = CorrectionSkipped, "reverted in simulation: "+sim.Reason
+	case txmgr.SimulationTransient:
+		c.Status, c.Reason = CorrectionFailed, errors.Wrap(sim.Err, "failed to simulate tx").Error()
+	default:
+		receipt, err := cb.TxMgr.Send(ctx, tx)
+		if err != nil {
+			c.Status, c.Reason = CorrectionFailed, errors.Wrap(err, "failed to send tx").Error()
+			break
+		}
+		c.Status, c.TxHash = CorrectionSent, receipt.TxHash.Hex()
+	}
+
+	switch c.Status {
+	case CorrectionSent:
+		entry.Status = journal.StatusForwarded
+		entry.TargetTxHash = c.TxHash
+		entry.Reason = c.Mismatch.String()
+	case CorrectionSkipped:
+		entry.Status = journal.StatusSkipped
+		entry.Reason = c.Mismatch.String() + ": " + c.Reason
+	default:
+		entry.Status = journal.StatusFailed
+		entry.Reason = c.Mismatch.String() + ": " + c.Reason
+	}
+	if err := r.journal.Record(entry); err != nil {
+		r.logger.Error("failed to record journal entry", "error", err)
+	}
+}
+
+func simulate(ctx context.Context, cb *chains.ChainBindings, build buildTxFunc) (*gethtypes.Transaction, *txmgr.SimulationResult) {
+	opts, err := cb.TxMgr.GetNoSendTxOpts()
+	if err != nil {
+		return nil, &txmgr.SimulationResult{Status: txmgr.SimulationTransient, Err: err}
+	}
+	opts.Context = ctx
+	tx, err := build(opts)
+	if err != nil {
+		return nil, txmgr.ClassifyCallError(err)
+	}
+	return tx, txmgr.Simulate(ctx, cb.RPCClient, opts.From, tx)
+}
+
+// rateLimited returns why another call can not be sent, empty if it can
+func (r *Reconciler) rateLimited(sentThisRun int) string {
+	if r.cfg.MaxCallsPerRun > 0 && sentThisRun >= r.cfg.MaxCallsPerRun {
+		return fmt.Sprintf("max %d calls per run", r.cfg.MaxCallsPerRun)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cutoff := time.Now().Add(-time.Hour)
+	for len(r.sent) > 0 && r.sent[0].Before(cutoff) {
+		r.sent = r.sent[1:]
+	}
+	if r.cfg.MaxCallsPerHour > 0 && len(r.sent) >= r.cfg.MaxCallsPerHour {
+		return fmt.Sprintf("max %d calls per hour", r.cfg.MaxCallsPerHour)
+	}
+	return ""
+}
+
+func (r *Reconciler) waitCallInterval(ctx context.Context) error {
+	r.mu.Lock()
+	wait := time.Until(r.lastSent.Add(time.Duration(r.cfg.MinCallIntervalSeconds) * time.Second))
+	r.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+func (r *Reconciler) markSent() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastSent = time.Now()
+	r.sent = append(r.sent, r.lastSent)
+}
+
+// shareDelta returns how many shares the mirror of m is off, and whether they must be added
+func shareDelta(m Mismatch) (*big.Int, bool, error) {
+	expected, ok := new(big.Int).SetString(m.Expected, 10)
+	if !ok {
+		return nil, false, errors.Errorf("invalid expected shares %q", m.Expected)
+	}
+	actual, ok := new(big.Int).SetString(m.Actual, 10)
+	if !ok {
+		return nil, false, errors.Errorf("invalid actual shares %q", m.Actual)
+	}
+	delta := new(big.Int).Sub(expected, actual)
+	return delta.Abs(delta), expected.Cmp(actual) > 0, nil
+}
+
+func skipped(m Mismatch, reason string) *Correction {
+	return &Correction{Mismatch: m, Status: CorrectionSkipped, Reason: reason}
+}
@@ -0,0 +1,360 @@
+package consistency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var (
+	testChainID  = big.NewInt(1337)
+	testSender   = gethcommon.HexToAddress("0x00000000000000000000000000000000000f0001")
+	testStaker   = gethcommon.HexToAddress("0x00000000000000000000000000000000000b0001")
+	testOperator = gethcommon.HexToAddress("0x00000000000000000000000000000000000b0002")
+	testStrategy = gethcommon.HexToAddress("0x00000000000000000000000000000000000b0004")
+)
+
+type memJournal struct {
+	mu      sync.Mutex
+	entries []journal.Entry
+}
+
+func (j *memJournal) Record(entry journal.Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+func (j *memJournal) Entries() []journal.Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]journal.Entry(nil), j.entries...)
+}
+
+type testChain struct {
+	client   *ethtest.Client
+	txMgr    *ethtest.TxManager
+	bindings *chains.ChainBindings
+}
+
+// newTestChain returns bindings of a fake chain whose PellDelegationManager has the staker
+// delegated to the operator, and whose calls succeed otherwise
+func newTestChain(t *testing.T) *testChain {
+	client := ethtest.NewClient(testChainID)
+	txMgr := ethtest.NewTxManager(testSender)
+	address := config.DefaultContractAddress()
+	rpcBindings, err := chains.NewRPCBindings(client, address, log.NewNopLogger())
+	require.NoError(t, err)
+
+	delegatedTo := viewDelegatedTo.abi.Methods["delegatedTo"]
+	client.OnCall(gethcommon.HexToAddress(address.PellDelegationManager), func(msg ethereum.CallMsg) ([]byte, error) {
+		if bytes.HasPrefix(msg.Data, delegatedTo.ID) {
+			return delegatedTo.Outputs.Pack(testOperator)
+		}
+		return nil, nil
+	})
+
+	return &testChain{
+		client: client,
+		txMgr:  txMgr,
+		bindings: &chains.ChainBindings{
+			RPCClient:       client,
+			RPCBindings:     rpcBindings,
+			TxMgr:           txMgr,
+			ChainID:         testChainID,
+			Config:          &config.Config{ContractAddress: address},
+			ContractAddress: address,
+		},
+	}
+}
+
+func stakerSharesMismatch(expected, actual string) Mismatch {
+	return Mismatch{
+		Kind: KindStakerShares, Staker: testStaker.Hex(), Strategy: testStrategy.Hex(),
+		Source: "StakingStrategyManager", Mirror: "PellStrategyManager",
+		Expected: expected, Actual: actual, Route: RouteDeposit, Cause: CauseLost,
+	}
+}
+
+func delegationMismatch(expected, actual gethcommon.Address) Mismatch {
+	return Mismatch{
+		Kind: KindDelegation, Staker: testStaker.Hex(), Operator: expected.Hex(),
+		Source: "StakingDelegationManager", Mirror: "PellDelegationManager",
+		Expected: expected.Hex(), Actual: actual.Hex(), Route: delegationRoute(expected), Cause: CauseLost,
+	}
+}
+
+func operatorSharesMismatch(mirror, expected, actual string) Mismatch {
+	return Mismatch{
+		Kind: KindOperatorShares, Operator: testOperator.Hex(), Strategy: testStrategy.Hex(),
+		Source: "PellDelegationManager", Mirror: mirror,
+		Expected: expected, Actual: actual, Route: RouteOperatorSharesIncreased, Cause: CauseLost,
+	}
+}
+
+// plannedCorrection is the part of a correction the plan tests compare
+type plannedCorrection struct {
+	target string
+	status CorrectionStatus
+}
+
+func TestReconcilerPlan(t *testing.T) {
+	var tests = map[string]struct {
+		mismatches []Mismatch
+		want       []plannedCorrection
+	}{
+		"staker share shortfall": {
+			mismatches: []Mismatch{stakerSharesMismatch("10", "4")},
+			want:       []plannedCorrection{{target: "PellStrategyManager.SyncDepositState"}},
+		},
+		"staker share excess": {
+			mismatches: []Mismatch{stakerSharesMismatch("4", "10")},
+			want:       []plannedCorrection{{target: "PellDelegationManager.SyncWithdrawalState"}},
+		},
+		"invalid shares": {
+			mismatches: []Mismatch{stakerSharesMismatch("4", "ten")},
+			want:       []plannedCorrection{{status: CorrectionSkipped}},
+		},
+		"lost delegation": {
+			mismatches: []Mismatch{delegationMismatch(testOperator, gethcommon.Address{})},
+			want:       []plannedCorrection{{target: "PellDelegationManager.SyncDelegateState"}},
+		},
+		"lost undelegation": {
+			mismatches: []Mismatch{delegationMismatch(gethcommon.Address{}, testOperator)},
+			want:       []plannedCorrection{{target: "PellDelegationManager.SyncUndelegateState"}},
+		},
+		"lost redelegation": {
+			mismatches: []Mismatch{delegationMismatch(testOperator, testStaker)},
+			want: []plannedCorrection{
+				{target: "PellDelegationManager.SyncUndelegateState"},
+				{target: "PellDelegationManager.SyncDelegateState"},
+			},
+		},
+		"service operator shares": {
+			mismatches: []Mismatch{
+				operatorSharesMismatch("ServiceOmniOperatorSharesManager", "10", "4"),
+				operatorSharesMismatch("ServiceOmniOperatorSharesManager", "4", "10"),
+			},
+			want: []plannedCorrection{
+				{target: "ServiceOmniOperatorShareManager.BatchSyncIncreaseDelegatedShares"},
+				{target: "ServiceOmniOperatorShareManager.BatchSyncDecreaseDelegatedShares"},
+			},
+		},
+		"pell operator shares": {
+			mismatches: []Mismatch{operatorSharesMismatch("PellDelegationManager", "10", "4")},
+			want:       []plannedCorrection{{status: CorrectionSkipped}},
+		},
+		"operator shares deferred after the stakers": {
+			mismatches: []Mismatch{
+				operatorSharesMismatch("ServiceOmniOperatorSharesManager", "10", "4"),
+				stakerSharesMismatch("10", "4"),
+			},
+			want: []plannedCorrection{
+				{status: CorrectionDeferred},
+				{target: "PellStrategyManager.SyncDepositState"},
+			},
+		},
+		"operator shares deferred after the delegations": {
+			mismatches: []Mismatch{
+				delegationMismatch(testOperator, gethcommon.Address{}),
+				operatorSharesMismatch("ServiceOmniOperatorSharesManager", "10", "4"),
+			},
+			want: []plannedCorrection{
+				{target: "PellDelegationManager.SyncDelegateState"},
+				{status: CorrectionDeferred},
+			},
+		},
+		"operator shares not deferred by the DVS": {
+			mismatches: []Mismatch{
+				{Kind: KindGroupCount, Expected: "2", Actual: "1"},
+				{Kind: KindOperatorWeight, Operator: testOperator.Hex(), Group: "0", Expected: "10", Actual: "4"},
+				operatorSharesMismatch("ServiceOmniOperatorSharesManager", "10", "4"),
+			},
+			want: []plannedCorrection{
+				{status: CorrectionSkipped},
+				{status: CorrectionSkipped},
+				{target: "ServiceOmniOperatorShareManager.BatchSyncIncreaseDelegatedShares"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			chain := newTestChain(t)
+			r := NewReconciler(config.DefaultReconcileConfig(), nil, log.NewNopLogger())
+
+			corrections := r.Plan(context.Background(), chain.bindings, &Report{Mismatches: tt.mismatches})
+			got := make([]plannedCorrection, len(corrections))
+			for i, c := range corrections {
+				got[i] = plannedCorrection{target: c.Target, status: c.Status}
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Empty(t, chain.txMgr.Sent())
+		})
+	}
+}
+
+func TestReconcileSendsAndJournals(t *testing.T) {
+	chain := newTestChain(t)
+	jn := &memJournal{}
+	r := NewReconciler(&config.ReconcileConfig{Enabled: true}, jn, log.NewNopLogger())
+
+	report := &Report{Mismatches: []Mismatch{
+		stakerSharesMismatch("10", "4"),
+		delegationMismatch(testOperator, gethcommon.Address{}),
+	}}
+	corrections := r.Reconcile(context.Background(), chain.bindings, report)
+	require.Len(t, corrections, 2)
+
+	sent := chain.txMgr.Sent()
+	require.Len(t, sent, 2)
+	assert.Equal(t, gethcommon.HexToAddress(chain.bindings.ContractAddress.PellStrategyManager), *sent[0].To())
+	assert.Equal(t, gethcommon.HexToAddress(chain.bindings.ContractAddress.PellDelegationManager), *sent[1].To())
+
+	entries := jn.Entries()
+	require.Len(t, entries, 2)
+	for i, c := range corrections {
+		assert.Equal(t, CorrectionSent, c.Status)
+		assert.NotEmpty(t, c.TxHash)
+		assert.True(t, entries[i].Reconciliation)
+		assert.Equal(t, journal.StatusForwarded, entries[i].Status)
+		assert.Equal(t, c.Target, entries[i].Target)
+		assert.Equal(t, c.Mismatch.Route, entries[i].Route)
+		assert.Equal(t, c.TxHash, entries[i].TargetTxHash)
+		assert.Equal(t, c.Mismatch.String(), entries[i].Reason)
+	}
+}
+
+func TestReconcileFailures(t *testing.T) {
+	chain := newTestChain(t)
+	chain.client.OnCall(gethcommon.HexToAddress(chain.bindings.ContractAddress.PellStrategyManager),
+		func(ethereum.CallMsg) ([]byte, error) {
+			return nil, errors.New("execution reverted: not connector")
+		})
+	chain.txMgr.FailNext(errors.New("nonce too low"))
+	jn := &memJournal{}
+	r := NewReconciler(&config.ReconcileConfig{Enabled: true}, jn, log.NewNopLogger())
+
+	report := &Report{Mismatches: []Mismatch{
+		stakerSharesMismatch("10", "4"),
+		delegationMismatch(testOperator, gethcommon.Address{}),
+	}}
+	corrections := r.Reconcile(context.Background(), chain.bindings, report)
+	require.Len(t, corrections, 2)
+	assert.Empty(t, chain.txMgr.Sent())
+
+	assert.Equal(t, CorrectionSkipped, corrections[0].Status)
+	assert.Contains(t, corrections[0].Reason, "reverted in simulation: execution reverted: not connector")
+	assert.Equal(t, CorrectionFailed, corrections[1].Status)
+	assert.Contains(t, corrections[1].Reason, "nonce too low")
+
+	entries := jn.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, journal.StatusSkipped, entries[0].Status)
+	assert.Equal(t, journal.StatusFailed, entries[1].Status)
+	for _, entry := range entries {
+		assert.True(t, entry.Reconciliation)
+		assert.Empty(t, entry.TargetTxHash)
+	}
+}
+
+func TestReconcileDryRun(t *testing.T) {
+	chain := newTestChain(t)
+	jn := &memJournal{}
+	r := NewReconciler(&config.ReconcileConfig{Enabled: true, DryRun: true, MaxCallsPerRun: 1}, jn, log.NewNopLogger())
+
+	report := &Report{Mismatches: []Mismatch{
+		stakerSharesMismatch("10", "4"),
+		delegationMismatch(testOperator, gethcommon.Address{}),
+		operatorSharesMismatch("ServiceOmniOperatorSharesManager", "10", "4"),
+	}}
+	corrections := r.Reconcile(context.Background(), chain.bindings, report)
+
+	var statuses []CorrectionStatus
+	for _, c := range corrections {
+		statuses = append(statuses, c.Status)
+	}
+	// dry run plans every call, the limits only apply to the calls sent
+	assert.Equal(t, []CorrectionStatus{CorrectionPlanned, CorrectionPlanned, CorrectionDeferred}, statuses)
+	assert.Empty(t, chain.txMgr.Sent())
+	assert.Empty(t, chain.client.Calls())
+	assert.Empty(t, jn.Entries())
+}
+
+func TestReconcileRateLimits(t *testing.T) {
+	mismatches := []Mismatch{
+		stakerSharesMismatch("10", "4"),
+		stakerSharesMismatch("20", "4"),
+		stakerSharesMismatch("30", "4"),
+	}
+
+	var tests = map[string]struct {
+		cfg  config.ReconcileConfig
+		runs int
+		// want are the statuses of the last run
+		want     []CorrectionStatus
+		wantSent int
+		reason   string
+	}{
+		"per run": {
+			cfg:      config.ReconcileConfig{Enabled: true, MaxCallsPerRun: 2},
+			runs:     2,
+			want:     []CorrectionStatus{CorrectionSent, CorrectionSent, CorrectionRateLimited},
+			wantSent: 4,
+			reason:   "max 2 calls per run",
+		},
+		"per hour": {
+			cfg:      config.ReconcileConfig{Enabled: true, MaxCallsPerHour: 4},
+			runs:     2,
+			want:     []CorrectionStatus{CorrectionSent, CorrectionRateLimited, CorrectionRateLimited},
+			wantSent: 4,
+			reason:   "max 4 calls per hour",
+		},
+		"unlimited": {
+			cfg:      config.ReconcileConfig{Enabled: true},
+			runs:     2,
+			want:     []CorrectionStatus{CorrectionSent, CorrectionSent, CorrectionSent},
+			wantSent: 6,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			chain := newTestChain(t)
+			jn := &memJournal{}
+			r := NewReconciler(&tt.cfg, jn, log.NewNopLogger())
+
+			var corrections []*Correction
+			for i := 0; i < tt.runs; i++ {
+				corrections = r.Reconcile(context.Background(), chain.bindings, &Report{Mismatches: mismatches})
+			}
+
+			var statuses []CorrectionStatus
+			for _, c := range corrections {
+				statuses = append(statuses, c.Status)
+				if c.Status == CorrectionRateLimited {
+					assert.Equal(t, tt.reason, c.Reason)
+				}
+			}
+			assert.Equal(t, tt.want, statuses)
+			assert.Len(t, chain.txMgr.Sent(), tt.wantSent)
+			// rate limited calls are left for the next check, they are not journaled
+			assert.Len(t, jn.Entries(), tt.wantSent)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// view is a view function of a contract, called through its json abi so the checks
+// do not depend on the bindings exposing every getter
+type view struct {
+	method string
+	abi    abi.ABI
+}
+
+func newView(method, fragment string) view {
+	parsed, err := abi.JSON(strings.NewReader(fragment))
+	if err != nil {
+		panic(fmt.Sprintf("invalid abi of %s: %v", method, err))
+	}
+	return view{method: method, abi: parsed}
+}
+
+var (
+	viewStakingStakerShares = newView("stakerStrategyShares",
+		`[{"type":"function","name":"stakerStrategyShares","stateMutability":"view","inputs":[{"name":"staker","type":"address"},{"name":"strategy","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}]`)
+	viewPellStakerShares = newView("stakerStrategyShares",
+		`[{"type":"function","name":"stakerStrategyShares","stateMutability":"view","inputs":[{"name":"chainId","type":"uint256"},{"name":"staker","type":"address"},{"name":"strategy","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}]`)
+	viewDelegatedTo = newView("delegatedTo",
+		`[{"type":"function","name":"delegatedTo","stateMutability":"view","inputs":[{"name":"staker","type":"address"}],"outputs":[{"name":"","type":"address"}]}]`)
+	viewStakingOperatorShares = newView("getOperatorShares",
+		`[{"type":"function","name":"getOperatorShares","stateMutability":"view","inputs":[{"name":"operator","type":"address"},{"name":"strategies","type":"address[]"}],"outputs":[{"name":"","type":"uint256[]"}]}]`)
+	viewOmniOperatorShares = newView("getOperatorShares",
+		`[{"type":"function","name":"getOperatorShares","stateMutability":"view","inputs":[{"name":"operator","type":"address"},{"name":"strategies","type":"tuple[]","components":[{"name":"chainId","type":"uint256"},{"name":"strategy","type":"address"}]}],"outputs":[{"name":"","type":"uint256[]"}]}]`)
+	viewGroupCount = newView("groupCount",
+		`[{"type":"function","name":"groupCount","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}]`)
+	viewOperatorWeight = newView("weightOfOperatorForGroup",
+		`[{"type":"function","name":"weightOfOperatorForGroup","stateMutability":"view","inputs":[{"name":"groupNumber","type":"uint8"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"uint96"}]}]`)
+)
+
+// omniStrategy is a strategy of a chain, as the pell and service contracts take it
+type omniStrategy struct {
+	ChainId  *big.Int
+	Strategy gethcommon.Address
+}
+
+func (c *Checker) call(ctx context.Context, contract string, v view, args ...any) ([]any, error) {
+	address, err := c.address(contract)
+	if err != nil {
+		return nil, err
+	}
+	bound := bind.NewBoundContract(address, v.abi, c.client, nil, nil)
+	var out []any
+	if err := bound.Call(&bind.CallOpts{Context: ctx}, &out, v.method, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to call %s.%s", contract, v.method)
+	}
+	if len(out) == 0 {
+		return nil, errors.Errorf("%s.%s returned nothing", contract, v.method)
+	}
+	return out, nil
+}
+
+func (c *Checker) callUint(ctx context.Context, contract string, v view, args ...any) (*big.Int, error) {
+	out, err := c.call(ctx, contract, v, args...)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, errors.Errorf("%s.%s returned %T, expected a uint", contract, v.method, out[0])
+	}
+	return value, nil
+}
+
+func (c *Checker) callUint8(ctx context.Context, contract string, v view, args ...any) (uint8, error) {
+	out, err := c.call(ctx, contract, v, args...)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := out[0].(uint8)
+	if !ok {
+		return 0, errors.Errorf("%s.%s returned %T, expected a uint8", contract, v.method, out[0])
+	}
+	return value, nil
+}
+
+func (c *Checker) callAddress(ctx context.Context, contract string, v view, args ...any) (gethcommon.Address, error) {
+	out, err := c.call(ctx, contract, v, args...)
+	if err != nil {
+		return gethcommon.Address{}, err
+	}
+	value, ok := out[0].(gethcommon.Address)
+	if !ok {
+		return gethcommon.Address{}, errors.Errorf("%s.%s returned %T, expected an address", contract, v.method, out[0])
+	}
+	return value, nil
+}
+
+func (c *Checker) callUints(ctx context.Context, contract string, v view, n int, args ...any) ([]*big.Int, error) {
+	out, err := c.call(ctx, contract, v, args...)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := out[0].([]*big.Int)
+	if !ok {
+		return nil, errors.Errorf("%s.%s returned %T, expected a uint array", contract, v.method, out[0])
+	}
+	if len(values) != n {
+		return nil, errors.Errorf("%s.%s returned %d values, expected %d", contract, v.method, len(values), n)
+	}
+	return values, nil
+}
@@ -0,0 +1,39 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+var drainPollInterval = 100 * time.Millisecond
+
+// detach returns the context events are processed with. It is not canceled with ctx,
+// so a forward in flight when the route is stopped can still finish, see Drain.
+func (be *BaseEvent) detach(ctx context.Context) context.Context {
+	be.processOnce.Do(func() {
+		be.processCtx, be.cancelProcess = context.WithCancel(context.WithoutCancel(ctx))
+	})
+	return be.processCtx
+}
+
+// Drain waits for the event in flight of a stopped route. If ctx is done first
+// the forward is canceled and ctx.Err() is returned.
+func (be *BaseEvent) Drain(ctx context.Context) error {
+	defer func() {
+		be.processOnce.Do(func() {})
+		if be.cancelProcess != nil {
+			be.cancelProcess()
+		}
+	}()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for be.isProcessing() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
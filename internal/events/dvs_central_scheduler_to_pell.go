@@ -10,13 +10,13 @@ import (
 	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/registryrouter.sol"
 	"github.com/0xPellNetwork/contracts/pkg/contracts/service_evm/registryinteractor.sol"
 	gethbind "github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventCentralSchedulerToPell struct {
@@ -38,7 +38,7 @@ func NewEventCentralSchedulerToPell(
 	eventName := "CentralSchedulerEvent"
 	contractName := "PellRegistryInteractor"
 
-	eventCh := make(chan *registryinteractor.RegistryInteractorRegisterCentralSchedulerToPell)
+	eventCh := make(chan *registryinteractor.RegistryInteractorRegisterCentralSchedulerToPell, eventQueueSize)
 
 	var res = &EventCentralSchedulerToPell{
 		BaseEvent: BaseEvent{
@@ -51,6 +51,7 @@ func NewEventCentralSchedulerToPell(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMPell, "PellRegistryRouter", "AddSupportedChain"),
 			},
@@ -74,27 +75,26 @@ func (e *EventCentralSchedulerToPell) Init(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
 func (e *EventCentralSchedulerToPell) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				time.Sleep(1 * time.Second)
@@ -105,6 +105,29 @@ func (e *EventCentralSchedulerToPell) Listen(ctx context.Context) error {
 	return nil
 }
 
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventCentralSchedulerToPell) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.PellRegistryInteractor.FilterRegisterCentralSchedulerToPell(&gethbind.FilterOpts{Start: from.Block, Context: ctx})
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
+
 func (e *EventCentralSchedulerToPell) process(
 	ctx context.Context,
 	event *registryinteractor.RegistryInteractorRegisterCentralSchedulerToPell,
@@ -151,22 +174,16 @@ func (e *EventCentralSchedulerToPell) process(
 
 	e.logger.Debug("dvsChainApproverSignature", "dvsChainApproverSignature", dvsChainApproverSignature)
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
-	if err != nil {
-		return errors.Wrap(err, "failed to get no send tx opts")
-	}
-	tx, err := e.rpcBindings.PellRegistryRouter.AddSupportedChain(noSendTxOpts, dvsInfo, dvsChainApproverSignature)
+	receipt, err := e.forward(ctx, event.Raw, func(opts *gethbind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.PellRegistryRouter.AddSupportedChain(opts, dvsInfo, dvsChainApproverSignature)
+	})
 	if err != nil {
 		// if the chain is already supported, we can ignore the error
-		if strings.Contains(err.Error(), "revert: RR25") {
+		if errors.Is(err, ErrTargetReverted) && strings.Contains(err.Error(), "RR25") {
 			e.logger.Info("chain already supported")
 			return nil
 		}
-		return errors.Wrap(err, "failed to add supported chain")
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.New("failed to send tx with err: " + err.Error())
+		return err
 	}
 	e.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 
 	gethevent "github.com/ethereum/go-ethereum/event"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/metrics/collectors/relay"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
 )
@@ -17,21 +21,63 @@ import (
 type IEvents interface {
 	Init(ctx context.Context) error
 	Listen(ctx context.Context) error
+	Status(head uint64) RouteStatus
+	Drain(ctx context.Context) error
+	ResumeFrom() (LogPosition, bool)
 }
 
 type BaseEvent struct {
-	srcEVM      string
-	eventName   string
-	srcContract string
-	logger      log.Logger
-	chainID     *big.Int
-	wsClient    eth.Client
-	rpcClient   eth.Client
-	wsBindings  *chains.TypesWsBindings
-	rpcBindings *chains.TypesRPCBindings
-	txMgr       txmgr.TxManager
-	evtSub      gethevent.Subscription
-	targets     []EventTargetInfo
+	srcEVM         string
+	eventName      string
+	srcContract    string
+	logger         log.Logger
+	chainID        *big.Int
+	wsClient       eth.Client
+	rpcClient      eth.Client
+	wsBindings     *chains.TypesWsBindings
+	rpcBindings    *chains.TypesRPCBindings
+	txMgr          txmgr.TxManager
+	evtSub         gethevent.Subscription
+	targets        []EventTargetInfo
+	journal        journal.Journal
+	metrics        *relay.Collector
+	tracerProvider trace.TracerProvider
+	queueDepth     func() int
+	health         routeHealth
+	position       routePosition
+
+	// processCtx outlives the Listen context so the event in flight can finish
+	processOnce   sync.Once
+	processCtx    context.Context
+	cancelProcess context.CancelFunc
+}
+
+func (be *BaseEvent) baseEvent() *BaseEvent {
+	return be
+}
+
+// Option configures the dependencies shared by all events
+type Option func(*BaseEvent)
+
+// WithJournal sets the journal every forward is recorded to
+func WithJournal(j journal.Journal) Option {
+	return func(be *BaseEvent) {
+		be.journal = j
+	}
+}
+
+// WithMetrics sets the collector the route events, latencies and gas are reported to
+func WithMetrics(collector *relay.Collector) Option {
+	return func(be *BaseEvent) {
+		be.metrics = collector
+	}
+}
+
+// WithTracerProvider sets the provider every forward is traced with
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(be *BaseEvent) {
+		be.tracerProvider = provider
+	}
 }
 
 func (be *BaseEvent) setLogger(logger log.Logger) log.Logger {
@@ -66,7 +112,7 @@ func newTarget(evm string, contract string, method string) EventTargetInfo {
 func GetAllEvents(chainID *big.Int,
 	rpcClient eth.Client, rpcBindings *chains.TypesRPCBindings,
 	wsClient eth.Client, wsBindings *chains.TypesWsBindings,
-	txMgr txmgr.TxManager, logger log.Logger) []IEvents {
+	txMgr txmgr.TxManager, logger log.Logger, opts ...Option) []IEvents {
 
 	var eventList []IEvents
 
@@ -144,5 +190,13 @@ func GetAllEvents(chainID *big.Int,
 	)
 	eventList = append(eventList, eventEventPellDelegationManagerOperatorSharesDecreased)
 
+	for _, event := range eventList {
+		be := event.(interface{ baseEvent() *BaseEvent }).baseEvent()
+		be.journal = journal.NewNopJournal()
+		for _, opt := range opts {
+			opt(be)
+		}
+	}
+
 	return eventList
 }
@@ -0,0 +1,208 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/metrics/collectors/relay"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+	"github.com/0xPellNetwork/pell-emulator/libs/tracing"
+)
+
+var (
+	maxSimulationAttempts   = 3
+	simulationRetryInterval = 2 * time.Second
+)
+
+// ErrTargetReverted is returned by forward when the target call reverts, in simulation the event
+// is skipped and dead-lettered in the journal, on chain the mined tx is recorded as failed.
+var ErrTargetReverted = errors.New("target call reverted")
+
+type buildTxFunc func(opts *bind.TransactOpts) (*gethtypes.Transaction, error)
+
+// forward builds the target tx, simulates it with eth_call and only broadcasts it
+// through the tx manager if the simulation succeeded. Transient rpc failures are retried,
+// reverts are not.
+func (be *BaseEvent) forward(ctx context.Context, src gethtypes.Log, build buildTxFunc) (*gethtypes.Receipt, error) {
+	entry := journal.Entry{
+		Route:          be.eventName,
+		SrcTxHash:      src.TxHash.Hex(),
+		SrcLogIndex:    src.Index,
+		SrcBlockNumber: src.BlockNumber,
+		Target:         be.targetName(),
+	}
+	lg := be.logger.With("srcTxHash", entry.SrcTxHash, "srcLogIndex", entry.SrcLogIndex)
+	be.countEvent(relay.StatusReceived)
+
+	ctx, span := be.startForwardSpan(ctx, src)
+	defer span.End()
+
+	var tx *gethtypes.Transaction
+	var sim *txmgr.SimulationResult
+	for attempt := 1; attempt <= maxSimulationAttempts; attempt++ {
+		tx, sim = be.simulate(ctx, build)
+		if sim.Status != txmgr.SimulationTransient {
+			break
+		}
+		lg.Error("simulation failed, will retry",
+			"error", sim.Err,
+			"attempt", attempt,
+			"maxAttempts", maxSimulationAttempts,
+		)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(simulationRetryInterval):
+		}
+	}
+
+	switch sim.Status {
+	case txmgr.SimulationReverted:
+		lg.Error("target call reverted in simulation, skipping", "reason", sim.Reason)
+		entry.Status = journal.StatusSkipped
+		entry.Reason = sim.Reason
+		be.record(entry)
+		err := fmt.Errorf("%w: %s", ErrTargetReverted, sim.Reason)
+		tracing.RecordError(span, err)
+		return nil, err
+	case txmgr.SimulationTransient:
+		entry.Status = journal.StatusFailed
+		entry.Reason = sim.Err.Error()
+		be.record(entry)
+		tracing.RecordError(span, sim.Err)
+		return nil, errors.Wrap(sim.Err, "failed to simulate tx")
+	}
+
+	receipt, err := be.txMgr.Send(ctx, tx)
+	if err != nil {
+		entry.Status = journal.StatusFailed
+		entry.Reason = err.Error()
+		be.record(entry)
+		tracing.RecordError(span, err)
+		return nil, errors.Wrap(err, "failed to send tx")
+	}
+
+	entry.TargetTxHash = receipt.TxHash.Hex()
+	span.SetAttributes(attribute.String("target.tx_hash", entry.TargetTxHash))
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		// the simulation passed but the state changed before the tx was mined
+		err := fmt.Errorf("%w: tx %s reverted in block %s", ErrTargetReverted, entry.TargetTxHash, receipt.BlockNumber)
+		lg.Error("target tx reverted", "error", err)
+		entry.Status = journal.StatusFailed
+		entry.Reason = err.Error()
+		be.record(entry)
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	entry.Status = journal.StatusForwarded
+	be.record(entry)
+	be.forwarded(entry.TargetTxHash)
+	be.observeForward(ctx, src, receipt)
+
+	return receipt, nil
+}
+
+// simulate builds the tx and runs it with eth_call, the binding already estimates gas
+// when building so a revert can surface from either step.
+func (be *BaseEvent) simulate(ctx context.Context, build buildTxFunc) (*gethtypes.Transaction, *txmgr.SimulationResult) {
+	noSendTxOpts, err := be.txMgr.GetNoSendTxOpts()
+	if err != nil {
+		return nil, &txmgr.SimulationResult{Status: txmgr.SimulationTransient, Err: err}
+	}
+	noSendTxOpts.Context = ctx
+
+	_, convertSpan := tracing.Start(ctx, "convert")
+	tx, err := build(noSendTxOpts)
+	tracing.RecordError(convertSpan, err)
+	convertSpan.End()
+	if err != nil {
+		return nil, txmgr.ClassifyCallError(err)
+	}
+
+	simCtx, simSpan := tracing.Start(ctx, "simulate", attribute.String("from", noSendTxOpts.From.Hex()))
+	defer simSpan.End()
+	sim := txmgr.Simulate(simCtx, be.rpcClient, noSendTxOpts.From, tx)
+	simSpan.SetAttributes(attribute.String("status", string(sim.Status)))
+	if sim.Reason != "" {
+		simSpan.SetAttributes(attribute.String("reason", sim.Reason))
+	}
+	tracing.RecordError(simSpan, sim.Err)
+	return tx, sim
+}
+
+// startForwardSpan starts the trace of a forward and records the receive of the source log
+func (be *BaseEvent) startForwardSpan(ctx context.Context, src gethtypes.Log) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("route", be.eventName),
+		attribute.String("src.evm", be.srcEVM),
+		attribute.String("src.contract", be.srcContract),
+		attribute.String("src.tx_hash", src.TxHash.Hex()),
+		attribute.Int64("src.log_index", int64(src.Index)),
+		attribute.Int64("src.block_number", int64(src.BlockNumber)),
+	}
+	if len(be.targets) > 0 {
+		attrs = append(attrs,
+			attribute.String("target.evm", be.targets[0].EVM),
+			attribute.String("target.contract", be.targets[0].Contract),
+			attribute.String("target.method", be.targets[0].Method),
+		)
+	}
+	ctx, span := tracing.Tracer(be.tracerProvider).Start(ctx, "forward "+be.eventName, trace.WithAttributes(attrs...))
+
+	_, receiveSpan := tracing.Start(ctx, "receive", attribute.String("src.block_hash", src.BlockHash.Hex()))
+	receiveSpan.End()
+
+	return ctx, span
+}
+
+func (be *BaseEvent) record(entry journal.Entry) {
+	be.countEvent(string(entry.Status))
+	if be.journal == nil {
+		return
+	}
+	if err := be.journal.Record(entry); err != nil {
+		be.logger.Error("failed to record journal entry", "error", err)
+	}
+}
+
+func (be *BaseEvent) targetName() string {
+	if len(be.targets) == 0 {
+		return ""
+	}
+	target := be.targets[0]
+	return fmt.Sprintf("%s:%s:%s", target.EVM, target.Contract, target.Method)
+}
+
+func (be *BaseEvent) countEvent(status string) {
+	if be.metrics == nil {
+		return
+	}
+	be.metrics.AddEvent(be.eventName, status)
+}
+
+// observeForward reports the gas used by the target tx and the time from the source block to the receipt
+func (be *BaseEvent) observeForward(ctx context.Context, src gethtypes.Log, receipt *gethtypes.Receipt) {
+	if be.metrics == nil {
+		return
+	}
+	if len(be.targets) > 0 {
+		be.metrics.AddGasUsed(be.targets[0].Contract, be.targets[0].Method, receipt.GasUsed)
+	}
+
+	header, err := be.rpcClient.HeaderByHash(ctx, src.BlockHash)
+	if err != nil {
+		be.logger.Error("failed to get source block header", "error", err, "blockHash", src.BlockHash.Hex())
+		return
+	}
+	latency := time.Since(time.Unix(int64(header.Time), 0))
+	be.metrics.ObserveForwardLatencySeconds(be.eventName, latency.Seconds())
+}
@@ -0,0 +1,282 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v2/strategymanager.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v3/delegationmanager.sol"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/events/routetest"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var (
+	testChainID = big.NewInt(1337)
+	testSender  = gethcommon.HexToAddress("0x00000000000000000000000000000000000f0001")
+)
+
+type memJournal struct {
+	mu      sync.Mutex
+	entries []journal.Entry
+}
+
+func (j *memJournal) Record(entry journal.Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+func (j *memJournal) Entries() []journal.Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]journal.Entry(nil), j.entries...)
+}
+
+type testRoute struct {
+	client  *ethtest.Client
+	txMgr   *ethtest.TxManager
+	journal *memJournal
+	address *config.ContractAddress
+	block   uint64
+	route   IEvents
+}
+
+// startRoute runs the route name of GetAllEvents against a fake client until the test ends
+func startRoute(t *testing.T, name string, opts ...Option) *testRoute {
+	r := newTestRoute()
+	r.start(t, name, opts...)
+	return r
+}
+
+func newTestRoute() *testRoute {
+	return &testRoute{
+		client:  ethtest.NewClient(testChainID),
+		txMgr:   ethtest.NewTxManager(testSender),
+		journal: &memJournal{},
+		address: config.DefaultContractAddress(),
+	}
+}
+
+func (r *testRoute) start(t *testing.T, name string, opts ...Option) {
+	logger := log.NewNopLogger()
+	rpcBindings, err := chains.NewRPCBindings(r.client, r.address, logger)
+	require.NoError(t, err)
+	wsBindings, err := chains.NewWSBindings(r.client, r.address, logger)
+	require.NoError(t, err)
+
+	routes, err := FilterRoutes(GetAllEvents(
+		testChainID, r.client, rpcBindings, r.client, wsBindings, r.txMgr, logger,
+		append([]Option{WithJournal(r.journal)}, opts...)...,
+	), []string{name})
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	require.NoError(t, routes[0].Init(ctx))
+	require.NoError(t, routes[0].Listen(ctx))
+	r.route = routes[0]
+}
+
+// emit raises the event of a source contract in a new block
+func (r *testRoute) emit(t *testing.T, address string, parsed *abi.ABI, event string, args ...any) {
+	r.emitTx(t, address, routetest.Event{ABI: parsed, Name: event, Args: args})
+}
+
+// emitDeposit raises a Deposit of the StakingStrategyManager in a new block
+func (r *testRoute) emitDeposit(t *testing.T) {
+	r.emit(t, r.address.StakingStrategyManager, routetest.MustABI(t, strategymanager.StrategyManagerMetaData.GetAbi),
+		"Deposit", routetest.Staker, routetest.Token, routetest.Strategy, routetest.Shares)
+}
+
+// emitDelegation raises event of the StakingDelegationManager in a new block
+func (r *testRoute) emitDelegation(t *testing.T, event string) {
+	r.emit(t, r.address.StakingDelegationManager, routetest.MustABI(t, delegationmanager.DelegationManagerMetaData.GetAbi),
+		event, routetest.Staker, routetest.Operator)
+}
+
+// emitTx raises events of a source contract in one tx of a new block,
+// it returns the args of each event by name
+func (r *testRoute) emitTx(t *testing.T, address string, events ...routetest.Event) []map[string]any {
+	r.block++
+	logs, named := routetest.Logs(t, gethcommon.HexToAddress(address), events...)
+	for i := range logs {
+		logs[i].BlockNumber = r.block
+		logs[i].TxHash = gethcommon.BigToHash(new(big.Int).SetUint64(r.block))
+		logs[i].Index = uint(i)
+	}
+	r.client.AddLogs(logs...)
+	return named
+}
+
+func (r *testRoute) waitJournal(t *testing.T, entries int) []journal.Entry {
+	require.Eventually(t, func() bool {
+		return len(r.journal.Entries()) >= entries
+	}, 10*time.Second, 20*time.Millisecond)
+	return r.journal.Entries()
+}
+
+func TestRoutesForwardTargetCalldata(t *testing.T) {
+	tests := routetest.Cases(t, config.DefaultContractAddress())
+
+	// every route of GetAllEvents is covered
+	var routes, covered []string
+	for _, route := range GetAllEvents(nil, nil, nil, nil, nil, nil, log.NewNopLogger()) {
+		routes = append(routes, RouteName(route))
+	}
+	for _, tt := range tests {
+		covered = append(covered, tt.Route)
+	}
+	assert.ElementsMatch(t, routes, covered)
+
+	for _, tt := range tests {
+		t.Run(tt.Route, func(t *testing.T) {
+			r := startRoute(t, tt.Route)
+			events := r.emitTx(t, tt.Source, tt.Events...)
+
+			entries := r.waitJournal(t, 1)
+			require.Equal(t, journal.StatusForwarded, entries[0].Status, entries[0].Reason)
+			assert.Equal(t, tt.Route, entries[0].Route)
+			assert.Equal(t, uint64(1), entries[0].SrcBlockNumber)
+
+			_, args := tt.ForwardArgs(t, testChainID, events)
+			sent := r.txMgr.Sent()
+			require.Len(t, sent, 1)
+			assert.Equal(t, gethcommon.HexToAddress(tt.Target), *sent[0].To())
+			expected, err := tt.TargetABI.Pack(tt.Method, args...)
+			require.NoError(t, err)
+			assert.Equal(t, expected, sent[0].Data())
+			assert.Equal(t, sent[0].Hash().Hex(), entries[0].TargetTxHash)
+
+			// the forward was simulated from the sender of the tx manager first
+			calls := r.client.Calls()
+			require.Len(t, calls, 1)
+			assert.Equal(t, testSender, calls[0].From)
+			assert.Equal(t, expected, calls[0].Data)
+		})
+	}
+}
+
+func TestForwardSkipsRevertedTarget(t *testing.T) {
+	r := startRoute(t, "Deposit")
+	r.client.OnCall(gethcommon.HexToAddress(r.address.PellStrategyManager), func(ethereum.CallMsg) ([]byte, error) {
+		return nil, errors.New("execution reverted: not connector")
+	})
+	r.emitDeposit(t)
+
+	entries := r.waitJournal(t, 1)
+	assert.Equal(t, journal.StatusSkipped, entries[0].Status)
+	assert.Contains(t, entries[0].Reason, "not connector")
+	assert.Empty(t, r.txMgr.Sent())
+}
+
+func TestForwardRecordsFailedSend(t *testing.T) {
+	r := startRoute(t, "StakerDelegated")
+	r.txMgr.FailNext(errors.New("nonce too low"))
+
+	r.emitDelegation(t, "StakerDelegated")
+	entries := r.waitJournal(t, 1)
+	assert.Equal(t, journal.StatusFailed, entries[0].Status)
+	assert.Contains(t, entries[0].Reason, "nonce too low")
+	assert.Empty(t, r.txMgr.Sent())
+
+	// the next event is forwarded
+	r.emitDelegation(t, "StakerDelegated")
+	entries = r.waitJournal(t, 2)
+	assert.Equal(t, journal.StatusForwarded, entries[1].Status)
+	assert.Len(t, r.txMgr.Sent(), 1)
+}
+
+func TestForwardRecordsRevertedReceipt(t *testing.T) {
+	r := startRoute(t, "StakerDelegated")
+	r.txMgr.OnSend(func(tx *gethtypes.Transaction) (*gethtypes.Receipt, error) {
+		return &gethtypes.Receipt{Status: gethtypes.ReceiptStatusFailed, TxHash: tx.Hash()}, nil
+	})
+
+	r.emitDelegation(t, "StakerDelegated")
+	entries := r.waitJournal(t, 1)
+	require.Len(t, r.txMgr.Sent(), 1)
+	assert.Equal(t, journal.StatusFailed, entries[0].Status)
+	assert.Equal(t, r.txMgr.Sent()[0].Hash().Hex(), entries[0].TargetTxHash)
+	assert.Contains(t, entries[0].Reason, ErrTargetReverted.Error())
+	assert.Empty(t, r.route.Status(0).LastForwardTxHash)
+}
+
+func TestRouteResubscribes(t *testing.T) {
+	interval := resubscribeInterval
+	resubscribeInterval = 10 * time.Millisecond
+	t.Cleanup(func() { resubscribeInterval = interval })
+
+	r := startRoute(t, "StakerUndelegated")
+	require.Equal(t, 1, r.client.Subscriptions())
+
+	r.client.FailNext("SubscribeFilterLogs", errors.New("dial tcp: connection refused"))
+	r.client.DropSubscriptions(errors.New("websocket: close 1006"))
+	require.Eventually(t, func() bool {
+		return r.client.Subscriptions() == 1
+	}, 10*time.Second, 20*time.Millisecond)
+
+	r.emitDelegation(t, "StakerUndelegated")
+	entries := r.waitJournal(t, 1)
+	assert.Equal(t, journal.StatusForwarded, entries[0].Status)
+}
+
+func TestRouteReplaysLogsMissedWhileResubscribing(t *testing.T) {
+	interval := resubscribeInterval
+	resubscribeInterval = 300 * time.Millisecond
+	t.Cleanup(func() { resubscribeInterval = interval })
+
+	r := startRoute(t, "Deposit")
+	require.Eventually(t, func() bool {
+		_, ok := r.route.ResumeFrom()
+		return ok
+	}, 10*time.Second, 20*time.Millisecond)
+
+	// emitted before the route resubscribes, only the replay sees it
+	r.client.DropSubscriptions(errors.New("websocket: close 1006"))
+	r.emitDeposit(t)
+
+	entries := r.waitJournal(t, 1)
+	assert.Equal(t, journal.StatusForwarded, entries[0].Status, entries[0].Reason)
+	assert.Equal(t, uint64(1), entries[0].SrcBlockNumber)
+
+	r.emitDeposit(t)
+	entries = r.waitJournal(t, 2)
+	assert.Equal(t, uint64(2), entries[1].SrcBlockNumber)
+	assert.Len(t, r.txMgr.Sent(), 2)
+}
+
+func TestRouteResumesFromStoppedRoute(t *testing.T) {
+	r := newTestRoute()
+	// block 1 was handled by the stopped route, block 2 was queued and block 3 emitted while stopped
+	for i := 0; i < 3; i++ {
+		r.emitDeposit(t)
+	}
+	r.start(t, "Deposit", WithResume(map[string]LogPosition{"Deposit": {Block: 2}}))
+
+	entries := r.waitJournal(t, 2)
+	assert.Equal(t, uint64(2), entries[0].SrcBlockNumber)
+	assert.Equal(t, uint64(3), entries[1].SrcBlockNumber)
+
+	r.emitDeposit(t)
+	entries = r.waitJournal(t, 3)
+	assert.Equal(t, uint64(4), entries[2].SrcBlockNumber)
+	assert.Len(t, r.journal.Entries(), 3)
+	assert.Len(t, r.txMgr.Sent(), 3)
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethevent "github.com/ethereum/go-ethereum/event"
+)
+
+// eventQueueSize is the buffer of the channel between the subscription and the route
+const eventQueueSize = 128
+
+// RouteStatus is the health of a single route
+type RouteStatus struct {
+	Route             string     `json:"route"`
+	SrcEVM            string     `json:"src_evm"`
+	SrcContract       string     `json:"src_contract"`
+	Subscribed        bool       `json:"subscribed"`
+	LastEventAt       *time.Time `json:"last_event_at,omitempty"`
+	LastEventBlock    uint64     `json:"last_event_block,omitempty"`
+	LastForwardAt     *time.Time `json:"last_forward_at,omitempty"`
+	LastForwardTxHash string     `json:"last_forward_tx_hash,omitempty"`
+	ErrorCount        uint64     `json:"error_count"`
+	LastError         string     `json:"last_error,omitempty"`
+	QueueDepth        int        `json:"queue_depth"`
+	// LagBlocks is how far the event being processed is behind the chain head, 0 when idle
+	LagBlocks uint64 `json:"lag_blocks"`
+}
+
+type routeHealth struct {
+	mu                sync.Mutex
+	subscribed        bool
+	lastEventAt       time.Time
+	lastEventBlock    uint64
+	processingBlock   uint64
+	processing        bool
+	lastForwardAt     time.Time
+	lastForwardTxHash string
+	errorCount        uint64
+	lastError         string
+}
+
+// setSubscription replaces the subscription of the route
+func (be *BaseEvent) setSubscription(sub gethevent.Subscription) {
+	be.health.mu.Lock()
+	defer be.health.mu.Unlock()
+	be.evtSub = sub
+	be.health.subscribed = true
+}
+
+func (be *BaseEvent) subscriptionLost() {
+	be.health.mu.Lock()
+	defer be.health.mu.Unlock()
+	be.health.subscribed = false
+}
+
+func (be *BaseEvent) eventSeen(src gethtypes.Log) {
+	be.health.mu.Lock()
+	defer be.health.mu.Unlock()
+	be.health.lastEventAt = time.Now()
+	be.health.lastEventBlock = src.BlockNumber
+	be.health.processingBlock = src.BlockNumber
+	be.health.processing = true
+}
+
+func (be *BaseEvent) eventDone(err error) {
+	be.health.mu.Lock()
+	defer be.health.mu.Unlock()
+	be.health.processingBlock = 0
+	be.health.processing = false
+	if err != nil {
+		be.health.errorCount++
+		be.health.lastError = err.Error()
+	}
+}
+
+func (be *BaseEvent) forwarded(txHash string) {
+	be.health.mu.Lock()
+	defer be.health.mu.Unlock()
+	be.health.lastForwardAt = time.Now()
+	be.health.lastForwardTxHash = txHash
+}
+
+// Status returns the health of the route, head is the latest block of the source chain
+func (be *BaseEvent) Status(head uint64) RouteStatus {
+	be.health.mu.Lock()
+	defer be.health.mu.Unlock()
+
+	status := RouteStatus{
+		Route:             be.eventName,
+		SrcEVM:            be.srcEVM,
+		SrcContract:       be.srcContract,
+		Subscribed:        be.health.subscribed,
+		LastEventBlock:    be.health.lastEventBlock,
+		LastForwardTxHash: be.health.lastForwardTxHash,
+		ErrorCount:        be.health.errorCount,
+		LastError:         be.health.lastError,
+	}
+	if !be.health.lastEventAt.IsZero() {
+		lastEventAt := be.health.lastEventAt
+		status.LastEventAt = &lastEventAt
+	}
+	if !be.health.lastForwardAt.IsZero() {
+		lastForwardAt := be.health.lastForwardAt
+		status.LastForwardAt = &lastForwardAt
+	}
+	if be.queueDepth != nil {
+		status.QueueDepth = be.queueDepth()
+	}
+	if be.health.processingBlock != 0 && head > be.health.processingBlock {
+		status.LagBlocks = head - be.health.processingBlock
+	}
+	return status
+}
+
+func (be *BaseEvent) isProcessing() bool {
+	be.health.mu.Lock()
+	defer be.health.mu.Unlock()
+	return be.health.processing
+}
@@ -2,19 +2,18 @@ package events
 
 import (
 	"context"
-	"errors"
 	"math/big"
 	"time"
 
 	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/stakeregistryrouter.sol"
 	"github.com/0xPellNetwork/pell-middleware-contracts/pkg/src/operatorstakemanager.sol"
 	gethbind "github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventRegistryRouterSyncAddPools struct {
@@ -34,7 +33,7 @@ func NewEventRegistryRouterSyncAddPools(
 ) *EventRegistryRouterSyncAddPools {
 	eventName := "SyncAddPools"
 	contractName := ContractNamePellRegistryRouter
-	eventCh := make(chan *stakeregistryrouter.StakeRegistryRouterSyncAddPools)
+	eventCh := make(chan *stakeregistryrouter.StakeRegistryRouterSyncAddPools, eventQueueSize)
 
 	var res = &EventRegistryRouterSyncAddPools{
 		BaseEvent: BaseEvent{
@@ -47,6 +46,7 @@ func NewEventRegistryRouterSyncAddPools(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMDVS, "DVSOperatorStakeManager", "SyncAddPools"),
 			},
@@ -77,21 +77,15 @@ func (e *EventRegistryRouterSyncAddPools) process(
 		"toContract", "DVSOperatorStakeManager.SyncAddStrategies",
 	)
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *gethbind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.DVSOperatorStakeManager.SyncAddPools(opts,
+			groupNumber,
+			strategyParams,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.DVSOperatorStakeManager.SyncAddPools(noSendTxOpts,
-		groupNumber,
-		strategyParams,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.New("failed to send tx with err: " + err.Error())
-	}
 	e.logger.Info("tx successfully included",
 		"txHash", receipt.TxHash.String(),
 		"toContract", "DVSOperatorStakeManager.SyncAddStrategies",
@@ -106,27 +100,26 @@ func (e *EventRegistryRouterSyncAddPools) Init(ctx context.Context) error {
 		e.logger.Error("Failed to subscribe to events", "error", err)
 		return err
 	}
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
 func (e *EventRegistryRouterSyncAddPools) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -136,3 +129,26 @@ func (e *EventRegistryRouterSyncAddPools) Listen(ctx context.Context) error {
 	}(ctx)
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventRegistryRouterSyncAddPools) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.PellStakeRegistryRouter.FilterSyncAddPools(&gethbind.FilterOpts{Start: from.Block, Context: ctx}, nil)
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
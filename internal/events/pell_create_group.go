@@ -9,12 +9,12 @@ import (
 	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/registryrouter.sol"
 	"github.com/0xPellNetwork/pell-middleware-contracts/pkg/src/centralscheduler.sol"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventRegistryRouterSyncCreateGroup struct {
@@ -33,7 +33,7 @@ func NewEventRegistryRouterSyncCreateGroup(
 
 	eventName := "SyncCreateGroup"
 	contractName := ContractNamePellRegistryRouter
-	eventCh := make(chan *registryrouter.RegistryRouterSyncCreateGroup)
+	eventCh := make(chan *registryrouter.RegistryRouterSyncCreateGroup, eventQueueSize)
 	var res = &EventRegistryRouterSyncCreateGroup{
 		BaseEvent: BaseEvent{
 			srcEVM:      EVMPell,
@@ -45,6 +45,7 @@ func NewEventRegistryRouterSyncCreateGroup(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMDVS, "DVSCentralScheduler", "SyncCreateGroup"),
 			},
@@ -77,23 +78,17 @@ func (e *EventRegistryRouterSyncCreateGroup) process(
 		poolParams[i] = centralscheduler.IOperatorStakeManagerPoolParams(strategyParam)
 	}
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.DVSCentralScheduler.SyncCreateGroup(opts,
+			event.GroupNumber,
+			operatorSetParams,
+			mintStake,
+			poolParams,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.DVSCentralScheduler.SyncCreateGroup(noSendTxOpts,
-		event.GroupNumber,
-		operatorSetParams,
-		mintStake,
-		poolParams,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.New("failed to send tx with err: " + err.Error())
-	}
 
 	e.logger.Info("tx successfully included",
 		"txHash", receipt.TxHash.String(),
@@ -108,27 +103,26 @@ func (e *EventRegistryRouterSyncCreateGroup) Init(ctx context.Context) error {
 		e.logger.Error("Failed to subscribe to events", "error", err)
 		return err
 	}
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
 func (e *EventRegistryRouterSyncCreateGroup) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -139,3 +133,26 @@ func (e *EventRegistryRouterSyncCreateGroup) Listen(ctx context.Context) error {
 
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventRegistryRouterSyncCreateGroup) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.PellRegistryRouter.FilterSyncCreateGroup(&bind.FilterOpts{Start: from.Block, Context: ctx}, nil)
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
@@ -2,7 +2,6 @@ package events
 
 import (
 	"context"
-	"errors"
 	"math/big"
 	"time"
 
@@ -10,12 +9,12 @@ import (
 	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v3/delegationmanager.sol"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventPellDelegationManagerOperatorRegistered struct {
@@ -35,7 +34,7 @@ func NewEventPellDelegationManagerOperatorRegistered(
 	eventName := "OperatorRegistered"
 	contractName := ContractNamePellDelegationManager
 
-	eventCh := make(chan *pelldelegationmanager.PellDelegationManagerOperatorRegistered)
+	eventCh := make(chan *pelldelegationmanager.PellDelegationManagerOperatorRegistered, eventQueueSize)
 
 	var res = &EventPellDelegationManagerOperatorRegistered{
 		BaseEvent: BaseEvent{
@@ -48,6 +47,7 @@ func NewEventPellDelegationManagerOperatorRegistered(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMDVS, "StakingDelegationManager", "SyncRegisterAsOperator"),
 			},
@@ -77,21 +77,15 @@ func (e *EventPellDelegationManagerOperatorRegistered) process(
 
 	// TODO(jimmy): DeprecatedEarningsReceiver is not in the event, so it is set to the zero address.
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.StakingDelegationManager.SyncRegisterAsOperator(opts,
+			operator,
+			details,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.StakingDelegationManager.SyncRegisterAsOperator(noSendTxOpts,
-		operator,
-		details,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.New("failed to send tx with err: " + err.Error())
-	}
 	e.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 
 	return nil
@@ -106,27 +100,26 @@ func (e *EventPellDelegationManagerOperatorRegistered) Init(ctx context.Context)
 		return err
 	}
 
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
 func (e *EventPellDelegationManagerOperatorRegistered) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -137,3 +130,26 @@ func (e *EventPellDelegationManagerOperatorRegistered) Listen(ctx context.Contex
 
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventPellDelegationManagerOperatorRegistered) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.PellDelegationManager.FilterOperatorRegistered(&bind.FilterOpts{Start: from.Block, Context: ctx}, nil)
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
@@ -8,13 +8,12 @@ import (
 	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/pelldelegationmanager.sol"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/pkg/errors"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventPellDelegationManagerOperatorSharesDecreased struct {
@@ -33,7 +32,7 @@ func NewEventPellDelegationManagerOperatorSharesDecreased(
 
 	eventName := "OperatorSharesDecreased"
 	contractName := ContractNamePellDelegationManager
-	eventCh := make(chan *pelldelegationmanager.PellDelegationManagerOperatorSharesDecreased)
+	eventCh := make(chan *pelldelegationmanager.PellDelegationManagerOperatorSharesDecreased, eventQueueSize)
 
 	var res = &EventPellDelegationManagerOperatorSharesDecreased{
 		BaseEvent: BaseEvent{
@@ -46,6 +45,7 @@ func NewEventPellDelegationManagerOperatorSharesDecreased(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMService, "ServiceOmniOperatorShareManager", "BatchSyncDecreaseDelegatedShares"),
 			},
@@ -79,23 +79,17 @@ func (e *EventPellDelegationManagerOperatorSharesDecreased) process(
 	pools = append(pools, event.Strategy)
 	shares = append(shares, event.Shares)
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.ServiceOmniOperatorShareManager.BatchSyncDecreaseDelegatedShares(opts,
+			chainIDs,
+			operators,
+			pools,
+			shares,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.ServiceOmniOperatorShareManager.BatchSyncDecreaseDelegatedShares(noSendTxOpts,
-		chainIDs,
-		operators,
-		pools,
-		shares,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.New("failed to send tx with err: " + err.Error())
-	}
 	e.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 
 	//updateOperatorsReceipt, err := e.updateOperators(ctx, operators)
@@ -117,27 +111,26 @@ func (e *EventPellDelegationManagerOperatorSharesDecreased) Init(ctx context.Con
 		return err
 	}
 
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
 func (e *EventPellDelegationManagerOperatorSharesDecreased) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtChan:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtChan)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -148,3 +141,26 @@ func (e *EventPellDelegationManagerOperatorSharesDecreased) Listen(ctx context.C
 
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventPellDelegationManagerOperatorSharesDecreased) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.PellDelegationManager.FilterOperatorSharesDecreased(&bind.FilterOpts{Start: from.Block, Context: ctx}, nil, nil)
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
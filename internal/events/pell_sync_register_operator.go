@@ -10,12 +10,12 @@ import (
 	"github.com/0xPellNetwork/pell-middleware-contracts/pkg/src/centralscheduler.sol"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventRegistryRouterSyncRegisterOperator struct {
@@ -35,7 +35,7 @@ func NewEventRegistryRouterSyncRegisterOperator(
 
 	eventName := "SyncRegisterOperator"
 	contractName := ContractNamePellRegistryRouter
-	eventCh := make(chan *registryrouter.RegistryRouterSyncRegisterOperator)
+	eventCh := make(chan *registryrouter.RegistryRouterSyncRegisterOperator, eventQueueSize)
 
 	var res = &EventRegistryRouterSyncRegisterOperator{
 		BaseEvent: BaseEvent{
@@ -48,6 +48,7 @@ func NewEventRegistryRouterSyncRegisterOperator(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMDVS, "DVSCentralScheduler", "SyncRegisterOperator"),
 			},
@@ -92,22 +93,16 @@ func (e *EventRegistryRouterSyncRegisterOperator) process(
 		},
 	}
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.DVSCentralScheduler.SyncRegisterOperator(opts,
+			operatorAddress,
+			groupNumbers,
+			pubKeyParams,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.DVSCentralScheduler.SyncRegisterOperator(noSendTxOpts,
-		operatorAddress,
-		groupNumbers,
-		pubKeyParams,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.New("failed to send tx with err: " + err.Error())
-	}
 	e.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 
 	return nil
@@ -126,7 +121,7 @@ func (e *EventRegistryRouterSyncRegisterOperator) Init(ctx context.Context) erro
 		return err
 	}
 
-	e.evtSub = sub
+	e.setSubscription(sub)
 
 	return nil
 }
@@ -134,20 +129,19 @@ func (e *EventRegistryRouterSyncRegisterOperator) Init(ctx context.Context) erro
 func (e *EventRegistryRouterSyncRegisterOperator) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -158,3 +152,26 @@ func (e *EventRegistryRouterSyncRegisterOperator) Listen(ctx context.Context) er
 
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventRegistryRouterSyncRegisterOperator) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.PellRegistryRouter.FilterSyncRegisterOperator(&bind.FilterOpts{Start: from.Block, Context: ctx}, nil, nil)
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
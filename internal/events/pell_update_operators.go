@@ -8,13 +8,13 @@ import (
 	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/registryrouter.sol"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventRegistryRouterSyncUpdateOperators struct {
@@ -35,7 +35,7 @@ func NewEventRegistryRouterSyncUpdateOperators(
 	eventName := "SyncUpdateOperators"
 	contractName := ContractNamePellRegistryRouter
 
-	eventCh := make(chan *registryrouter.RegistryRouterSyncUpdateOperators)
+	eventCh := make(chan *registryrouter.RegistryRouterSyncUpdateOperators, eventQueueSize)
 	var res = &EventRegistryRouterSyncUpdateOperators{
 		BaseEvent: BaseEvent{
 			srcEVM:      EVMPell,
@@ -48,6 +48,7 @@ func NewEventRegistryRouterSyncUpdateOperators(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMDVS, "DVSCentralScheduler", "SyncUpdateOperators"),
 			},
@@ -77,20 +78,14 @@ func (e *EventRegistryRouterSyncUpdateOperators) process(
 		operators[i] = operator
 	}
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.DVSCentralScheduler.SyncUpdateOperators(opts,
+			operators,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.DVSCentralScheduler.SyncUpdateOperators(noSendTxOpts,
-		operators,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.Wrap(err, "failed to send tx")
-	}
 
 	e.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 
@@ -104,7 +99,7 @@ func (e *EventRegistryRouterSyncUpdateOperators) Init(ctx context.Context) error
 		e.logger.Error("Failed to subscribe to events", "error", err)
 		return err
 	}
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
@@ -112,20 +107,19 @@ func (e *EventRegistryRouterSyncUpdateOperators) Listen(ctx context.Context) err
 	e.logger.Info("Listening for events")
 
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -135,3 +129,26 @@ func (e *EventRegistryRouterSyncUpdateOperators) Listen(ctx context.Context) err
 	}(ctx)
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventRegistryRouterSyncUpdateOperators) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.PellRegistryRouter.FilterSyncUpdateOperators(&bind.FilterOpts{Start: from.Block, Context: ctx})
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogPosition is the position of a log on the source chain
+type LogPosition struct {
+	Block uint64 `json:"block"`
+	Index uint   `json:"index"`
+}
+
+func logPosition(lg gethtypes.Log) LogPosition {
+	return LogPosition{Block: lg.BlockNumber, Index: lg.Index}
+}
+
+func (p LogPosition) before(other LogPosition) bool {
+	return p.Block < other.Block || (p.Block == other.Block && p.Index < other.Index)
+}
+
+// routePosition is where a route is in the logs of its source contract
+type routePosition struct {
+	mu sync.Mutex
+	// next is the first log not handled yet, logs before it are skipped
+	next    LogPosition
+	hasNext bool
+	// startBlock is the first block watched by a route that has not handled any log
+	startBlock uint64
+}
+
+// WithResume makes each route replay the logs from the position, by route name, returned by
+// ResumeFrom of the route it replaces, so the logs queued or emitted while it was stopped are forwarded
+func WithResume(positions map[string]LogPosition) Option {
+	return func(be *BaseEvent) {
+		if pos, ok := positions[be.eventName]; ok {
+			be.position.next = pos
+			be.position.hasNext = true
+		}
+	}
+}
+
+// ResumeFrom returns the first log the route has not handled, false if it never listened
+func (be *BaseEvent) ResumeFrom() (LogPosition, bool) {
+	be.position.mu.Lock()
+	defer be.position.mu.Unlock()
+	if be.position.hasNext {
+		return be.position.next, true
+	}
+	if be.position.startBlock > 0 {
+		return LogPosition{Block: be.position.startBlock}, true
+	}
+	return LogPosition{}, false
+}
+
+// replayFrom returns where the logs missed by the subscription start. The first call of a
+// route without a position records the head block instead, there is nothing to replay then.
+func (be *BaseEvent) replayFrom(ctx context.Context) (LogPosition, bool) {
+	if ctx.Err() != nil {
+		return LogPosition{}, false
+	}
+	if from, ok := be.ResumeFrom(); ok {
+		return from, true
+	}
+	head, err := be.rpcClient.BlockNumber(ctx)
+	if err != nil {
+		be.logger.Error("failed to get head block, logs missed before it are not replayed", "error", err)
+		return LogPosition{}, false
+	}
+	be.position.mu.Lock()
+	defer be.position.mu.Unlock()
+	be.position.startBlock = head + 1
+	return LogPosition{}, false
+}
+
+// handle processes a log of the source contract once, a log replayed and also delivered
+// by the subscription is skipped the second time
+func (be *BaseEvent) handle(ctx context.Context, src gethtypes.Log, process func(ctx context.Context) error) {
+	pos := logPosition(src)
+	be.position.mu.Lock()
+	handled := be.position.hasNext && pos.before(be.position.next)
+	be.position.mu.Unlock()
+	if handled {
+		be.logger.Debug("log already handled, skip", "block", pos.Block, "index", pos.Index)
+		return
+	}
+
+	be.eventSeen(src)
+	err := process(be.detach(ctx))
+	be.eventDone(err)
+
+	be.position.mu.Lock()
+	be.position.next = LogPosition{Block: pos.Block, Index: pos.Index + 1}
+	be.position.hasNext = true
+	be.position.mu.Unlock()
+
+	if err != nil {
+		be.logger.Error("Failed to process to events:", "error", err)
+	}
+}
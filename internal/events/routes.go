@@ -0,0 +1,69 @@
+package events
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+// RouteName returns the name routes are configured and reported by
+func RouteName(event IEvents) string {
+	return event.(interface{ baseEvent() *BaseEvent }).baseEvent().eventName
+}
+
+// FilterRoutes returns the events of routes, every event if routes is empty. Unknown route names are an error.
+func FilterRoutes(events []IEvents, routes []string) ([]IEvents, error) {
+	if len(routes) == 0 {
+		return events, nil
+	}
+
+	byName := make(map[string]IEvents, len(events))
+	for _, event := range events {
+		byName[RouteName(event)] = event
+	}
+
+	var res []IEvents
+	var unknown []string
+	for _, route := range routes {
+		event, ok := byName[route]
+		if !ok {
+			unknown = append(unknown, route)
+			continue
+		}
+		res = append(res, event)
+	}
+	if len(unknown) > 0 {
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, errors.Errorf("unknown routes %s, known routes are %s",
+			strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+	return res, nil
+}
+
+// ConnectorContracts returns the target contracts of routes, of every route if routes is empty
+func ConnectorContracts(routes []string) ([]string, error) {
+	events, err := FilterRoutes(GetAllEvents(nil, nil, nil, nil, nil, nil, log.NewNopLogger()), routes)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var contracts []string
+	for _, event := range events {
+		for _, target := range event.(interface{ baseEvent() *BaseEvent }).baseEvent().targets {
+			if !seen[target.Contract] {
+				seen[target.Contract] = true
+				contracts = append(contracts, target.Contract)
+			}
+		}
+	}
+	sort.Strings(contracts)
+	return contracts, nil
+}
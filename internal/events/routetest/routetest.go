@@ -0,0 +1,313 @@
+// Package routetest has the source events of every route and the forward each one makes,
+// shared by the route tests against a fake client and the e2e test on a simulated chain.
+package routetest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/pelldelegationmanager.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/pellstrategymanager.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/registryrouter.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/pell_evm/registry/stakeregistryrouter.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/service_evm/omnioperatorsharesmanager.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/service_evm/registryinteractor.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v2/strategymanager.sol"
+	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v3/delegationmanager.sol"
+	"github.com/0xPellNetwork/pell-middleware-contracts/pkg/src/centralscheduler.sol"
+	"github.com/0xPellNetwork/pell-middleware-contracts/pkg/src/operatorstakemanager.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+)
+
+var (
+	Staker   = gethcommon.HexToAddress("0x00000000000000000000000000000000000b0001")
+	Operator = gethcommon.HexToAddress("0x00000000000000000000000000000000000b0002")
+	Token    = gethcommon.HexToAddress("0x00000000000000000000000000000000000b0003")
+	Strategy = gethcommon.HexToAddress("0x00000000000000000000000000000000000b0004")
+	Shares   = big.NewInt(1000)
+	// SrcChainID is the staking chain of the events raised on the pell chain
+	SrcChainID = big.NewInt(7)
+)
+
+// Event is an event raised by a source contract
+type Event struct {
+	ABI  *abi.ABI
+	Name string
+	// Args are in the order of the inputs of the event, nil for the values of ethtest.Values
+	Args []any
+}
+
+// Case is a route, the events that trigger it and the forward it makes
+type Case struct {
+	Route  string
+	Source string
+	// Events are raised in one tx, the route subscribes to the last one
+	Events []Event
+
+	Target    string
+	TargetABI *abi.ABI
+	Method    string
+	// Args are the args of the forward from the chain id of the emulator and the args of the
+	// events by name, converted to the inputs of the method by ForwardArgs
+	Args func(chainID *big.Int, events []map[string]any) []any
+}
+
+// Logs returns the logs of the events of c raised by its source contract, and the args of
+// each event by name
+func (c Case) Logs(t testing.TB) ([]gethtypes.Log, []map[string]any) {
+	return Logs(t, gethcommon.HexToAddress(c.Source), c.Events...)
+}
+
+// ForwardArgs returns the method of the forward of c and its args for chainID and the args of events
+func (c Case) ForwardArgs(t testing.TB, chainID *big.Int, events []map[string]any) (abi.Method, []any) {
+	method, ok := c.TargetABI.Methods[c.Method]
+	require.True(t, ok, "method %s not in the abi", c.Method)
+	args := c.Args(chainID, events)
+	require.Len(t, args, len(method.Inputs), "arguments of %s", method.Sig)
+	for i, input := range method.Inputs {
+		var err error
+		args[i], err = ethtest.Convert(input.Type, args[i])
+		require.NoError(t, err, "argument %s of %s", input.Name, method.Sig)
+	}
+	return method, args
+}
+
+// Logs returns the logs of events raised by the contract at address, and the args of each
+// event by name
+func Logs(t testing.TB, address gethcommon.Address, events ...Event) ([]gethtypes.Log, []map[string]any) {
+	var logs []gethtypes.Log
+	var named []map[string]any
+	for i, e := range events {
+		ev, ok := e.ABI.Events[e.Name]
+		require.True(t, ok, "event %s not in the abi", e.Name)
+		args := e.Args
+		if args == nil {
+			var err error
+			args, err = ethtest.Values(ev.Inputs, uint64(i+1))
+			require.NoError(t, err)
+		}
+		lg, err := ethtest.EventLog(ev, address, args...)
+		require.NoError(t, err)
+		logs = append(logs, lg)
+		named = append(named, ethtest.Named(ev.Inputs, args))
+	}
+	return logs, named
+}
+
+// MustABI returns the abi of a binding
+func MustABI(t testing.TB, getAbi func() (*abi.ABI, error)) *abi.ABI {
+	parsed, err := getAbi()
+	require.NoError(t, err)
+	return parsed
+}
+
+// Contracts returns the addresses of the contracts of address
+func Contracts(address *config.ContractAddress) []gethcommon.Address {
+	return []gethcommon.Address{
+		gethcommon.HexToAddress(address.PellDelegationManager),
+		gethcommon.HexToAddress(address.PellRegistryRouter),
+		gethcommon.HexToAddress(address.PellRegistryInteractor),
+		gethcommon.HexToAddress(address.PellStrategyManager),
+		gethcommon.HexToAddress(address.PellStakeRegistryRouter),
+		gethcommon.HexToAddress(address.StakingStrategyManager),
+		gethcommon.HexToAddress(address.StakingDelegationManager),
+		gethcommon.HexToAddress(address.ServiceOmniOperatorSharesManager),
+		gethcommon.HexToAddress(address.DVSCentralScheduler),
+		gethcommon.HexToAddress(address.DVSOperatorStakeManager),
+	}
+}
+
+// Cases returns the case of every route for the contracts of address
+func Cases(t testing.TB, address *config.ContractAddress) []Case {
+	stakingStrategyABI := MustABI(t, strategymanager.StrategyManagerMetaData.GetAbi)
+	stakingDelegationABI := MustABI(t, delegationmanager.DelegationManagerMetaData.GetAbi)
+	pellStrategyABI := MustABI(t, pellstrategymanager.PellStrategyManagerMetaData.GetAbi)
+	pellDelegationABI := MustABI(t, pelldelegationmanager.PellDelegationManagerMetaData.GetAbi)
+	omniABI := MustABI(t, omnioperatorsharesmanager.OmniOperatorSharesManagerMetaData.GetAbi)
+	registryRouterABI := MustABI(t, registryrouter.RegistryRouterMetaData.GetAbi)
+	stakeRegistryRouterABI := MustABI(t, stakeregistryrouter.StakeRegistryRouterMetaData.GetAbi)
+	registryInteractorABI := MustABI(t, registryinteractor.RegistryInteractorMetaData.GetAbi)
+	centralSchedulerABI := MustABI(t, centralscheduler.CentralSchedulerMetaData.GetAbi)
+	operatorStakeManagerABI := MustABI(t, operatorstakemanager.OperatorStakeManagerMetaData.GetAbi)
+
+	return []Case{
+		{
+			Route:     "Deposit",
+			Source:    address.StakingStrategyManager,
+			Events:    []Event{{stakingStrategyABI, "Deposit", []any{Staker, Token, Strategy, Shares}}},
+			Target:    address.PellStrategyManager,
+			TargetABI: pellStrategyABI,
+			Method:    "syncDepositState",
+			Args: func(chainID *big.Int, _ []map[string]any) []any {
+				return []any{chainID, Staker, Strategy, Shares}
+			},
+		},
+		{
+			Route:     "StakerDelegated",
+			Source:    address.StakingDelegationManager,
+			Events:    []Event{{stakingDelegationABI, "StakerDelegated", []any{Staker, Operator}}},
+			Target:    address.PellDelegationManager,
+			TargetABI: pellDelegationABI,
+			Method:    "syncDelegateState",
+			Args: func(chainID *big.Int, _ []map[string]any) []any {
+				return []any{chainID, Staker, Operator}
+			},
+		},
+		{
+			Route:     "StakerUndelegated",
+			Source:    address.StakingDelegationManager,
+			Events:    []Event{{stakingDelegationABI, "StakerUndelegated", []any{Staker, Operator}}},
+			Target:    address.PellDelegationManager,
+			TargetABI: pellDelegationABI,
+			Method:    "syncUndelegateState",
+			Args: func(chainID *big.Int, _ []map[string]any) []any {
+				return []any{chainID, Staker}
+			},
+		},
+		{
+			Route:     "StakingWithdrawalQueued",
+			Source:    address.StakingDelegationManager,
+			Events:    []Event{{ABI: stakingDelegationABI, Name: "WithdrawalQueued"}},
+			Target:    address.PellDelegationManager,
+			TargetABI: pellDelegationABI,
+			Method:    "syncWithdrawalState",
+			Args: func(chainID *big.Int, events []map[string]any) []any {
+				withdrawal := events[0]["Withdrawal"]
+				return []any{
+					chainID, ethtest.Field(withdrawal, "Staker"), ethtest.Field(withdrawal, "DelegatedTo"),
+					map[string]any{
+						"Strategies": ethtest.Field(withdrawal, "Strategies"),
+						"Shares":     ethtest.Field(withdrawal, "Shares"),
+					},
+				}
+			},
+		},
+		{
+			Route:  "OperatorSharesIncreased",
+			Source: address.PellDelegationManager,
+			Events: []Event{{pellDelegationABI, "OperatorSharesIncreased",
+				[]any{SrcChainID, Operator, Staker, Strategy, Shares}}},
+			Target:    address.ServiceOmniOperatorSharesManager,
+			TargetABI: omniABI,
+			Method:    "batchSyncIncreaseDelegatedShares",
+			Args: func(*big.Int, []map[string]any) []any {
+				return []any{
+					[]*big.Int{SrcChainID}, []gethcommon.Address{Operator},
+					[]gethcommon.Address{Strategy}, []*big.Int{Shares},
+				}
+			},
+		},
+		{
+			Route:  "OperatorSharesDecreased",
+			Source: address.PellDelegationManager,
+			Events: []Event{{pellDelegationABI, "OperatorSharesDecreased",
+				[]any{SrcChainID, Operator, Staker, Strategy, Shares}}},
+			Target:    address.ServiceOmniOperatorSharesManager,
+			TargetABI: omniABI,
+			Method:    "batchSyncDecreaseDelegatedShares",
+			Args: func(*big.Int, []map[string]any) []any {
+				return []any{
+					[]*big.Int{SrcChainID}, []gethcommon.Address{Operator},
+					[]gethcommon.Address{Strategy}, []*big.Int{Shares},
+				}
+			},
+		},
+		{
+			Route:     "OperatorRegistered",
+			Source:    address.PellDelegationManager,
+			Events:    []Event{{ABI: pellDelegationABI, Name: "OperatorRegistered"}},
+			Target:    address.StakingDelegationManager,
+			TargetABI: stakingDelegationABI,
+			Method:    "syncRegisterAsOperator",
+			Args: func(_ *big.Int, events []map[string]any) []any {
+				details := events[0]["OperatorDetails"]
+				// the event has no earnings receiver, it is forwarded as the zero address
+				return []any{events[0]["Operator"], map[string]any{
+					"DelegationApprover": ethtest.Field(details, "DelegationApprover"),
+					"StakerOptOutWindow": ethtest.Field(details, "StakerOptOutWindow"),
+				}}
+			},
+		},
+		{
+			Route:     "SyncRegisterOperator",
+			Source:    address.PellRegistryRouter,
+			Events:    []Event{{ABI: registryRouterABI, Name: "SyncRegisterOperator"}},
+			Target:    address.DVSCentralScheduler,
+			TargetABI: centralSchedulerABI,
+			Method:    "syncRegisterOperator",
+			Args: func(_ *big.Int, events []map[string]any) []any {
+				params := events[0]["Params"]
+				return []any{events[0]["Operator"], events[0]["GroupNumbers"], map[string]any{
+					"PubkeyG1": ethtest.Field(params, "PubkeyG1"),
+					"PubkeyG2": ethtest.Field(params, "PubkeyG2"),
+				}}
+			},
+		},
+		{
+			Route:     "SyncUpdateOperators",
+			Source:    address.PellRegistryRouter,
+			Events:    []Event{{ABI: registryRouterABI, Name: "SyncUpdateOperators"}},
+			Target:    address.DVSCentralScheduler,
+			TargetABI: centralSchedulerABI,
+			Method:    "syncUpdateOperators",
+			Args: func(_ *big.Int, events []map[string]any) []any {
+				return []any{events[0]["Operators"]}
+			},
+		},
+		{
+			Route:     "SyncCreateGroup",
+			Source:    address.PellRegistryRouter,
+			Events:    []Event{{ABI: registryRouterABI, Name: "SyncCreateGroup"}},
+			Target:    address.DVSCentralScheduler,
+			TargetABI: centralSchedulerABI,
+			Method:    "syncCreateGroup",
+			Args: func(_ *big.Int, events []map[string]any) []any {
+				return []any{
+					events[0]["GroupNumber"], events[0]["OperatorSetParams"],
+					events[0]["MinimumStake"], events[0]["PoolParams"],
+				}
+			},
+		},
+		{
+			Route:     "SyncAddPools",
+			Source:    address.PellStakeRegistryRouter,
+			Events:    []Event{{ABI: stakeRegistryRouterABI, Name: "SyncAddPools"}},
+			Target:    address.DVSOperatorStakeManager,
+			TargetABI: operatorStakeManagerABI,
+			Method:    "syncAddPools",
+			Args: func(_ *big.Int, events []map[string]any) []any {
+				return []any{events[0]["GroupNumber"], events[0]["PoolParams"]}
+			},
+		},
+		{
+			Route:  "CentralSchedulerEvent",
+			Source: address.PellRegistryInteractor,
+			// the route reads the registration of the stake and ejection managers from its tx
+			Events: []Event{
+				{ABI: registryInteractorABI, Name: "RegisterStakeManagerToPell"},
+				{ABI: registryInteractorABI, Name: "RegisterEjectionManagerToPell"},
+				{ABI: registryInteractorABI, Name: "RegisterCentralSchedulerToPell"},
+			},
+			Target:    address.PellRegistryRouter,
+			TargetABI: registryRouterABI,
+			Method:    "addSupportedChain",
+			Args: func(chainID *big.Int, events []map[string]any) []any {
+				return []any{
+					map[string]any{
+						"ChainId":          chainID,
+						"CentralScheduler": events[2]["CentralScheduler"],
+						"EjectionManager":  events[1]["EjectionManager"],
+						"StakeManager":     events[0]["StakeManager"],
+					},
+					events[2]["DvsChainApproverSignature"],
+				}
+			},
+		},
+	}
+}
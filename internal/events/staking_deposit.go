@@ -7,13 +7,12 @@ import (
 
 	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v2/strategymanager.sol"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/pkg/errors"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventStakingDeposit struct {
@@ -34,7 +33,7 @@ func NewEventStakingDeposit(
 	eventName := "Deposit"
 	contractName := "StakingStrategyManager"
 
-	eventCh := make(chan *strategymanager.StrategyManagerDeposit)
+	eventCh := make(chan *strategymanager.StrategyManagerDeposit, eventQueueSize)
 
 	var res = &EventStakingDeposit{
 		BaseEvent: BaseEvent{
@@ -47,6 +46,7 @@ func NewEventStakingDeposit(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMPell, "PellStrategyManager", "SyncDepositState"),
 			},
@@ -67,23 +67,17 @@ func (e *EventStakingDeposit) process(
 		"Shares", event.Shares,
 	)
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.PellStrategyManager.SyncDepositState(opts,
+			e.chainID,
+			event.Staker,
+			event.Strategy,
+			event.Shares,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.PellStrategyManager.SyncDepositState(noSendTxOpts,
-		e.chainID,
-		event.Staker,
-		event.Strategy,
-		event.Shares,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.Wrap(err, "failed to send tx")
-	}
 
 	e.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 
@@ -97,27 +91,26 @@ func (e *EventStakingDeposit) Init(ctx context.Context) error {
 		e.logger.Error("Failed to subscribe to events", "error", err)
 		return err
 	}
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
 func (e *EventStakingDeposit) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -128,3 +121,26 @@ func (e *EventStakingDeposit) Listen(ctx context.Context) error {
 
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventStakingDeposit) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.StakingStrategyManager.FilterDeposit(&bind.FilterOpts{Start: from.Block, Context: ctx})
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
@@ -8,13 +8,12 @@ import (
 	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v3/delegationmanager.sol"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/pkg/errors"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventStakingStakerDelegated struct {
@@ -33,7 +32,7 @@ func NewEventStakingStakerDelegated(
 ) *EventStakingStakerDelegated {
 	eventName := "StakerDelegated"
 	contractName := ContractNameStakingDelegationManager
-	eventCh := make(chan *delegationmanager.DelegationManagerStakerDelegated)
+	eventCh := make(chan *delegationmanager.DelegationManagerStakerDelegated, eventQueueSize)
 	var res = &EventStakingStakerDelegated{
 		BaseEvent: BaseEvent{
 			srcEVM:      EVMStaking,
@@ -46,6 +45,7 @@ func NewEventStakingStakerDelegated(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMPell, "PellDelegationManager", "SyncDelegateState"),
 			},
@@ -63,22 +63,16 @@ func (e *EventStakingStakerDelegated) process(
 		"Staker", event.Staker,
 		"Operator", event.Operator,
 	)
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.PellDelegationManager.SyncDelegateState(opts,
+			e.chainID,
+			event.Staker,
+			event.Operator,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.PellDelegationManager.SyncDelegateState(noSendTxOpts,
-		e.chainID,
-		event.Staker,
-		event.Operator,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.Wrap(err, "failed to send tx")
-	}
 
 	e.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 	return nil
@@ -94,7 +88,7 @@ func (e *EventStakingStakerDelegated) Init(ctx context.Context) error {
 		e.logger.Error("Failed to subscribe to events", "error", err)
 		return err
 	}
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
@@ -103,20 +97,19 @@ func (e *EventStakingStakerDelegated) Init(ctx context.Context) error {
 func (e *EventStakingStakerDelegated) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -127,3 +120,26 @@ func (e *EventStakingStakerDelegated) Listen(ctx context.Context) error {
 
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventStakingStakerDelegated) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.StakingDelegationManager.FilterStakerDelegated(&bind.FilterOpts{Start: from.Block, Context: ctx}, nil, nil)
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
@@ -8,13 +8,12 @@ import (
 	"github.com/0xPellNetwork/contracts/pkg/contracts/staking_evm/core/v3/delegationmanager.sol"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/pkg/errors"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
-	"github.com/0xPellNetwork/pell-emulator/libs/utils"
 )
 
 type EventStakingStakerUndelegated struct {
@@ -33,7 +32,7 @@ func NewEventStakingStakerUndelegated(
 ) *EventStakingStakerUndelegated {
 	eventName := "StakerUndelegated"
 	contractName := ContractNameStakingDelegationManager
-	eventCh := make(chan *delegationmanager.DelegationManagerStakerUndelegated)
+	eventCh := make(chan *delegationmanager.DelegationManagerStakerUndelegated, eventQueueSize)
 	var res = &EventStakingStakerUndelegated{
 		BaseEvent: BaseEvent{
 			srcEVM:      EVMStaking,
@@ -46,6 +45,7 @@ func NewEventStakingStakerUndelegated(
 			wsBindings:  wsBindings,
 			rpcBindings: rpcBindings,
 			txMgr:       txMgr,
+			queueDepth:  func() int { return len(eventCh) },
 			targets: []EventTargetInfo{
 				newTarget(EVMPell, "PellDelegationManager", "SyncUndelegateState"),
 			},
@@ -64,21 +64,15 @@ func (e *EventStakingStakerUndelegated) process(
 		"Operator", event.Operator,
 	)
 
-	noSendTxOpts, err := e.txMgr.GetNoSendTxOpts()
+	receipt, err := e.forward(ctx, event.Raw, func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+		return e.rpcBindings.PellDelegationManager.SyncUndelegateState(opts,
+			e.chainID,
+			event.Staker,
+		)
+	})
 	if err != nil {
 		return err
 	}
-	tx, err := e.rpcBindings.PellDelegationManager.SyncUndelegateState(noSendTxOpts,
-		e.chainID,
-		event.Staker,
-	)
-	if err != nil {
-		return err
-	}
-	receipt, err := e.txMgr.Send(ctx, tx)
-	if err != nil {
-		return errors.Wrap(err, "failed to send tx")
-	}
 
 	e.logger.Info("tx successfully included", "txHash", receipt.TxHash.String())
 	return nil
@@ -95,7 +89,7 @@ func (e *EventStakingStakerUndelegated) Init(ctx context.Context) error {
 		return err
 	}
 
-	e.evtSub = sub
+	e.setSubscription(sub)
 	return nil
 }
 
@@ -105,20 +99,19 @@ func (e *EventStakingStakerUndelegated) Listen(ctx context.Context) error {
 	e.logger.Info("Listening for events")
 
 	go func(ctx context.Context) {
+		e.replay(ctx)
 		for {
 			select {
 			case event := <-e.evtCh:
-				err := e.process(ctx, event)
-				if err != nil {
-					e.logger.Error("Failed to process to events:", "error", err)
-				}
+				e.handle(ctx, event.Raw, func(ctx context.Context) error {
+					return e.process(ctx, event)
+				})
 			case err := <-e.evtSub.Err():
-				utils.LogSubError(e.logger, err)
-				time.Sleep(1 * time.Second)
+				e.resubscribe(ctx, err, e.Init)
+				e.replay(ctx)
 			case <-ctx.Done():
 				e.logger.Info("received unsubscribe signal, shutting down...")
 				e.evtSub.Unsubscribe()
-				close(e.evtCh)
 				return
 			default:
 				//fmt.Println("Waiting for events...")
@@ -128,3 +121,26 @@ func (e *EventStakingStakerUndelegated) Listen(ctx context.Context) error {
 	}(ctx)
 	return nil
 }
+
+// replay processes the logs the subscription missed since the route stopped or lost it
+func (e *EventStakingStakerUndelegated) replay(ctx context.Context) {
+	from, ok := e.replayFrom(ctx)
+	if !ok {
+		return
+	}
+	it, err := e.wsBindings.StakingDelegationManager.FilterStakerUndelegated(&bind.FilterOpts{Start: from.Block, Context: ctx}, nil, nil)
+	if err != nil {
+		e.logger.Error("failed to filter missed events", "error", err, "fromBlock", from.Block)
+		return
+	}
+	defer it.Close()
+	for it.Next() {
+		event := it.Event
+		e.handle(ctx, event.Raw, func(ctx context.Context) error {
+			return e.process(ctx, event)
+		})
+	}
+	if err := it.Error(); err != nil {
+		e.logger.Error("failed to replay missed events", "error", err, "fromBlock", from.Block)
+	}
+}
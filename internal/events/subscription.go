@@ -0,0 +1,36 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+var resubscribeInterval = 1 * time.Second
+
+// resubscribe replaces the subscription of the event after it failed with err,
+// init is the Init of the route and sets a new evtSub on success
+func (be *BaseEvent) resubscribe(ctx context.Context, err error, init func(ctx context.Context) error) {
+	be.logger.Error("subscription failed, resubscribing", "error", err)
+	be.subscriptionLost()
+	if be.evtSub != nil {
+		be.evtSub.Unsubscribe()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeInterval):
+		}
+
+		if err := init(ctx); err != nil {
+			be.logger.Error("failed to resubscribe", "error", err)
+			continue
+		}
+		if be.metrics != nil {
+			be.metrics.AddSubscriptionReconnect(be.eventName)
+		}
+		be.logger.Info("resubscribed")
+		return
+	}
+}
@@ -0,0 +1,93 @@
+package journal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	// StatusForwarded the target tx was included
+	StatusForwarded Status = "forwarded"
+	// StatusSkipped the target call reverted in simulation and was dead-lettered
+	StatusSkipped Status = "skipped"
+	// StatusFailed the forward failed and may be retried by the next event
+	StatusFailed Status = "failed"
+)
+
+// Entry is one record of the journal, it describes what happened to a single source log
+type Entry struct {
+	Time           time.Time `json:"time"`
+	Route          string    `json:"route"`
+	SrcTxHash      string    `json:"src_tx_hash"`
+	SrcLogIndex    uint      `json:"src_log_index"`
+	SrcBlockNumber uint64    `json:"src_block_number"`
+	Target         string    `json:"target"`
+	Status         Status    `json:"status"`
+	Reason         string    `json:"reason,omitempty"`
+	TargetTxHash   string    `json:"target_tx_hash,omitempty"`
+	// Reconciliation marks the corrective calls of the reconciler, they have no source log
+	Reconciliation bool `json:"reconciliation,omitempty"`
+}
+
+// Journal records the outcome of every forward
+type Journal interface {
+	Record(entry Entry) error
+}
+
+type nopJournal struct{}
+
+var _ Journal = (*nopJournal)(nil)
+
+func NewNopJournal() Journal {
+	return &nopJournal{}
+}
+
+func (j *nopJournal) Record(entry Entry) error {
+	return nil
+}
+
+// FileJournal appends entries as json lines to a file
+type FileJournal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+var _ Journal = (*FileJournal)(nil)
+
+// NewFileJournal opens (or creates) the journal file at path, parent dirs are created if missing
+func NewFileJournal(path string) (*FileJournal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileJournal{file: file}, nil
+}
+
+func (j *FileJournal) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.file.Write(append(line, '\n'))
+	return err
+}
+
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"github.com/0xPellNetwork/pell-emulator/config"
+)
+
+// pick returns the proxy address if it is set, the implementation otherwise
+func pick(proxy, implementation string) string {
+	if proxy != "" {
+		return proxy
+	}
+	return implementation
+}
+
+// deploymentField is a ContractAddress field and the address found for it in a deployment file
+type deploymentField struct {
+	name    string
+	field   *string
+	address string
+}
+
+// ApplyHardhatDeployments sets the contract addresses of addrs found in the hardhat deployment
+// outputs, pell or dvs may be nil. Proxies are preferred over implementations. It returns the
+// names of the contracts a given file should provide but does not, their address is left as is.
+// The PellRegistryRouter is created through the PellRegistryRouterFactory and is not in the
+// deployment outputs.
+func ApplyHardhatDeployments(
+	addrs *config.ContractAddress,
+	pell *TypesHardhatDeploymentsContractAddressPell,
+	dvs *TypesHardhatDeploymentsContractAddressDVS,
+) []string {
+	var fields []deploymentField
+	if pell != nil {
+		fields = append(fields,
+			deploymentField{"PellDelegationManager", &addrs.PellDelegationManager,
+				pick(pell.PellDelegationManagerProxy, pell.PellDelegationManagerImplementation)},
+			deploymentField{"PellRegistryInteractor", &addrs.PellRegistryInteractor, pell.RegistryInteractor},
+			deploymentField{"PellStrategyManager", &addrs.PellStrategyManager,
+				pick(pell.PellStrategyManagerProxy, pell.PellStrategyManagerImplementation)},
+			deploymentField{"StakingStrategyManager", &addrs.StakingStrategyManager,
+				pick(pell.StrategyManagerProxy, pell.StrategyManagerImplementation)},
+			deploymentField{"StakingDelegationManager", &addrs.StakingDelegationManager,
+				pick(pell.DelegationManagerProxy, pell.DelegationManagerImplementation)},
+			deploymentField{"ServiceOmniOperatorSharesManager", &addrs.ServiceOmniOperatorSharesManager,
+				pick(pell.OmniOperatorSharesManagerProxy, pell.OmniOperatorSharesManagerImplementation)},
+		)
+	}
+	if dvs != nil {
+		fields = append(fields,
+			deploymentField{"DVSCentralScheduler", &addrs.DVSCentralScheduler,
+				pick(dvs.CentralSchedulerProxy, dvs.CentralSchedulerImplementation)},
+			deploymentField{"DVSOperatorStakeManager", &addrs.DVSOperatorStakeManager,
+				pick(dvs.OperatorStakeManagerProxy, dvs.OperatorStakeManagerImplementation)},
+		)
+	}
+
+	var missing []string
+	for _, f := range fields {
+		if f.address == "" {
+			missing = append(missing, f.name)
+			continue
+		}
+		*f.field = f.address
+	}
+	return missing
+}
@@ -0,0 +1,161 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+)
+
+const (
+	hardhatPellDeployments = `{
+  "PellDelegationManager-Implementation": "0x1000000000000000000000000000000000000001",
+  "PellDelegationManager-Proxy": "0x1000000000000000000000000000000000000002",
+  "PellRegistryRouter-Implementation": "0x1000000000000000000000000000000000000003",
+  "PellRegistryRouterFactory": "0x1000000000000000000000000000000000000004",
+  "RegistryInteractor": "0x1000000000000000000000000000000000000005",
+  "PellStrategyManager-Implementation": "0x1000000000000000000000000000000000000006",
+  "PellStrategyManager-Proxy": "0x1000000000000000000000000000000000000007",
+  "StrategyManager-Implementation": "0x1000000000000000000000000000000000000008",
+  "StrategyManager-Proxy": "0x1000000000000000000000000000000000000009",
+  "DelegationManager-Implementation": "0x100000000000000000000000000000000000000a",
+  "DelegationManager-Proxy": "0x100000000000000000000000000000000000000b",
+  "OmniOperatorSharesManager-Implementation": "0x100000000000000000000000000000000000000c",
+  "OmniOperatorSharesManager-Proxy": "0x100000000000000000000000000000000000000d"
+}`
+	hardhatDVSDeployments = `{
+  "CentralScheduler-Implementation": "0x2000000000000000000000000000000000000001",
+  "CentralScheduler-Proxy": "0x2000000000000000000000000000000000000002",
+  "OperatorStakeManager-Implementation": "0x2000000000000000000000000000000000000003",
+  "OperatorStakeManager-Proxy": "0x2000000000000000000000000000000000000004"
+}`
+	// implementations only, as deployed without proxies
+	hardhatDVSImplementations = `{
+  "CentralScheduler-Implementation": "0x2000000000000000000000000000000000000001",
+  "OperatorStakeManager-Implementation": "0x2000000000000000000000000000000000000003"
+}`
+	// CentralScheduler renamed by a newer deployment script, with an artifact the emulator does not know
+	hardhatDVSRenamed = `{
+  "DVSCentralScheduler-Proxy": "0x2000000000000000000000000000000000000002",
+  "OperatorStakeManager-Proxy": "0x2000000000000000000000000000000000000004",
+  "RewardsCoordinator-Proxy": "0x2000000000000000000000000000000000000005"
+}`
+)
+
+const configuredAddress = "0x9000000000000000000000000000000000000009"
+
+func TestApplyHardhatDeployments(t *testing.T) {
+	var tests = map[string]struct {
+		pell        string
+		dvs         string
+		wantMissing []string
+		want        config.ContractAddress
+	}{
+		"proxies of pell and dvs": {
+			pell: hardhatPellDeployments,
+			dvs:  hardhatDVSDeployments,
+			want: config.ContractAddress{
+				PellDelegationManager:            "0x1000000000000000000000000000000000000002",
+				PellRegistryRouter:               configuredAddress,
+				PellRegistryInteractor:           "0x1000000000000000000000000000000000000005",
+				PellStrategyManager:              "0x1000000000000000000000000000000000000007",
+				StakingStrategyManager:           "0x1000000000000000000000000000000000000009",
+				StakingDelegationManager:         "0x100000000000000000000000000000000000000b",
+				ServiceOmniOperatorSharesManager: "0x100000000000000000000000000000000000000d",
+				DVSCentralScheduler:              "0x2000000000000000000000000000000000000002",
+				DVSOperatorStakeManager:          "0x2000000000000000000000000000000000000004",
+			},
+		},
+		"implementations without proxies": {
+			dvs: hardhatDVSImplementations,
+			want: config.ContractAddress{
+				PellDelegationManager:            configuredAddress,
+				PellRegistryRouter:               configuredAddress,
+				PellRegistryInteractor:           configuredAddress,
+				PellStrategyManager:              configuredAddress,
+				StakingStrategyManager:           configuredAddress,
+				StakingDelegationManager:         configuredAddress,
+				ServiceOmniOperatorSharesManager: configuredAddress,
+				DVSCentralScheduler:              "0x2000000000000000000000000000000000000001",
+				DVSOperatorStakeManager:          "0x2000000000000000000000000000000000000003",
+			},
+		},
+		"renamed and unknown artifacts": {
+			dvs:         hardhatDVSRenamed,
+			wantMissing: []string{"DVSCentralScheduler"},
+			want: config.ContractAddress{
+				PellDelegationManager:            configuredAddress,
+				PellRegistryRouter:               configuredAddress,
+				PellRegistryInteractor:           configuredAddress,
+				PellStrategyManager:              configuredAddress,
+				StakingStrategyManager:           configuredAddress,
+				StakingDelegationManager:         configuredAddress,
+				ServiceOmniOperatorSharesManager: configuredAddress,
+				DVSCentralScheduler:              configuredAddress,
+				DVSOperatorStakeManager:          "0x2000000000000000000000000000000000000004",
+			},
+		},
+		"empty pell deployments": {
+			pell: `{}`,
+			wantMissing: []string{
+				"PellDelegationManager", "PellRegistryInteractor", "PellStrategyManager",
+				"StakingStrategyManager", "StakingDelegationManager", "ServiceOmniOperatorSharesManager",
+			},
+			want: configuredContractAddress(),
+		},
+		"no deployments": {
+			want: configuredContractAddress(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			var pell *TypesHardhatDeploymentsContractAddressPell
+			if tt.pell != "" {
+				var err error
+				pell, err = LoadTypesHardhatDeploymentsContractAddressPellFromFile(writeTestFile(t, dir, "pell.json", tt.pell))
+				require.NoError(t, err)
+			}
+			var dvs *TypesHardhatDeploymentsContractAddressDVS
+			if tt.dvs != "" {
+				var err error
+				dvs, err = LoadTypesHardhatDeploymentsContractAddressDVSFromFile(writeTestFile(t, dir, "dvs.json", tt.dvs))
+				require.NoError(t, err)
+			}
+
+			addrs := configuredContractAddress()
+			missing := ApplyHardhatDeployments(&addrs, pell, dvs)
+			assert.Equal(t, tt.wantMissing, missing)
+			assert.Equal(t, tt.want, addrs)
+		})
+	}
+}
+
+// configuredContractAddress is a config whose every contract is set, so a field left as is shows
+func configuredContractAddress() config.ContractAddress {
+	return config.ContractAddress{
+		PellDelegationManager:            configuredAddress,
+		PellRegistryRouter:               configuredAddress,
+		PellRegistryInteractor:           configuredAddress,
+		PellStrategyManager:              configuredAddress,
+		StakingStrategyManager:           configuredAddress,
+		StakingDelegationManager:         configuredAddress,
+		ServiceOmniOperatorSharesManager: configuredAddress,
+		DVSCentralScheduler:              configuredAddress,
+		DVSOperatorStakeManager:          configuredAddress,
+	}
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	return file
+}
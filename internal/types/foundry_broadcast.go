@@ -0,0 +1,198 @@
+package types
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/utils"
+)
+
+const foundryRunLatest = "run-latest.json"
+
+// FoundryBroadcast is the part of a forge script broadcast artifact,
+// broadcast/<script>/<chainId>/run-latest.json, that records the deployed contracts
+type FoundryBroadcast struct {
+	Transactions []FoundryBroadcastTransaction `json:"transactions"`
+	Chain        uint64                        `json:"chain"`
+
+	// File the broadcast was loaded from
+	File string `json:"-"`
+}
+
+type FoundryBroadcastTransaction struct {
+	TransactionType string   `json:"transactionType"`
+	ContractName    string   `json:"contractName"`
+	ContractAddress string   `json:"contractAddress"`
+	Arguments       []string `json:"arguments"`
+}
+
+// foundryContractFields maps the contract names of the deployment scripts to the ContractAddress fields
+var foundryContractFields = map[string]string{
+	"PellDelegationManager":     "PellDelegationManager",
+	"PellRegistryRouter":        "PellRegistryRouter",
+	"RegistryInteractor":        "PellRegistryInteractor",
+	"PellStrategyManager":       "PellStrategyManager",
+	"StrategyManager":           "StakingStrategyManager",
+	"DelegationManager":         "StakingDelegationManager",
+	"OmniOperatorSharesManager": "ServiceOmniOperatorSharesManager",
+	"CentralScheduler":          "DVSCentralScheduler",
+	"OperatorStakeManager":      "DVSOperatorStakeManager",
+}
+
+// LoadFoundryBroadcasts loads the run-latest.json of chainID of every script in broadcastDir.
+// broadcastDir is either the broadcast dir of a foundry project or the dir of a single script.
+func LoadFoundryBroadcasts(broadcastDir string, chainID uint64) ([]*FoundryBroadcast, error) {
+	chain := strconv.FormatUint(chainID, 10)
+	var files []string
+	for _, pattern := range []string{
+		filepath.Join(broadcastDir, "*", chain, foundryRunLatest),
+		filepath.Join(broadcastDir, chain, foundryRunLatest),
+	} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("no %s for chain id %s in %s", foundryRunLatest, chain, broadcastDir)
+	}
+
+	var broadcasts []*FoundryBroadcast
+	for _, file := range files {
+		var data FoundryBroadcast
+		if err := utils.DecodeJSONFromFile(file, &data); err != nil {
+			return nil, errors.Wrapf(err, "failed to load %s", file)
+		}
+		if data.Chain != 0 && data.Chain != chainID {
+			return nil, errors.Errorf("%s is a broadcast of chain id %d, not %d", file, data.Chain, chainID)
+		}
+		data.File = file
+		broadcasts = append(broadcasts, &data)
+	}
+	return broadcasts, nil
+}
+
+// foundryCandidate is a deployed address found for a ContractAddress field
+type foundryCandidate struct {
+	address string
+	proxy   bool
+	source  string
+}
+
+// ApplyFoundryBroadcasts sets the contract addresses of addrs deployed in broadcasts. A contract
+// is matched by its name, a proxy by the implementation it was constructed with, and proxies are
+// preferred over implementations. It returns the fields no contract was found for, their address
+// is left as is, and fails if a field matches more than one address.
+func ApplyFoundryBroadcasts(addrs *config.ContractAddress, broadcasts []*FoundryBroadcast) ([]string, error) {
+	// implementations by address, to resolve the proxies, which may be deployed by another script
+	names := make(map[string]string)
+	for _, b := range broadcasts {
+		for _, tx := range b.Transactions {
+			if isFoundryCreate(tx) {
+				names[strings.ToLower(tx.ContractAddress)] = tx.ContractName
+			}
+		}
+	}
+
+	candidates := make(map[string][]foundryCandidate)
+	for _, b := range broadcasts {
+		for _, tx := range b.Transactions {
+			if !isFoundryCreate(tx) {
+				continue
+			}
+			name, proxy := tx.ContractName, false
+			if strings.Contains(name, "Proxy") && len(tx.Arguments) > 0 {
+				if impl, ok := names[strings.ToLower(tx.Arguments[0])]; ok {
+					name, proxy = impl, true
+				}
+			}
+			field, ok := foundryContractFields[name]
+			if !ok {
+				continue
+			}
+			source := fmt.Sprintf("%s %s in %s", tx.ContractName, tx.ContractAddress, b.File)
+			candidates[field] = append(candidates[field], foundryCandidate{tx.ContractAddress, proxy, source})
+		}
+	}
+
+	fields := contractAddressFields(addrs)
+	var missing, ambiguous []string
+	for _, field := range sortedFoundryFields() {
+		address, sources := pickFoundryCandidate(candidates[field])
+		switch {
+		case len(sources) > 1:
+			ambiguous = append(ambiguous, fmt.Sprintf("%s matches %s", field, strings.Join(sources, ", ")))
+		case address == "":
+			missing = append(missing, field)
+		default:
+			*fields[field] = address
+		}
+	}
+	if len(ambiguous) > 0 {
+		return missing, errors.Errorf("ambiguous contracts in the foundry broadcasts: %s", strings.Join(ambiguous, "; "))
+	}
+	return missing, nil
+}
+
+func isFoundryCreate(tx FoundryBroadcastTransaction) bool {
+	return (tx.TransactionType == "CREATE" || tx.TransactionType == "CREATE2") && tx.ContractAddress != ""
+}
+
+// pickFoundryCandidate returns the address of the proxies, or of the implementations if there is
+// no proxy. More than one distinct address is returned as the sources to report.
+func pickFoundryCandidate(candidates []foundryCandidate) (string, []string) {
+	var proxies []foundryCandidate
+	for _, c := range candidates {
+		if c.proxy {
+			proxies = append(proxies, c)
+		}
+	}
+	if len(proxies) > 0 {
+		candidates = proxies
+	}
+
+	var address string
+	var sources []string
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		if key := strings.ToLower(c.address); !seen[key] {
+			seen[key] = true
+			address = c.address
+			sources = append(sources, c.source)
+		}
+	}
+	if len(sources) > 1 {
+		return "", sources
+	}
+	return address, nil
+}
+
+func sortedFoundryFields() []string {
+	fields := make([]string, 0, len(foundryContractFields))
+	for _, field := range foundryContractFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func contractAddressFields(addrs *config.ContractAddress) map[string]*string {
+	return map[string]*string{
+		"PellDelegationManager":            &addrs.PellDelegationManager,
+		"PellRegistryRouter":               &addrs.PellRegistryRouter,
+		"PellRegistryInteractor":           &addrs.PellRegistryInteractor,
+		"PellStrategyManager":              &addrs.PellStrategyManager,
+		"StakingStrategyManager":           &addrs.StakingStrategyManager,
+		"StakingDelegationManager":         &addrs.StakingDelegationManager,
+		"ServiceOmniOperatorSharesManager": &addrs.ServiceOmniOperatorSharesManager,
+		"DVSCentralScheduler":              &addrs.DVSCentralScheduler,
+		"DVSOperatorStakeManager":          &addrs.DVSOperatorStakeManager,
+	}
+}
@@ -0,0 +1,219 @@
+package types
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+)
+
+// foundryCreate is the broadcast transaction of a contract created at address
+func foundryCreate(name, address string, args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = fmt.Sprintf("%q", arg)
+	}
+	return fmt.Sprintf(`{"transactionType": "CREATE", "contractName": %q, "contractAddress": %q, "arguments": [%s]}`,
+		name, address, strings.Join(quoted, ", "))
+}
+
+func foundryRun(chainID uint64, txs ...string) string {
+	return fmt.Sprintf(`{"chain": %d, "transactions": [%s]}`, chainID, strings.Join(txs, ", "))
+}
+
+func TestLoadFoundryBroadcasts(t *testing.T) {
+	var tests = map[string]struct {
+		files     map[string]string
+		dir       string
+		wantFiles []string
+		wantErr   string
+	}{
+		"broadcast dir of a project": {
+			files: map[string]string{
+				"broadcast/DeployPell.s.sol/31337/run-latest.json": foundryRun(31337),
+				"broadcast/DeployDVS.s.sol/31337/run-latest.json":  foundryRun(31337),
+				"broadcast/DeployDVS.s.sol/1/run-latest.json":      foundryRun(1),
+				"broadcast/DeployDVS.s.sol/31337/run-1.json":       foundryRun(31337),
+			},
+			dir: "broadcast",
+			wantFiles: []string{
+				"broadcast/DeployDVS.s.sol/31337/run-latest.json",
+				"broadcast/DeployPell.s.sol/31337/run-latest.json",
+			},
+		},
+		"dir of a single script": {
+			files: map[string]string{
+				"broadcast/DeployPell.s.sol/31337/run-latest.json": foundryRun(31337),
+			},
+			dir:       "broadcast/DeployPell.s.sol",
+			wantFiles: []string{"broadcast/DeployPell.s.sol/31337/run-latest.json"},
+		},
+		"no broadcast of the chain": {
+			files: map[string]string{
+				"broadcast/DeployPell.s.sol/1/run-latest.json": foundryRun(1),
+			},
+			dir:     "broadcast",
+			wantErr: "no run-latest.json for chain id 31337",
+		},
+		"broadcast of another chain": {
+			files: map[string]string{
+				"broadcast/DeployPell.s.sol/31337/run-latest.json": foundryRun(1),
+			},
+			dir:     "broadcast",
+			wantErr: "is a broadcast of chain id 1, not 31337",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			for file, content := range tt.files {
+				writeTestFile(t, root, file, content)
+			}
+
+			broadcasts, err := LoadFoundryBroadcasts(filepath.Join(root, tt.dir), 31337)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var files []string
+			for _, b := range broadcasts {
+				rel, err := filepath.Rel(root, b.File)
+				require.NoError(t, err)
+				files = append(files, filepath.ToSlash(rel))
+			}
+			assert.ElementsMatch(t, tt.wantFiles, files)
+		})
+	}
+}
+
+func TestApplyFoundryBroadcasts(t *testing.T) {
+	const (
+		delegationImpl   = "0x1000000000000000000000000000000000000001"
+		delegationProxy  = "0x1000000000000000000000000000000000000002"
+		delegationProxy2 = "0x1000000000000000000000000000000000000003"
+		schedulerImpl    = "0x2000000000000000000000000000000000000001"
+		schedulerProxy   = "0x2000000000000000000000000000000000000002"
+		stakeManagerImpl = "0x2000000000000000000000000000000000000003"
+		emptyContract    = "0x3000000000000000000000000000000000000001"
+	)
+
+	var tests = map[string]struct {
+		runs        []string
+		wantMissing []string
+		wantErr     string
+		want        map[string]string
+	}{
+		"proxies win over implementations": {
+			runs: []string{foundryRun(31337,
+				foundryCreate("DelegationManager", delegationImpl),
+				foundryCreate("TransparentUpgradeableProxy", delegationProxy, delegationImpl, emptyContract, "0x"),
+				foundryCreate("CentralScheduler", schedulerImpl),
+				foundryCreate("ERC1967Proxy", schedulerProxy, schedulerImpl, "0x"),
+				foundryCreate("OperatorStakeManager", stakeManagerImpl),
+			)},
+			want: map[string]string{
+				"StakingDelegationManager": delegationProxy,
+				"DVSCentralScheduler":      schedulerProxy,
+				"DVSOperatorStakeManager":  stakeManagerImpl,
+			},
+		},
+		"proxy of an implementation deployed by another script": {
+			runs: []string{
+				foundryRun(31337, foundryCreate("CentralScheduler", schedulerImpl)),
+				foundryRun(31337, foundryCreate("ERC1967Proxy", schedulerProxy, schedulerImpl, "0x")),
+			},
+			want: map[string]string{"DVSCentralScheduler": schedulerProxy},
+		},
+		"proxy of an unknown implementation": {
+			runs: []string{foundryRun(31337,
+				foundryCreate("TransparentUpgradeableProxy", delegationProxy, emptyContract, emptyContract, "0x"),
+				foundryCreate("DelegationManager", delegationImpl),
+			)},
+			want: map[string]string{"StakingDelegationManager": delegationImpl},
+		},
+		"same contract in two broadcasts": {
+			runs: []string{
+				foundryRun(31337, foundryCreate("CentralScheduler", schedulerImpl)),
+				foundryRun(31337, foundryCreate("CentralScheduler", strings.ToUpper(schedulerImpl[:2])+schedulerImpl[2:])),
+			},
+			want: map[string]string{"DVSCentralScheduler": schedulerImpl},
+		},
+		"unknown and renamed contracts": {
+			runs: []string{foundryRun(31337,
+				foundryCreate("EmptyContract", emptyContract),
+				foundryCreate("DVSCentralScheduler", schedulerImpl),
+			)},
+		},
+		"calls are not deployments": {
+			runs: []string{foundryRun(31337,
+				`{"transactionType": "CALL", "contractName": "CentralScheduler", "contractAddress": "`+schedulerImpl+`"}`,
+			)},
+		},
+		"two proxies of one implementation": {
+			runs: []string{foundryRun(31337,
+				foundryCreate("DelegationManager", delegationImpl),
+				foundryCreate("TransparentUpgradeableProxy", delegationProxy, delegationImpl, emptyContract, "0x"),
+				foundryCreate("TransparentUpgradeableProxy", delegationProxy2, delegationImpl, emptyContract, "0x"),
+			)},
+			wantErr: "StakingDelegationManager matches TransparentUpgradeableProxy " + delegationProxy,
+		},
+		"two implementations": {
+			runs: []string{
+				foundryRun(31337, foundryCreate("CentralScheduler", schedulerImpl)),
+				foundryRun(31337, foundryCreate("CentralScheduler", schedulerProxy)),
+			},
+			wantErr: "DVSCentralScheduler matches CentralScheduler " + schedulerImpl,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var broadcasts []*FoundryBroadcast
+			for i, run := range tt.runs {
+				dir := t.TempDir()
+				writeTestFile(t, dir, fmt.Sprintf("Deploy%d.s.sol/31337/run-latest.json", i), run)
+				loaded, err := LoadFoundryBroadcasts(dir, 31337)
+				require.NoError(t, err)
+				broadcasts = append(broadcasts, loaded...)
+			}
+
+			addrs := configuredContractAddress()
+			missing, err := ApplyFoundryBroadcasts(&addrs, broadcasts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			want := configuredContractAddress()
+			fields := contractAddressFields(&want)
+			for field, address := range tt.want {
+				*fields[field] = address
+			}
+			assert.Equal(t, want, addrs)
+
+			var wantMissing []string
+			for _, field := range sortedFoundryFields() {
+				if _, ok := tt.want[field]; !ok {
+					wantMissing = append(wantMissing, field)
+				}
+			}
+			assert.Equal(t, wantMissing, missing)
+		})
+	}
+}
+
+// TestFoundryContractFields checks every contract name maps to a field of the config
+func TestFoundryContractFields(t *testing.T) {
+	fields := contractAddressFields(&config.ContractAddress{})
+	for name, field := range foundryContractFields {
+		assert.Contains(t, fields, field, "contract %s", name)
+	}
+}
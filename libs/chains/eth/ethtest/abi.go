@@ -0,0 +1,200 @@
+package ethtest
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// Values returns non-zero values of the types of args, as abi decodes them: tuples are the
+// structs of their abi type and slices hold two elements. The values only depend on seed, so a
+// test can raise an event of a binding without naming its tuple types.
+func Values(args abi.Arguments, seed uint64) ([]any, error) {
+	n := seed * 1000
+	values := make([]any, len(args))
+	for i, arg := range args {
+		v, err := valueOf(arg.Type, &n)
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %s", arg.Name)
+		}
+		values[i] = v.Interface()
+	}
+	return values, nil
+}
+
+func valueOf(t abi.Type, n *uint64) (reflect.Value, error) {
+	typ := t.GetType()
+	switch t.T {
+	case abi.TupleTy:
+		v := reflect.New(typ).Elem()
+		for i, elem := range t.TupleElems {
+			field, err := valueOf(*elem, n)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.FieldByName(abi.ToCamelCase(t.TupleRawNames[i])).Set(field)
+		}
+		return v, nil
+	case abi.SliceTy, abi.ArrayTy:
+		size := t.Size
+		v := reflect.New(typ).Elem()
+		if t.T == abi.SliceTy {
+			size = 2
+			v = reflect.MakeSlice(typ, size, size)
+		}
+		for i := 0; i < size; i++ {
+			elem, err := valueOf(*t.Elem, n)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.Index(i).Set(elem)
+		}
+		return v, nil
+	}
+
+	*n++
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		if t.Size > 64 {
+			return reflect.ValueOf(new(big.Int).SetUint64(*n)), nil
+		}
+		// in range of the smallest signed type
+		x := 1 + *n%(1<<(t.Size-1)-1)
+		v := reflect.New(typ).Elem()
+		if t.T == abi.IntTy {
+			v.SetInt(int64(x))
+		} else {
+			v.SetUint(x)
+		}
+		return v, nil
+	case abi.BoolTy:
+		return reflect.ValueOf(true), nil
+	case abi.StringTy:
+		return reflect.ValueOf(fmt.Sprintf("value-%d", *n)), nil
+	case abi.AddressTy:
+		return reflect.ValueOf(common.BigToAddress(new(big.Int).SetUint64(*n))), nil
+	case abi.BytesTy:
+		return reflect.ValueOf([]byte(fmt.Sprintf("bytes-%d", *n))), nil
+	case abi.FixedBytesTy:
+		v := reflect.New(typ).Elem()
+		reflect.Copy(v, reflect.ValueOf(common.BigToHash(new(big.Int).SetUint64(*n)).Bytes()[common.HashLength-t.Size:]))
+		return v, nil
+	default:
+		return reflect.Value{}, errors.Errorf("unsupported type %s", t)
+	}
+}
+
+// Named returns values by the go names of args, as the fields of the event of a binding
+func Named(args abi.Arguments, values []any) map[string]any {
+	named := make(map[string]any, len(args))
+	for i, arg := range args[:min(len(args), len(values))] {
+		named[abi.ToCamelCase(arg.Name)] = values[i]
+	}
+	return named
+}
+
+// Field returns the field name of the struct v, it panics when v has no such field
+func Field(v any, name string) any {
+	field := reflect.Indirect(reflect.ValueOf(v)).FieldByName(name)
+	if !field.IsValid() {
+		panic(fmt.Sprintf("%T has no field %s", v, name))
+	}
+	return field.Interface()
+}
+
+// Convert returns v as the go type of t, the way a route copies the fields of an event into the
+// params of a target. Tuples are filled by field name from a struct or a map[string]any, the
+// fields v does not have are left zero; integers are converted to the size of t.
+func Convert(t abi.Type, v any) (any, error) {
+	converted, err := convert(t, reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return converted.Interface(), nil
+}
+
+func convert(t abi.Type, v reflect.Value) (reflect.Value, error) {
+	typ := t.GetType()
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return reflect.Zero(typ), nil
+	}
+	if v.Type() == typ {
+		return v, nil
+	}
+
+	switch t.T {
+	case abi.TupleTy:
+		v = reflect.Indirect(v)
+		if v.Kind() != reflect.Struct && v.Kind() != reflect.Map {
+			break
+		}
+		out := reflect.New(typ).Elem()
+		for i, elem := range t.TupleElems {
+			name := abi.ToCamelCase(t.TupleRawNames[i])
+			var field reflect.Value
+			if v.Kind() == reflect.Map {
+				field = v.MapIndex(reflect.ValueOf(name))
+			} else {
+				field = v.FieldByName(name)
+			}
+			if !field.IsValid() {
+				continue
+			}
+			converted, err := convert(*elem, field)
+			if err != nil {
+				return reflect.Value{}, errors.Wrapf(err, "field %s", name)
+			}
+			out.FieldByName(name).Set(converted)
+		}
+		return out, nil
+	case abi.SliceTy, abi.ArrayTy:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			break
+		}
+		out := reflect.New(typ).Elem()
+		if t.T == abi.SliceTy {
+			out = reflect.MakeSlice(typ, v.Len(), v.Len())
+		} else if v.Len() != t.Size {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			converted, err := convert(*t.Elem, v.Index(i))
+			if err != nil {
+				return reflect.Value{}, errors.Wrapf(err, "element %d", i)
+			}
+			out.Index(i).Set(converted)
+		}
+		return out, nil
+	case abi.IntTy, abi.UintTy:
+		if x, ok := v.Interface().(*big.Int); ok && t.Size <= 64 {
+			v = reflect.ValueOf(x.Int64())
+			if t.T == abi.UintTy {
+				v = reflect.ValueOf(x.Uint64())
+			}
+		}
+		if t.Size > 64 {
+			switch {
+			case v.CanInt():
+				return reflect.ValueOf(big.NewInt(v.Int())), nil
+			case v.CanUint():
+				return reflect.ValueOf(new(big.Int).SetUint64(v.Uint())), nil
+			}
+			break
+		}
+		if v.CanInt() || v.CanUint() {
+			return v.Convert(typ), nil
+		}
+	default:
+		if v.Type().ConvertibleTo(typ) {
+			return v.Convert(typ), nil
+		}
+	}
+	return reflect.Value{}, errors.Errorf("cannot convert %s to %s", v.Type(), t)
+}
@@ -0,0 +1,173 @@
+package ethtest
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tupleABI = `[
+	{"type":"event","name":"Queued","anonymous":false,"inputs":[
+		{"name":"root","type":"bytes32","indexed":false},
+		{"name":"groupNumber","type":"uint8","indexed":true},
+		{"name":"withdrawal","type":"tuple","indexed":false,"components":[
+			{"name":"staker","type":"address"},
+			{"name":"nonce","type":"uint256"},
+			{"name":"strategies","type":"address[]"},
+			{"name":"shares","type":"uint256[]"}]},
+		{"name":"socket","type":"string","indexed":false},
+		{"name":"pubkey","type":"uint256[2]","indexed":false},
+		{"name":"signature","type":"bytes","indexed":false},
+		{"name":"enabled","type":"bool","indexed":false}]},
+	{"type":"function","name":"sync","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"chainId","type":"uint256"},
+		{"name":"groupNumber","type":"uint8"},
+		{"name":"params","type":"tuple","components":[
+			{"name":"strategies","type":"address[]"},
+			{"name":"shares","type":"uint256[]"},
+			{"name":"approver","type":"address"}]},
+		{"name":"pubkey","type":"uint256[2]"}]}
+]`
+
+func parseTupleABI(t *testing.T) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(tupleABI))
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestValues(t *testing.T) {
+	ev := parseTupleABI(t).Events["Queued"]
+
+	values, err := Values(ev.Inputs, 1)
+	require.NoError(t, err)
+	for i, v := range values {
+		assert.NotZero(t, v, "argument %s", ev.Inputs[i].Name)
+	}
+
+	// the values round trip through the log of the event
+	lg, err := EventLog(ev, testContract, values...)
+	require.NoError(t, err)
+	unpacked, err := ev.Inputs.Unpack(lg.Data)
+	require.NoError(t, err)
+	var nonIndexed []any
+	for i, input := range ev.Inputs {
+		if !input.Indexed {
+			nonIndexed = append(nonIndexed, values[i])
+		}
+	}
+	assert.Equal(t, nonIndexed, unpacked)
+
+	again, err := Values(ev.Inputs, 1)
+	require.NoError(t, err)
+	assert.Equal(t, values, again)
+	other, err := Values(ev.Inputs, 2)
+	require.NoError(t, err)
+	assert.NotEqual(t, values, other)
+
+	named := Named(ev.Inputs, values)
+	assert.Equal(t, values[1], named["GroupNumber"])
+	assert.Equal(t, values[2], named["Withdrawal"])
+	assert.Len(t, Field(named["Withdrawal"], "Strategies"), 2)
+	assert.Panics(t, func() { Field(named["Withdrawal"], "Withdrawer") })
+}
+
+func TestConvert(t *testing.T) {
+	sync := parseTupleABI(t).Methods["sync"]
+	strategies := []common.Address{testFrom, testTo}
+	shares := []*big.Int{big.NewInt(1), big.NewInt(2)}
+
+	var tests = map[string]struct {
+		input   int
+		value   any
+		want    any
+		wantErr string
+	}{
+		"int to uint256": {
+			input: 0,
+			value: 7,
+			want:  big.NewInt(7),
+		},
+		"big int to uint8": {
+			input: 1,
+			value: big.NewInt(3),
+			want:  uint8(3),
+		},
+		"uint64 to uint8": {
+			input: 1,
+			value: uint64(3),
+			want:  uint8(3),
+		},
+		"struct by field name": {
+			input: 2,
+			value: struct {
+				Staker     common.Address
+				Strategies []common.Address
+				Shares     []*big.Int
+			}{testFrom, strategies, shares},
+			want: []any{strategies, shares, common.Address{}},
+		},
+		"map by field name": {
+			input: 2,
+			value: map[string]any{"Approver": testTo, "Shares": []int{1, 2}},
+			want:  []any{[]common.Address(nil), shares, testTo},
+		},
+		"nil": {
+			input: 2,
+			value: nil,
+			want:  []any{[]common.Address(nil), []*big.Int(nil), common.Address{}},
+		},
+		"slice to array": {
+			input: 3,
+			value: []*big.Int{big.NewInt(1), big.NewInt(2)},
+			want:  [2]*big.Int{big.NewInt(1), big.NewInt(2)},
+		},
+		"slice of another length": {
+			input:   3,
+			value:   []*big.Int{big.NewInt(1)},
+			wantErr: "cannot convert []*big.Int to uint256[2]",
+		},
+		"address to uint8": {
+			input:   1,
+			value:   testFrom,
+			wantErr: "cannot convert common.Address to uint8",
+		},
+		"field of another type": {
+			input:   2,
+			value:   map[string]any{"Approver": "0x01"},
+			wantErr: "field Approver: cannot convert string to address",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			typ := sync.Inputs[tt.input].Type
+			got, err := Convert(typ, tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, typ.GetType(), reflect.TypeOf(got))
+
+			if fields, ok := tt.want.([]any); ok {
+				for i, field := range fields {
+					assert.Equal(t, field, Field(got, abi.ToCamelCase(typ.TupleRawNames[i])))
+				}
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// the converted args pack as the params of the method
+	params, err := Convert(sync.Inputs[2].Type, map[string]any{"Strategies": strategies, "Shares": shares})
+	require.NoError(t, err)
+	_, err = sync.Inputs.Pack(big.NewInt(1), uint8(1), params, [2]*big.Int{big.NewInt(1), big.NewInt(2)})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,666 @@
+// Package ethtest provides in-memory test doubles of eth.Client and txmgr.TxManager, so the
+// routes can be unit-tested without a node: logs are injected into subscriptions and filters,
+// calls, receipts and errors are scripted, and every transaction is recorded.
+package ethtest
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+)
+
+var (
+	// DefaultGas is returned by EstimateGas when the call does not fail
+	DefaultGas uint64 = 100_000
+
+	baseFee   = big.NewInt(1_000_000_000)
+	gasTipCap = big.NewInt(1_000_000_000)
+
+	// subscriptionBuffer is the number of logs a subscription holds before AddLogs blocks
+	subscriptionBuffer = 1024
+)
+
+// CallFunc answers the eth_call and eth_estimateGas of a contract, an error reverts the call
+type CallFunc func(msg ethereum.CallMsg) ([]byte, error)
+
+// SendFunc returns the receipt of a sent transaction, or the error of its send
+type SendFunc func(tx *types.Transaction) (*types.Receipt, error)
+
+// Client is an eth.Client backed by memory. Every address has code unless set otherwise by
+// SetCode, calls return no data and sent transactions are mined with a successful receipt.
+// Errors are scripted per method with Fail and FailNext, by the name of the eth.Client method.
+type Client struct {
+	mu       sync.Mutex
+	chainID  *big.Int
+	head     uint64
+	logs     []types.Log
+	subs     map[*logSubscription]struct{}
+	code     map[common.Address][]byte
+	balances map[common.Address]*big.Int
+	onCall   map[common.Address]CallFunc
+	onSend   SendFunc
+	calls    []ethereum.CallMsg
+	sent     []*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	failures map[string]error
+	queued   map[string][]error
+}
+
+var _ eth.Client = (*Client)(nil)
+
+// NewClient returns an empty chain with chainID at block 0
+func NewClient(chainID *big.Int) *Client {
+	return &Client{
+		chainID:  chainID,
+		subs:     make(map[*logSubscription]struct{}),
+		code:     make(map[common.Address][]byte),
+		balances: make(map[common.Address]*big.Int),
+		onCall:   make(map[common.Address]CallFunc),
+		receipts: make(map[common.Hash]*types.Receipt),
+		failures: make(map[string]error),
+		queued:   make(map[string][]error),
+	}
+}
+
+// AddLogs appends logs to the chain and delivers them to the matching subscriptions.
+// The head moves to the highest block of the logs.
+func (c *Client) AddLogs(logs ...types.Log) {
+	c.mu.Lock()
+	subs := make([]*logSubscription, 0, len(c.subs))
+	for sub := range c.subs {
+		subs = append(subs, sub)
+	}
+	for _, lg := range logs {
+		c.logs = append(c.logs, lg)
+		c.head = max(c.head, lg.BlockNumber)
+	}
+	c.mu.Unlock()
+
+	for _, lg := range logs {
+		for _, sub := range subs {
+			if matchLog(sub.query, lg) {
+				sub.queue <- lg
+			}
+		}
+	}
+}
+
+// DropSubscriptions fails every open subscription with err, as a lost websocket would
+func (c *Client) DropSubscriptions(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for sub := range c.subs {
+		sub.fail <- err
+		delete(c.subs, sub)
+	}
+}
+
+// Subscriptions returns the number of open log subscriptions
+func (c *Client) Subscriptions() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.subs)
+}
+
+// SetBlockNumber moves the head to number
+func (c *Client) SetBlockNumber(number uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head = number
+}
+
+// SetCode sets the code of address, nil leaves it without code
+func (c *Client) SetCode(address common.Address, code []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.code[address] = code
+}
+
+// SetBalance sets the balance of account
+func (c *Client) SetBalance(account common.Address, balance *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.balances[account] = balance
+}
+
+// OnCall answers the calls to address with fn
+func (c *Client) OnCall(address common.Address, fn CallFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onCall[address] = fn
+}
+
+// OnSend returns the receipts of the sent transactions with fn
+func (c *Client) OnSend(fn SendFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onSend = fn
+}
+
+// SetReceipt sets the receipt returned for hash
+func (c *Client) SetReceipt(hash common.Hash, receipt *types.Receipt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.receipts[hash] = receipt
+}
+
+// Fail makes every call of method return err, nil clears it
+func (c *Client) Fail(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.failures, method)
+		return
+	}
+	c.failures[method] = err
+}
+
+// FailNext makes the next calls of method return errs, one error per call
+func (c *Client) FailNext(method string, errs ...error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queued[method] = append(c.queued[method], errs...)
+}
+
+// Calls returns the eth_call messages received, in order
+func (c *Client) Calls() []ethereum.CallMsg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ethereum.CallMsg(nil), c.calls...)
+}
+
+// Sent returns the transactions sent, in order
+func (c *Client) Sent() []*types.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*types.Transaction(nil), c.sent...)
+}
+
+func (c *Client) fail(method string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if errs := c.queued[method]; len(errs) > 0 {
+		c.queued[method] = errs[1:]
+		return errs[0]
+	}
+	return c.failures[method]
+}
+
+func (c *Client) call(msg ethereum.CallMsg, record bool) ([]byte, error) {
+	c.mu.Lock()
+	if record {
+		c.calls = append(c.calls, msg)
+	}
+	var fn CallFunc
+	if msg.To != nil {
+		fn = c.onCall[*msg.To]
+	}
+	c.mu.Unlock()
+
+	if fn == nil {
+		return nil, nil
+	}
+	return fn(msg)
+}
+
+func (c *Client) header(number *big.Int) *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number == nil || number.Sign() < 0 {
+		number = new(big.Int).SetUint64(c.head)
+	}
+	return &types.Header{
+		Number:     new(big.Int).Set(number),
+		Difficulty: new(big.Int),
+		GasLimit:   30_000_000,
+		BaseFee:    new(big.Int).Set(baseFee),
+		Time:       uint64(time.Now().Unix()),
+	}
+}
+
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	if err := c.fail("ChainID"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(c.chainID), nil
+}
+
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	if err := c.fail("BalanceAt"); err != nil {
+		return nil, err
+	}
+	return c.balance(account), nil
+}
+
+func (c *Client) balance(account common.Address) *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if balance, ok := c.balances[account]; ok {
+		return new(big.Int).Set(balance)
+	}
+	return new(big.Int)
+}
+
+func (c *Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	header, err := c.HeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewBlockWithHeader(header), nil
+}
+
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	if err := c.fail("BlockByNumber"); err != nil {
+		return nil, err
+	}
+	return types.NewBlockWithHeader(c.header(number)), nil
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	if err := c.fail("BlockNumber"); err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head, nil
+}
+
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if err := c.fail("CallContract"); err != nil {
+		return nil, err
+	}
+	return c.call(msg, true)
+}
+
+func (c *Client) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	if err := c.fail("CallContractAtHash"); err != nil {
+		return nil, err
+	}
+	return c.call(msg, true)
+}
+
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	if err := c.fail("CodeAt"); err != nil {
+		return nil, err
+	}
+	return c.codeAt(account), nil
+}
+
+func (c *Client) codeAt(account common.Address) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if code, ok := c.code[account]; ok {
+		return code
+	}
+	return []byte{0x00}
+}
+
+// EstimateGas runs the call of msg and returns DefaultGas if it does not fail
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	if err := c.fail("EstimateGas"); err != nil {
+		return 0, err
+	}
+	if _, err := c.call(msg, false); err != nil {
+		return 0, err
+	}
+	return DefaultGas, nil
+}
+
+func (c *Client) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	lastBlock *big.Int,
+	rewardPercentiles []float64,
+) (*ethereum.FeeHistory, error) {
+	if err := c.fail("FeeHistory"); err != nil {
+		return nil, err
+	}
+	return &ethereum.FeeHistory{OldestBlock: c.header(lastBlock).Number}, nil
+}
+
+// FilterLogs returns the logs added with AddLogs that match q
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if err := c.fail("FilterLogs"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var logs []types.Log
+	for _, lg := range c.logs {
+		if q.BlockHash != nil && lg.BlockHash != *q.BlockHash {
+			continue
+		}
+		if q.BlockHash == nil && !inRange(lg.BlockNumber, q.FromBlock, q.ToBlock, c.head) {
+			continue
+		}
+		if matchLog(q, lg) {
+			logs = append(logs, lg)
+		}
+	}
+	return logs, nil
+}
+
+// HeaderByHash returns the header of the block of a log added with AddLogs
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	if err := c.fail("HeaderByHash"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	var number *big.Int
+	for _, lg := range c.logs {
+		if lg.BlockHash == hash {
+			number = new(big.Int).SetUint64(lg.BlockNumber)
+			break
+		}
+	}
+	c.mu.Unlock()
+	if number == nil {
+		return nil, ethereum.NotFound
+	}
+	return c.header(number), nil
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := c.fail("HeaderByNumber"); err != nil {
+		return nil, err
+	}
+	return c.header(number), nil
+}
+
+func (c *Client) NetworkID(ctx context.Context) (*big.Int, error) {
+	if err := c.fail("NetworkID"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(c.chainID), nil
+}
+
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	if err := c.fail("NonceAt"); err != nil {
+		return 0, err
+	}
+	return c.nonce(), nil
+}
+
+func (c *Client) nonce() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.sent))
+}
+
+func (c *Client) PeerCount(ctx context.Context) (uint64, error) {
+	if err := c.fail("PeerCount"); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (c *Client) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	if err := c.fail("PendingBalanceAt"); err != nil {
+		return nil, err
+	}
+	return c.balance(account), nil
+}
+
+func (c *Client) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	if err := c.fail("PendingCallContract"); err != nil {
+		return nil, err
+	}
+	return c.call(msg, true)
+}
+
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	if err := c.fail("PendingCodeAt"); err != nil {
+		return nil, err
+	}
+	return c.codeAt(account), nil
+}
+
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if err := c.fail("PendingNonceAt"); err != nil {
+		return 0, err
+	}
+	return c.nonce(), nil
+}
+
+func (c *Client) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	if err := c.fail("PendingStorageAt"); err != nil {
+		return nil, err
+	}
+	return make([]byte, common.HashLength), nil
+}
+
+func (c *Client) PendingTransactionCount(ctx context.Context) (uint, error) {
+	if err := c.fail("PendingTransactionCount"); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// SendTransaction records tx and mines it in a new block, with the receipt of OnSend if set
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := c.fail("SendTransaction"); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	onSend := c.onSend
+	c.mu.Unlock()
+
+	receipt := successReceipt(tx)
+	if onSend != nil {
+		var err error
+		if receipt, err = onSend(tx); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head++
+	c.sent = append(c.sent, tx)
+	if receipt != nil {
+		if receipt.BlockNumber == nil {
+			receipt.BlockNumber = new(big.Int).SetUint64(c.head)
+		}
+		c.receipts[tx.Hash()] = receipt
+	}
+	return nil
+}
+
+func (c *Client) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	if err := c.fail("StorageAt"); err != nil {
+		return nil, err
+	}
+	return make([]byte, common.HashLength), nil
+}
+
+// SubscribeFilterLogs delivers the logs added with AddLogs that match q to ch
+func (c *Client) SubscribeFilterLogs(
+	ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	if err := c.fail("SubscribeFilterLogs"); err != nil {
+		return nil, err
+	}
+	sub := &logSubscription{
+		query: q,
+		queue: make(chan types.Log, subscriptionBuffer),
+		fail:  make(chan error, 1),
+	}
+	c.mu.Lock()
+	c.subs[sub] = struct{}{}
+	c.mu.Unlock()
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer c.unsubscribe(sub)
+		for {
+			select {
+			case lg := <-sub.queue:
+				select {
+				case ch <- lg:
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.fail:
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+func (c *Client) unsubscribe(sub *logSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.subs, sub)
+}
+
+// SubscribeNewHead returns a subscription that delivers no header
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	if err := c.fail("SubscribeNewHead"); err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if err := c.fail("SuggestGasPrice"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Add(baseFee, gasTipCap), nil
+}
+
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	if err := c.fail("SuggestGasTipCap"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(gasTipCap), nil
+}
+
+func (c *Client) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	if err := c.fail("SyncProgress"); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if err := c.fail("TransactionByHash"); err != nil {
+		return nil, false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tx := range c.sent {
+		if tx.Hash() == hash {
+			return tx, false, nil
+		}
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (c *Client) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	if err := c.fail("TransactionCount"); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+func (c *Client) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	if err := c.fail("TransactionInBlock"); err != nil {
+		return nil, err
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if err := c.fail("TransactionReceipt"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if receipt, ok := c.receipts[txHash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *Client) TransactionSender(
+	ctx context.Context, tx *types.Transaction, block common.Hash, index uint,
+) (common.Address, error) {
+	if err := c.fail("TransactionSender"); err != nil {
+		return common.Address{}, err
+	}
+	return common.Address{}, ethereum.NotFound
+}
+
+type logSubscription struct {
+	query ethereum.FilterQuery
+	queue chan types.Log
+	fail  chan error
+}
+
+// matchLog tells if lg matches the addresses and topics of q, like the filters of a node
+func matchLog(q ethereum.FilterQuery, lg types.Log) bool {
+	if len(q.Addresses) > 0 {
+		found := false
+		for _, address := range q.Addresses {
+			if address == lg.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(q.Topics) > len(lg.Topics) {
+		return false
+	}
+	for i, alternatives := range q.Topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range alternatives {
+			if topic == lg.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func inRange(number uint64, from, to *big.Int, head uint64) bool {
+	if from != nil && from.Sign() >= 0 && number < from.Uint64() {
+		return false
+	}
+	if to != nil && to.Sign() >= 0 {
+		return number <= to.Uint64()
+	}
+	return number <= head
+}
+
+func successReceipt(tx *types.Transaction) *types.Receipt {
+	return &types.Receipt{
+		Type:              tx.Type(),
+		Status:            types.ReceiptStatusSuccessful,
+		TxHash:            tx.Hash(),
+		GasUsed:           tx.Gas(),
+		CumulativeGasUsed: tx.Gas(),
+		Logs:              []*types.Log{},
+	}
+}
@@ -0,0 +1,185 @@
+package ethtest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+)
+
+var _ txmgr.TxManager = (*TxManager)(nil)
+
+const testABI = `[
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[
+		{"name":"from","type":"address","indexed":true},
+		{"name":"to","type":"address","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"function","name":"sync","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"chainId","type":"uint256"},
+		{"name":"staker","type":"address"}]}
+]`
+
+var (
+	testContract = common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	testFrom     = common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	testTo       = common.HexToAddress("0x00000000000000000000000000000000000000f2")
+)
+
+func parseTestABI(t *testing.T) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestClientSubscribeFilterLogs(t *testing.T) {
+	parsed := parseTestABI(t)
+	client := NewClient(big.NewInt(1337))
+
+	transfer, err := EventLog(parsed.Events["Transfer"], testContract, testFrom, testTo, big.NewInt(5))
+	require.NoError(t, err)
+	transfer.BlockNumber = 3
+	other, err := EventLog(parsed.Events["Transfer"], testContract, testTo, testFrom, big.NewInt(6))
+	require.NoError(t, err)
+	other.BlockNumber = 4
+
+	// only the transfers from testFrom
+	logs := make(chan types.Log, 2)
+	sub, err := client.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{
+		Addresses: []common.Address{testContract},
+		Topics:    [][]common.Hash{{parsed.Events["Transfer"].ID}, {common.BytesToHash(testFrom.Bytes())}},
+	}, logs)
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.Subscriptions())
+
+	client.AddLogs(other, transfer)
+	select {
+	case lg := <-logs:
+		assert.Equal(t, transfer, lg)
+	case <-time.After(time.Second):
+		t.Fatal("log not delivered")
+	}
+	select {
+	case lg := <-logs:
+		t.Fatalf("unexpected log %v", lg)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	head, err := client.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), head)
+
+	dropped := errors.New("connection lost")
+	client.DropSubscriptions(dropped)
+	select {
+	case err := <-sub.Err():
+		assert.Equal(t, dropped, err)
+	case <-time.After(time.Second):
+		t.Fatal("subscription not dropped")
+	}
+	assert.Equal(t, 0, client.Subscriptions())
+}
+
+func TestClientFilterLogs(t *testing.T) {
+	parsed := parseTestABI(t)
+	client := NewClient(big.NewInt(1337))
+
+	var added []types.Log
+	for block := uint64(1); block <= 3; block++ {
+		lg, err := EventLog(parsed.Events["Transfer"], testContract, testFrom, testTo, big.NewInt(int64(block)))
+		require.NoError(t, err)
+		lg.BlockNumber = block
+		added = append(added, lg)
+	}
+	client.AddLogs(added...)
+
+	logs, err := client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(2),
+		Addresses: []common.Address{testContract},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, added[1:], logs)
+
+	logs, err = client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		Addresses: []common.Address{testFrom},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, logs)
+}
+
+func TestClientFail(t *testing.T) {
+	client := NewClient(big.NewInt(1337))
+	ctx := context.Background()
+
+	transient := errors.New("i/o timeout")
+	client.FailNext("BlockNumber", transient)
+	_, err := client.BlockNumber(ctx)
+	assert.Equal(t, transient, err)
+	_, err = client.BlockNumber(ctx)
+	assert.NoError(t, err)
+
+	client.Fail("ChainID", transient)
+	for i := 0; i < 2; i++ {
+		_, err = client.ChainID(ctx)
+		assert.Equal(t, transient, err)
+	}
+	client.Fail("ChainID", nil)
+	chainID, err := client.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1337), chainID)
+}
+
+func TestTxManagerRecordsBoundCalls(t *testing.T) {
+	parsed := parseTestABI(t)
+	client := NewClient(big.NewInt(1337))
+	txMgr := NewTxManager(testFrom)
+	contract := bind.NewBoundContract(testContract, parsed, client, client, client)
+
+	opts, err := txMgr.GetNoSendTxOpts()
+	require.NoError(t, err)
+	tx, err := contract.Transact(opts, "sync", big.NewInt(1337), testTo)
+	require.NoError(t, err)
+	assert.Empty(t, client.Sent())
+
+	// the calldata is checked against the abi, not replayed
+	result := txmgr.Simulate(context.Background(), client, opts.From, tx)
+	require.True(t, result.OK())
+	require.Len(t, client.Calls(), 1)
+	assert.Equal(t, testFrom, client.Calls()[0].From)
+
+	receipt, err := txMgr.Send(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	assert.Equal(t, tx.Hash(), receipt.TxHash)
+
+	expected, err := parsed.Pack("sync", big.NewInt(1337), testTo)
+	require.NoError(t, err)
+	require.Len(t, txMgr.Sent(), 1)
+	assert.Equal(t, expected, txMgr.Sent()[0].Data())
+	assert.Equal(t, testContract, *txMgr.Sent()[0].To())
+
+	// scripted reverts surface from the gas estimation of the bindings
+	client.OnCall(testContract, func(ethereum.CallMsg) ([]byte, error) {
+		return nil, errors.New("execution reverted: not connector")
+	})
+	_, err = contract.Transact(opts, "sync", big.NewInt(1337), testTo)
+	require.Error(t, err)
+	assert.Equal(t, txmgr.SimulationReverted, txmgr.ClassifyCallError(err).Status)
+
+	sendErr := errors.New("nonce too low")
+	txMgr.FailNext(sendErr)
+	_, err = txMgr.Send(context.Background(), tx)
+	assert.Equal(t, sendErr, err)
+	assert.Len(t, txMgr.Sent(), 1)
+}
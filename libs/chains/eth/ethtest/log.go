@@ -0,0 +1,35 @@
+package ethtest
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// EventLog returns the log of ev raised by address with args, in the order of its inputs.
+// The block and tx fields are left for the caller to set.
+func EventLog(ev abi.Event, address common.Address, args ...any) (types.Log, error) {
+	if len(args) != len(ev.Inputs) {
+		return types.Log{}, errors.Errorf("%s takes %d arguments, got %d", ev.Sig, len(ev.Inputs), len(args))
+	}
+
+	topics := []common.Hash{ev.ID}
+	var data []any
+	for i, input := range ev.Inputs {
+		if !input.Indexed {
+			data = append(data, args[i])
+			continue
+		}
+		topic, err := abi.MakeTopics([]any{args[i]})
+		if err != nil {
+			return types.Log{}, errors.Wrapf(err, "failed to encode topic %s", input.Name)
+		}
+		topics = append(topics, topic[0][0])
+	}
+	packed, err := ev.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		return types.Log{}, errors.Wrapf(err, "failed to encode %s", ev.Sig)
+	}
+	return types.Log{Address: address, Topics: topics, Data: packed}, nil
+}
@@ -0,0 +1,67 @@
+package ethtest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ethService is the part of the eth rpc a client probes when it connects
+type ethService struct {
+	client *Client
+}
+
+func (s *ethService) ChainId(ctx context.Context) (*hexutil.Big, error) { //nolint:revive,stylecheck // eth_chainId
+	chainID, err := s.client.ChainID(ctx)
+	return (*hexutil.Big)(chainID), err
+}
+
+func (s *ethService) BlockNumber(ctx context.Context) (hexutil.Uint64, error) {
+	number, err := s.client.BlockNumber(ctx)
+	return hexutil.Uint64(number), err
+}
+
+// setBalanceService is the setBalance rpc of anvil and hardhat dev nodes
+type setBalanceService struct {
+	client *Client
+}
+
+func (s *setBalanceService) SetBalance(account common.Address, balance *hexutil.Big) {
+	s.client.SetBalance(account, balance.ToInt())
+}
+
+// NewDevNodeServer returns an http and websocket rpc server backed by client. It answers
+// eth_chainId and eth_blockNumber, so an eth.Client can dial it, and anvil_setBalance and
+// hardhat_setBalance by setting the balance on client. Close it when done, see WSURL.
+func NewDevNodeServer(client *Client) *httptest.Server {
+	server := rpc.NewServer()
+	services := map[string]any{
+		"eth":     &ethService{client: client},
+		"anvil":   &setBalanceService{client: client},
+		"hardhat": &setBalanceService{client: client},
+	}
+	for namespace, service := range services {
+		if err := server.RegisterName(namespace, service); err != nil {
+			panic(err)
+		}
+	}
+
+	ws := server.WebsocketHandler([]string{"*"})
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+}
+
+// WSURL returns the websocket url of a server returned by NewDevNodeServer
+func WSURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
@@ -0,0 +1,46 @@
+package ethtest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevNodeServer(t *testing.T) {
+	client := NewClient(big.NewInt(1337))
+	client.SetBlockNumber(7)
+	server := NewDevNodeServer(client)
+	defer server.Close()
+
+	for _, url := range []string{server.URL, WSURL(server)} {
+		ethClient, err := ethclient.Dial(url)
+		require.NoError(t, err)
+
+		chainID, err := ethClient.ChainID(context.Background())
+		require.NoError(t, err, url)
+		assert.Equal(t, big.NewInt(1337), chainID)
+		number, err := ethClient.BlockNumber(context.Background())
+		require.NoError(t, err, url)
+		assert.Equal(t, uint64(7), number)
+		ethClient.Close()
+	}
+
+	rpcClient, err := rpc.DialContext(context.Background(), server.URL)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+
+	for i, method := range []string{"anvil_setBalance", "hardhat_setBalance"} {
+		balance := big.NewInt(int64(1000 + i))
+		require.NoError(t, rpcClient.CallContext(context.Background(), nil, method, testTo, hexutil.EncodeBig(balance)))
+
+		got, err := client.BalanceAt(context.Background(), testTo, nil)
+		require.NoError(t, err)
+		assert.Equal(t, balance, got, method)
+	}
+}
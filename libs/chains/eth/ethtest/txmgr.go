@@ -0,0 +1,88 @@
+package ethtest
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxManager is a txmgr.TxManager that records the transactions it is given instead of signing
+// and broadcasting them. They are mined with a successful receipt unless scripted otherwise.
+type TxManager struct {
+	mu     sync.Mutex
+	from   common.Address
+	sent   []*types.Transaction
+	onSend SendFunc
+	queued []error
+	block  uint64
+}
+
+// NewTxManager returns a tx manager sending from from
+func NewTxManager(from common.Address) *TxManager {
+	return &TxManager{from: from}
+}
+
+// OnSend returns the receipts of the sent transactions with fn
+func (m *TxManager) OnSend(fn SendFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onSend = fn
+}
+
+// FailNext makes the next sends return errs, one error per send
+func (m *TxManager) FailNext(errs ...error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queued = append(m.queued, errs...)
+}
+
+// Sent returns the transactions sent without error, in order
+func (m *TxManager) Sent() []*types.Transaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*types.Transaction(nil), m.sent...)
+}
+
+func (m *TxManager) Send(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	m.mu.Lock()
+	if len(m.queued) > 0 {
+		err := m.queued[0]
+		m.queued = m.queued[1:]
+		m.mu.Unlock()
+		return nil, err
+	}
+	onSend := m.onSend
+	m.mu.Unlock()
+
+	receipt := successReceipt(tx)
+	if onSend != nil {
+		var err error
+		if receipt, err = onSend(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.block++
+	m.sent = append(m.sent, tx)
+	if receipt != nil && receipt.BlockNumber == nil {
+		receipt.BlockNumber = new(big.Int).SetUint64(m.block)
+	}
+	return receipt, nil
+}
+
+// GetNoSendTxOpts returns opts that build the transaction unsigned, from the sender of m
+func (m *TxManager) GetNoSendTxOpts() (*bind.TransactOpts, error) {
+	return &bind.TransactOpts{
+		From:   m.from,
+		NoSend: true,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}, nil
+}
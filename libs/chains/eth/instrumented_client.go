@@ -51,6 +51,11 @@ func NewInstrumentedClientFromClient(
 	}
 }
 
+// Close closes the underlying rpc connection
+func (iec *InstrumentedClient) Close() {
+	iec.client.Close()
+}
+
 // gethClient interface methods
 
 func (iec *InstrumentedClient) ChainID(ctx context.Context) (*big.Int, error) {
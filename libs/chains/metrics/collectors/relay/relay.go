@@ -0,0 +1,83 @@
+package relay
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "pell_emulator"
+
+// Collector contains the metrics of the routes that relay source events to target contracts
+type Collector struct {
+	eventsTotal                 *prometheus.CounterVec
+	forwardLatencySeconds       *prometheus.HistogramVec
+	gasUsedTotal                *prometheus.CounterVec
+	subscriptionReconnectsTotal *prometheus.CounterVec
+}
+
+// Status of an event handled by a route
+const (
+	StatusReceived  = "received"
+	StatusForwarded = "forwarded"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+)
+
+// NewCollector returns a relay Collector registered on reg
+func NewCollector(reg prometheus.Registerer) *Collector {
+	return &Collector{
+		eventsTotal: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "route_events_total",
+				Help:      "Total number of source events per route and <status>: received, forwarded, failed or skipped",
+			},
+			[]string{"route", "status"},
+		),
+		forwardLatencySeconds: promauto.With(reg).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "route_forward_latency_seconds",
+				Help:      "Time between the source block and the receipt of the target tx",
+				Buckets:   []float64{1, 2, 5, 10, 20, 30, 60, 120, 300},
+			},
+			[]string{"route"},
+		),
+		gasUsedTotal: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "target_gas_used_total",
+				Help:      "Total gas used by target txs per <contract> and <method>",
+			},
+			[]string{"contract", "method"},
+		),
+		subscriptionReconnectsTotal: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "route_subscription_reconnects_total",
+				Help:      "Total number of times the source event subscription of a route was re-established",
+			},
+			[]string{"route"},
+		),
+	}
+}
+
+// AddEvent counts an event of route with status
+func (c *Collector) AddEvent(route, status string) {
+	c.eventsTotal.WithLabelValues(route, status).Inc()
+}
+
+// ObserveForwardLatencySeconds observes the end-to-end latency of a forward
+func (c *Collector) ObserveForwardLatencySeconds(route string, seconds float64) {
+	c.forwardLatencySeconds.WithLabelValues(route).Observe(seconds)
+}
+
+// AddGasUsed adds the gas used by a target tx
+func (c *Collector) AddGasUsed(contract, method string, gasUsed uint64) {
+	c.gasUsedTotal.WithLabelValues(contract, method).Add(float64(gasUsed))
+}
+
+// AddSubscriptionReconnect counts a re-established subscription of route
+func (c *Collector) AddSubscriptionReconnect(route string) {
+	c.subscriptionReconnectsTotal.WithLabelValues(route).Inc()
+}
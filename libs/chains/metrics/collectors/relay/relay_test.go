@@ -0,0 +1,30 @@
+package relay
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelayCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector := NewCollector(reg)
+
+	collector.AddEvent("Deposit", StatusReceived)
+	collector.AddEvent("Deposit", StatusReceived)
+	collector.AddEvent("Deposit", StatusForwarded)
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.eventsTotal.WithLabelValues("Deposit", StatusReceived)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.eventsTotal.WithLabelValues("Deposit", StatusForwarded)))
+
+	collector.AddGasUsed("PellStrategyManager", "SyncDepositState", 21000)
+	collector.AddGasUsed("PellStrategyManager", "SyncDepositState", 1000)
+	assert.Equal(t, 22000.0, testutil.ToFloat64(collector.gasUsedTotal.WithLabelValues("PellStrategyManager", "SyncDepositState")))
+
+	collector.AddSubscriptionReconnect("Deposit")
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.subscriptionReconnectsTotal.WithLabelValues("Deposit")))
+
+	collector.ObserveForwardLatencySeconds("Deposit", 3)
+	assert.Equal(t, 1, testutil.CollectAndCount(collector.forwardLatencySeconds))
+}
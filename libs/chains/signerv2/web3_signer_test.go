@@ -0,0 +1,86 @@
+package signerv2
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWeb3SignerStandIn serves eth_signTransaction by signing a legacy tx with privateKey
+func newWeb3SignerStandIn(t *testing.T, chainID *big.Int) (*httptest.Server, common.Address) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string              `json:"method"`
+			Params []map[string]string `json:"params"`
+			ID     string              `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_signTransaction", req.Method)
+		params := req.Params[0]
+
+		to := common.HexToAddress(params["to"])
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    hexutil.MustDecodeUint64(params["nonce"]),
+			GasPrice: hexutil.MustDecodeBig(params["gasPrice"]),
+			Gas:      hexutil.MustDecodeUint64(params["gas"]),
+			To:       &to,
+			Data:     hexutil.MustDecode(params["data"]),
+		})
+		signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
+		require.NoError(t, err)
+		raw, err := rlp.EncodeToBytes(signedTx)
+		require.NoError(t, err)
+
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  hexutil.Encode(raw),
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, crypto.PubkeyToAddress(privateKey.PublicKey)
+}
+
+func TestWeb3SignerFromConfig(t *testing.T) {
+	chainID := big.NewInt(1337)
+	srv, address := newWeb3SignerStandIn(t, chainID)
+
+	signerFn, sender, err := SignerFromConfig(Config{Endpoint: srv.URL, Address: address.Hex()}, chainID)
+	require.NoError(t, err)
+	assert.Equal(t, address, sender)
+
+	to := common.HexToAddress("0x7a2088a1bFc9d81c55368AE168C2C02570cB814F")
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    7,
+		GasPrice: big.NewInt(1_000_000_000),
+		Gas:      21000,
+		To:       &to,
+		Data:     []byte{0x01, 0x02},
+	})
+
+	sign, err := signerFn(context.Background(), sender)
+	require.NoError(t, err)
+	signedTx, err := sign(sender, tx)
+	require.NoError(t, err)
+
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	require.NoError(t, err)
+	assert.Equal(t, address, from)
+	assert.Equal(t, tx.Nonce(), signedTx.Nonce())
+	assert.Equal(t, tx.Data(), signedTx.Data())
+}
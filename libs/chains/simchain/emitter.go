@@ -0,0 +1,58 @@
+//go:build simulated
+
+package simchain
+
+import (
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// EmitterCode is the runtime code of a contract that emits a LOG4 per record of its calldata.
+// A record is the size n of its data, four topics and the n bytes of data, see EmitLogsCalldata.
+// Deployed at the address of a source contract, it raises the events the routes subscribe to
+// without deploying the real contract, several in one tx when a route reads its siblings.
+// Events with fewer indexed arguments get zero topics at the end, which their decoding ignores.
+// It stops at the first incomplete record, a forward to a target starts with a 4 bytes selector
+// that reads as a size larger than its calldata, so it also accepts the forwards of the routes.
+var EmitterCode = []byte{
+	0x60, 0x00, // ptr = 0
+	0x5b,                   // loop:
+	0x80, 0x60, 0xa0, 0x01, // ptr + 0xa0
+	0x36, 0x10, 0x60, 0x46, 0x57, // jumpi(stop, calldatasize < ptr + 0xa0)
+	0x80, 0x35, // n = calldataload(ptr)
+	0x80, 0x36, 0x10, 0x60, 0x46, 0x57, // jumpi(stop, calldatasize < n)
+	0x80, 0x82, 0x60, 0xa0, 0x01, 0x01, // ptr + 0xa0 + n
+	0x36, 0x10, 0x60, 0x46, 0x57, // jumpi(stop, calldatasize < ptr + 0xa0 + n)
+	0x80, 0x82, 0x60, 0xa0, 0x01, 0x60, 0x00, 0x37, // calldatacopy(0, ptr + 0xa0, n)
+	0x81, 0x60, 0x80, 0x01, 0x35, // topic3 = calldataload(ptr + 0x80)
+	0x82, 0x60, 0x60, 0x01, 0x35, // topic2 = calldataload(ptr + 0x60)
+	0x83, 0x60, 0x40, 0x01, 0x35, // topic1 = calldataload(ptr + 0x40)
+	0x84, 0x60, 0x20, 0x01, 0x35, // topic0 = calldataload(ptr + 0x20)
+	0x84, 0x60, 0x00, // offset = 0, size = n
+	0xa4,                   // log4(offset, size, topic0, topic1, topic2, topic3)
+	0x60, 0xa0, 0x01, 0x01, // ptr = ptr + 0xa0 + n
+	0x60, 0x02, 0x56, // jump(loop)
+	0x5b, 0x00, // stop:
+}
+
+// EmitCalldata returns the calldata that makes an emitter log topics and data, at most four topics
+func EmitCalldata(topics []gethcommon.Hash, data []byte) []byte {
+	return EmitLogsCalldata(gethtypes.Log{Topics: topics, Data: data})
+}
+
+// EmitLogsCalldata returns the calldata that makes an emitter raise logs in order, in a single tx.
+// Only the topics and data of the logs are used, the address is the one of the emitter.
+func EmitLogsCalldata(logs ...gethtypes.Log) []byte {
+	var calldata []byte
+	for _, lg := range logs {
+		record := make([]byte, 5*gethcommon.HashLength, 5*gethcommon.HashLength+len(lg.Data))
+		copy(record, gethcommon.BigToHash(big.NewInt(int64(len(lg.Data)))).Bytes())
+		for i, topic := range lg.Topics[:min(len(lg.Topics), 4)] {
+			copy(record[(i+1)*gethcommon.HashLength:], topic[:])
+		}
+		calldata = append(calldata, append(record, lg.Data...)...)
+	}
+	return calldata
+}
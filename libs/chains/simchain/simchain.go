@@ -0,0 +1,188 @@
+//go:build simulated
+
+// Package simchain runs an in-process chain on the simulated backend of go-ethereum. Blocks are
+// mined on an interval, so clients that poll for receipts behave like on a dev node.
+//
+// The simulated backend pulls in the node and database packages of go-ethereum, so the package
+// and its tests only build with the simulated tag: go test -tags simulated ./...
+package simchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/txmgr"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/wallet"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var (
+	// DevKeys are the first anvil/hardhat dev keys, they are funded on every chain.
+	// The first one is the default deployer of the emulator.
+	DevKeys = []*ecdsa.PrivateKey{
+		mustKey("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"),
+		mustKey("59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"),
+		mustKey("5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9ecbd82ebf1f9"),
+	}
+
+	devBalance           = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	defaultBlockInterval = 200 * time.Millisecond
+)
+
+// Chain is a running simulated chain
+type Chain struct {
+	// Client serves the rpc and the log subscriptions of the chain
+	Client  eth.Client
+	ChainID *big.Int
+
+	backend *simulated.Backend
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type options struct {
+	alloc         types.GenesisAlloc
+	blockInterval time.Duration
+}
+
+// Option configures the genesis and the mining of a Chain
+type Option func(*options)
+
+// WithCode deploys code at address in the genesis block
+func WithCode(address gethcommon.Address, code []byte) Option {
+	return func(o *options) {
+		account := o.alloc[address]
+		account.Code = code
+		if account.Balance == nil {
+			account.Balance = new(big.Int)
+		}
+		o.alloc[address] = account
+	}
+}
+
+// WithBalance funds address in the genesis block
+func WithBalance(address gethcommon.Address, balance *big.Int) Option {
+	return func(o *options) {
+		account := o.alloc[address]
+		account.Balance = balance
+		o.alloc[address] = account
+	}
+}
+
+// WithBlockInterval sets the time between two blocks
+func WithBlockInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.blockInterval = interval
+	}
+}
+
+// New starts a chain with the dev keys funded, it mines until Close
+func New(ctx context.Context, opts ...Option) (*Chain, error) {
+	o := &options{
+		alloc:         types.GenesisAlloc{},
+		blockInterval: defaultBlockInterval,
+	}
+	for _, key := range DevKeys {
+		o.alloc[crypto.PubkeyToAddress(key.PublicKey)] = types.Account{Balance: devBalance}
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	backend := simulated.NewBackend(o.alloc)
+	client, ok := backend.Client().(eth.Client)
+	if !ok {
+		_ = backend.Close()
+		return nil, errors.New("simulated client does not implement eth.Client")
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		_ = backend.Close()
+		return nil, errors.Wrap(err, "failed to get chain id")
+	}
+
+	c := &Chain{
+		Client:  client,
+		ChainID: chainID,
+		backend: backend,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go c.mine(o.blockInterval)
+	return c, nil
+}
+
+func (c *Chain) mine(interval time.Duration) {
+	defer close(c.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.backend.Commit()
+		}
+	}
+}
+
+// Close stops mining and shuts the chain down
+func (c *Chain) Close() error {
+	close(c.stop)
+	<-c.done
+	return c.backend.Close()
+}
+
+// TxManager returns a tx manager that signs with key
+func (c *Chain) TxManager(key *ecdsa.PrivateKey, logger log.Logger) (*txmgr.SimpleTxManager, error) {
+	keyWallet, sender, err := wallet.GetLocalGetWalletByPrivateKey(key, c.Client, c.ChainID, logger)
+	if err != nil {
+		return nil, err
+	}
+	return txmgr.NewSimpleTxManager(keyWallet, c.Client, logger, sender), nil
+}
+
+// Call sends a tx with data to address through txMgr and waits for its receipt. The tx manager
+// sends the nonce of the tx, it is set to the pending nonce of its sender.
+func (c *Chain) Call(
+	ctx context.Context, txMgr txmgr.TxManager, address gethcommon.Address, data []byte,
+) (*types.Receipt, error) {
+	opts, err := txMgr.GetNoSendTxOpts()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := c.Client.PendingNonceAt(ctx, opts.From)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get nonce")
+	}
+	receipt, err := txMgr.Send(ctx, types.NewTx(&types.DynamicFeeTx{
+		ChainID: c.ChainID,
+		Nonce:   nonce,
+		To:      &address,
+		Data:    data,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, errors.Errorf("tx %s to %s reverted", receipt.TxHash.Hex(), address.Hex())
+	}
+	return receipt, nil
+}
+
+func mustKey(hex string) *ecdsa.PrivateKey {
+	key, err := crypto.HexToECDSA(hex)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
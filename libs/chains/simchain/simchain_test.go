@@ -0,0 +1,105 @@
+//go:build simulated
+
+package simchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+func TestChainTxManager(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	chain, err := New(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, chain.Close()) }()
+
+	txMgr, err := chain.TxManager(DevKeys[0], log.NewNopLogger())
+	require.NoError(t, err)
+
+	to := gethcommon.HexToAddress("0x00000000000000000000000000000000000000aa")
+	receipt, err := txMgr.Send(ctx, types.NewTx(&types.DynamicFeeTx{
+		ChainID: chain.ChainID,
+		To:      &to,
+		Value:   big.NewInt(1000),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	balance, err := chain.Client.BalanceAt(ctx, to, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), balance)
+}
+
+func TestEmitter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	emitter := gethcommon.HexToAddress("0x00000000000000000000000000000000000000e1")
+	chain, err := New(ctx, WithCode(emitter, EmitterCode))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, chain.Close()) }()
+
+	logs := make(chan types.Log, 1)
+	sub, err := chain.Client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{Addresses: []gethcommon.Address{emitter}}, logs)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	txMgr, err := chain.TxManager(DevKeys[1], log.NewNopLogger())
+	require.NoError(t, err)
+
+	topics := []gethcommon.Hash{
+		crypto.Keccak256Hash([]byte("Event(address,uint256)")),
+		gethcommon.BytesToHash(emitter.Bytes()),
+	}
+	data := gethcommon.LeftPadBytes(big.NewInt(42).Bytes(), 32)
+	receipt, err := chain.Call(ctx, txMgr, emitter, EmitCalldata(topics, data))
+	require.NoError(t, err)
+	require.Len(t, receipt.Logs, 1)
+
+	select {
+	case lg := <-logs:
+		assert.Equal(t, emitter, lg.Address)
+		assert.Equal(t, []gethcommon.Hash{topics[0], topics[1], {}, {}}, lg.Topics)
+		assert.Equal(t, data, lg.Data)
+		assert.Equal(t, receipt.TxHash, lg.TxHash)
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-ctx.Done():
+		t.Fatal("log not received")
+	}
+
+	// several logs in one tx
+	receipt, err = chain.Call(ctx, txMgr, emitter, EmitLogsCalldata(
+		types.Log{Topics: topics[:1], Data: data},
+		types.Log{Topics: topics},
+	))
+	require.NoError(t, err)
+	require.Len(t, receipt.Logs, 2)
+	assert.Equal(t, []gethcommon.Hash{topics[0], {}, {}, {}}, receipt.Logs[0].Topics)
+	assert.Equal(t, data, receipt.Logs[0].Data)
+	assert.Equal(t, []gethcommon.Hash{topics[0], topics[1], {}, {}}, receipt.Logs[1].Topics)
+	assert.Empty(t, receipt.Logs[1].Data)
+
+	// the forwards to a target are accepted without a log
+	for _, calldata := range [][]byte{
+		make([]byte, 68),
+		append(crypto.Keccak256([]byte("syncDelegateState(uint256,address,address)"))[:4], make([]byte, 160)...),
+	} {
+		receipt, err = chain.Call(ctx, txMgr, emitter, calldata)
+		require.NoError(t, err)
+		assert.Empty(t, receipt.Logs)
+	}
+}
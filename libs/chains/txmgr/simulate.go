@@ -0,0 +1,96 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
+)
+
+type SimulationStatus string
+
+const (
+	// SimulationOK the call succeeded and the tx can be broadcast
+	SimulationOK SimulationStatus = "ok"
+	// SimulationReverted the call reverted, retrying it would revert again
+	SimulationReverted SimulationStatus = "reverted"
+	// SimulationTransient the call could not be evaluated, e.g. the node was unreachable
+	SimulationTransient SimulationStatus = "transient"
+)
+
+// SimulationResult is the outcome of a pre-flight eth_call of a transaction
+type SimulationResult struct {
+	Status SimulationStatus
+	// Reason is the decoded revert reason, only set when Status is SimulationReverted
+	Reason string
+	Err    error
+}
+
+func (r *SimulationResult) OK() bool {
+	return r.Status == SimulationOK
+}
+
+// Simulate executes tx with eth_call against the latest block as if it was sent by from,
+// nothing is signed or broadcast so no nonce or gas is spent.
+func Simulate(ctx context.Context, client eth.Client, from common.Address, tx *types.Transaction) *SimulationResult {
+	_, err := client.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, nil)
+	return ClassifyCallError(err)
+}
+
+// ClassifyCallError tells apart a permanent revert from a transient rpc failure.
+// It can be used on errors returned by eth_call and eth_estimateGas.
+func ClassifyCallError(err error) *SimulationResult {
+	if err == nil {
+		return &SimulationResult{Status: SimulationOK}
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			return &SimulationResult{
+				Status: SimulationReverted,
+				Reason: decodeRevertData(data, dataErr.Error()),
+				Err:    err,
+			}
+		}
+	}
+
+	// some nodes (and the abi bindings when estimating gas) only expose the revert in the message
+	msg := err.Error()
+	if idx := strings.Index(msg, "execution reverted"); idx >= 0 {
+		return &SimulationResult{
+			Status: SimulationReverted,
+			Reason: msg[idx:],
+			Err:    err,
+		}
+	}
+
+	return &SimulationResult{Status: SimulationTransient, Err: err}
+}
+
+// decodeRevertData decodes Error(string) and Panic(uint256) payloads, custom errors are
+// reported by their raw data.
+func decodeRevertData(data string, fallback string) string {
+	raw, err := hexutil.Decode(data)
+	if err != nil || len(raw) < 4 {
+		return fallback
+	}
+	if reason, err := abi.UnpackRevert(raw); err == nil {
+		return reason
+	}
+	return fmt.Sprintf("custom error: %s", data)
+}
@@ -0,0 +1,44 @@
+package txmgr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type revertError struct {
+	msg  string
+	data string
+}
+
+func (e *revertError) Error() string          { return e.msg }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+// Error(string) with "RR25"
+const rr25RevertData = "0x08c379a0" +
+	"0000000000000000000000000000000000000000000000000000000000000020" +
+	"0000000000000000000000000000000000000000000000000000000000000004" +
+	"5252323500000000000000000000000000000000000000000000000000000000"
+
+func TestClassifyCallError(t *testing.T) {
+	res := ClassifyCallError(nil)
+	assert.True(t, res.OK())
+
+	res = ClassifyCallError(&revertError{msg: "execution reverted", data: rr25RevertData})
+	assert.Equal(t, SimulationReverted, res.Status)
+	assert.Equal(t, "RR25", res.Reason)
+
+	res = ClassifyCallError(&revertError{msg: "execution reverted", data: hexutil.Encode([]byte{0xde, 0xad, 0xbe, 0xef})})
+	assert.Equal(t, SimulationReverted, res.Status)
+	assert.Equal(t, "custom error: 0xdeadbeef", res.Reason)
+
+	res = ClassifyCallError(errors.New("failed to estimate gas: execution reverted: revert: RR25"))
+	assert.Equal(t, SimulationReverted, res.Status)
+	assert.Equal(t, "execution reverted: revert: RR25", res.Reason)
+
+	res = ClassifyCallError(errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"))
+	assert.Equal(t, SimulationTransient, res.Status)
+	assert.Empty(t, res.Reason)
+}
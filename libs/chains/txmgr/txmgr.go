@@ -10,10 +10,13 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth"
 	"github.com/0xPellNetwork/pell-emulator/libs/chains/wallet"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
+	"github.com/0xPellNetwork/pell-emulator/libs/tracing"
 )
 
 var (
@@ -77,13 +80,43 @@ func (m *SimpleTxManager) WithGasLimitMultiplier(multiplier float64) *SimpleTxMa
 // and resign the transaction after adding the nonce and gas limit.
 // To check out the whole flow on how this works, check out the README.md in this folder
 func (m *SimpleTxManager) Send(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	sendCtx, span := tracing.Start(ctx, "sign_send", attribute.String("sender", m.sender.Hex()))
+	txID, err := m.broadcast(sendCtx, tx)
+	endSendSpan(span, txID, err)
+	if err != nil {
+		return nil, err
+	}
+
+	waitCtx, span := tracing.Start(ctx, "wait_receipt", attribute.String("target.tx_hash", txID))
+	receipt, err := m.waitForReceipt(waitCtx, txID)
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		m.log.Info("Transaction receipt not found", "err", err)
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+func endSendSpan(span trace.Span, txID wallet.TxID, err error) {
+	if err != nil {
+		tracing.RecordError(span, err)
+	} else {
+		span.SetAttributes(attribute.String("target.tx_hash", txID))
+	}
+	span.End()
+}
+
+// broadcast completes, signs and sends the tx without waiting for it to be mined
+func (m *SimpleTxManager) broadcast(ctx context.Context, tx *types.Transaction) (wallet.TxID, error) {
 	// Estimate gas and nonce
 	// can't print tx hash in logs because the tx changes below when we complete and sign it
 	// so the txHash is meaningless at this point
 	m.log.Debug("Estimating gas and nonce")
 	tx, err := m.estimateGasAndNonce(ctx, tx)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	bumpedGasTx := &types.DynamicFeeTx{
 		To:        tx.To(),
@@ -96,16 +129,10 @@ func (m *SimpleTxManager) Send(ctx context.Context, tx *types.Transaction) (*typ
 	}
 	txID, err := m.wallet.SendTransaction(ctx, types.NewTx(bumpedGasTx))
 	if err != nil {
-		return nil, errors.Join(errors.New("send: failed to estimate gas and nonce"), err)
+		return "", errors.Join(errors.New("send: failed to estimate gas and nonce"), err)
 	}
 
-	receipt, err := m.waitForReceipt(ctx, txID)
-	if err != nil {
-		m.log.Info("Transaction receipt not found", "err", err)
-		return nil, err
-	}
-
-	return receipt, nil
+	return txID, nil
 }
 
 func NoopSigner(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
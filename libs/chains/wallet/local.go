@@ -70,3 +70,22 @@ func GetLocalGetWalletByPrivateKey(
 	}
 	return keyWallet, sender, nil
 }
+
+func GetWalletFromSignerConfig(
+	signerCfg signerv2.Config,
+	ethClient eth.Client,
+	chainID *big.Int,
+	logger log.Logger,
+) (Wallet, gethcommon.Address, error) {
+	lg := logger.With("module", "wallet/local")
+
+	sgn, sender, err := signerv2.SignerFromConfig(signerCfg, chainID)
+	if err != nil {
+		return nil, gethcommon.Address{}, err
+	}
+	keyWallet, err := NewPrivateKeyWallet(ethClient, sgn, sender, lg)
+	if err != nil {
+		return nil, gethcommon.Address{}, err
+	}
+	return keyWallet, sender, nil
+}
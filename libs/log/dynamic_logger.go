@@ -0,0 +1,93 @@
+package log
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// NewDynamicLogger returns a logger whose output can be replaced at runtime with Set,
+// e.g. to apply a new log level without restarting. Loggers derived with With follow the replacement.
+func NewDynamicLogger(next Logger) *DynamicLogger {
+	root := &dynamicRoot{}
+	root.next.Store(&next)
+	return &DynamicLogger{root: root}
+}
+
+type dynamicRoot struct {
+	next atomic.Pointer[Logger]
+	gen  atomic.Uint64
+}
+
+type DynamicLogger struct {
+	root    *dynamicRoot
+	keyVals []any
+
+	// cached is root.next with keyVals, rebuilt after a Set
+	mu        sync.Mutex
+	cached    Logger
+	cachedGen uint64
+}
+
+var _ Logger = (*DynamicLogger)(nil)
+
+// Set replaces the output of the logger and of every logger derived from it
+func (l *DynamicLogger) Set(next Logger) {
+	l.root.next.Store(&next)
+	l.root.gen.Add(1)
+}
+
+func (l *DynamicLogger) current() Logger {
+	if len(l.keyVals) == 0 {
+		return *l.root.next.Load()
+	}
+
+	gen := l.root.gen.Load()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cached == nil || l.cachedGen != gen {
+		l.cached = (*l.root.next.Load()).With(l.keyVals...)
+		l.cachedGen = gen
+	}
+	return l.cached
+}
+
+func (l *DynamicLogger) Info(msg string, keyVals ...any) {
+	l.current().Info(msg, keyVals...)
+}
+
+func (l *DynamicLogger) Error(msg string, keyVals ...any) {
+	l.current().Error(msg, keyVals...)
+}
+
+func (l *DynamicLogger) Debug(msg string, keyVals ...any) {
+	l.current().Debug(msg, keyVals...)
+}
+
+func (l *DynamicLogger) With(keyVals ...any) Logger {
+	merged := make([]any, 0, len(l.keyVals)+len(keyVals))
+	merged = append(merged, l.keyVals...)
+	merged = append(merged, keyVals...)
+	return &DynamicLogger{root: l.root, keyVals: merged}
+}
+
+func (l *DynamicLogger) Impl() any {
+	return l.current().Impl()
+}
+
+// NewLoggerWithFormat returns a logger for a level accepted by ParseLogLevel, an empty level
+// logs everything, and a "json" or "plain" format.
+func NewLoggerWithFormat(dst io.Writer, level, format string) (Logger, error) {
+	var options []Option
+	if format == "json" {
+		options = append(options, OutputJSONOption())
+	}
+	if level != "" {
+		filter, err := ParseLogLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, FilterOption(filter))
+	}
+	return NewLogger(dst, options...), nil
+}
@@ -0,0 +1,35 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+func TestDynamicLogger(t *testing.T) {
+	var first, second bytes.Buffer
+
+	dynamic := log.NewDynamicLogger(log.NewLogger(&first, log.OutputJSONOption(), log.TimeFormatOption("")))
+	child := dynamic.With(log.ModuleKey, "server")
+	child.Debug("before")
+	assert.Equal(t, `{"level":"debug","module":"server","message":"before"}`, strings.TrimSpace(first.String()))
+
+	next, err := log.NewLoggerWithFormat(&second, "info", "json")
+	require.NoError(t, err)
+	dynamic.Set(next)
+
+	child.Debug("filtered")
+	child.With("k", "v").Info("after")
+	assert.Contains(t, second.String(), `"module":"server","k":"v"`)
+	assert.Contains(t, second.String(), `"message":"after"`)
+	assert.NotContains(t, second.String(), "filtered")
+	assert.NotContains(t, first.String(), "after")
+
+	_, err = log.NewLoggerWithFormat(&second, "loud", "json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+// Package tracing sets up the OpenTelemetry tracer provider of the emulator, it exports to an
+// OTLP/HTTP collector or to a local file.
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	instrumentationName = "github.com/0xPellNetwork/pell-emulator"
+	otlpTracesPath      = "/v1/traces"
+)
+
+// Provider is a tracer provider that closes its trace file on Shutdown
+type Provider struct {
+	*sdktrace.TracerProvider
+	file *os.File
+}
+
+// NewProvider returns a provider that exports the spans of serviceName to the OTLP/HTTP collector
+// at otlpEndpoint, e.g. http://localhost:4318, and appends them as json lines to file. Either can
+// be empty. The /v1/traces path is added when otlpEndpoint has no path.
+func NewProvider(ctx context.Context, serviceName, otlpEndpoint, file string) (*Provider, error) {
+	p := &Provider{}
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	}
+
+	if otlpEndpoint != "" {
+		endpoint, err := otlpURL(otlpEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create otlp exporter")
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	if file != "" {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return nil, errors.Wrap(err, "failed to create trace file directory")
+		}
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open trace file")
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, errors.Wrap(err, "failed to create file exporter")
+		}
+		p.file = f
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	p.TracerProvider = sdktrace.NewTracerProvider(opts...)
+	return p, nil
+}
+
+func otlpURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid otlp endpoint %q", endpoint)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.Errorf("invalid otlp endpoint %q: scheme must be http or https", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpTracesPath
+	}
+	return u.String(), nil
+}
+
+// Shutdown flushes the spans and closes the exporters, a nil Provider does nothing
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	err := p.TracerProvider.Shutdown(ctx)
+	if p.file != nil {
+		if closeErr := p.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Tracer returns the tracer of the emulator from provider, a no-op tracer if provider is nil
+func Tracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+	return provider.Tracer(instrumentationName)
+}
+
+// Start starts a child of the span in ctx with the provider of that span, nothing is traced
+// if ctx carries no span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer(trace.SpanFromContext(ctx).TracerProvider()).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// RecordError records err on span and marks the span failed, a nil err is ignored
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
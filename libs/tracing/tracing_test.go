@@ -0,0 +1,166 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStart(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	// no span in the context, nothing is traced
+	_, orphan := Start(context.Background(), "orphan")
+	assert.False(t, orphan.IsRecording())
+	orphan.End()
+
+	ctx, root := Tracer(provider).Start(context.Background(), "root")
+	_, child := Start(ctx, "child", attribute.String("route", "Deposit"))
+	assert.True(t, child.IsRecording())
+	child.End()
+	root.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, root.SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	assert.Equal(t, root.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, []attribute.KeyValue{attribute.String("route", "Deposit")}, spans[0].Attributes())
+
+	_, nop := Tracer(nil).Start(context.Background(), "nop")
+	assert.False(t, nop.IsRecording())
+}
+
+func TestRecordError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, failed := Tracer(provider).Start(context.Background(), "failed")
+	RecordError(failed, errors.New("boom"))
+	failed.End()
+	_, ok := Tracer(provider).Start(context.Background(), "ok")
+	RecordError(ok, nil)
+	ok.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, sdktrace.Status{Code: codes.Error, Description: "boom"}, spans[0].Status())
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Empty(t, spans[1].Events())
+}
+
+// fileSpan is the part of a span written by the file exporter the test reads
+type fileSpan struct {
+	Name        string
+	SpanContext struct{ SpanID string }
+	Parent      struct{ SpanID string }
+	Status      struct{ Code, Description string }
+	Resource    []struct {
+		Key   string
+		Value struct{ Value any }
+	}
+}
+
+func TestNewProviderFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces", "spans.jsonl")
+	provider, err := NewProvider(context.Background(), "test", "", path)
+	require.NoError(t, err)
+
+	ctx, root := Tracer(provider).Start(context.Background(), "root")
+	_, child := Start(ctx, "child")
+	RecordError(child, errors.New("boom"))
+	child.End()
+	root.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []fileSpan
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line fileSpan
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, lines, 2)
+	assert.Equal(t, "child", lines[0].Name)
+	assert.Equal(t, "Error", lines[0].Status.Code)
+	assert.Equal(t, "boom", lines[0].Status.Description)
+	assert.Equal(t, lines[1].SpanContext.SpanID, lines[0].Parent.SpanID)
+	require.Len(t, lines[1].Resource, 1)
+	assert.Equal(t, "service.name", lines[1].Resource[0].Key)
+	assert.Equal(t, "test", lines[1].Resource[0].Value.Value)
+}
+
+func TestNewProviderOTLP(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	provider, err := NewProvider(context.Background(), "test", collector.URL, "")
+	require.NoError(t, err)
+	_, span := Tracer(provider).Start(context.Background(), "forward")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{otlpTracesPath}, paths)
+}
+
+func TestNewProviderInvalidEndpoint(t *testing.T) {
+	var tests = map[string]struct {
+		endpoint string
+		wantErr  string
+	}{
+		"grpc scheme": {
+			endpoint: "grpc://localhost:4317",
+			wantErr:  `invalid otlp endpoint "grpc://localhost:4317": scheme must be http or https`,
+		},
+		"no scheme": {
+			endpoint: "localhost:4318",
+			wantErr:  "scheme must be http or https",
+		},
+		"invalid url": {
+			endpoint: "http://[::1",
+			wantErr:  `invalid otlp endpoint "http://[::1"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewProvider(context.Background(), "test", tt.endpoint, "")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	var provider *Provider
+	assert.NoError(t, provider.Shutdown(context.Background()))
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+)
+
+// startBalanceMonitor checks the balance of every signing address, reports it on /status,
+// warns when it is low and tops it up on dev chains if enabled
+func (s *Server) startBalanceMonitor(ctx context.Context, monitorCfg *config.BalanceMonitorConfig) error {
+	if monitorCfg == nil {
+		s.logger.Info("balance monitor disabled")
+		return nil
+	}
+
+	lg := s.logger.With("func", "BalanceMonitor")
+
+	warnBalance, ok := new(big.Int).SetString(monitorCfg.WarnBalance, 10)
+	if !ok {
+		return errors.Errorf("invalid warn balance: %s", monitorCfg.WarnBalance)
+	}
+	topUpBalance, ok := new(big.Int).SetString(monitorCfg.TopUpBalance, 10)
+	if monitorCfg.TopUp && !ok {
+		return errors.Errorf("invalid top up balance: %s", monitorCfg.TopUpBalance)
+	}
+	// the config is shared with /status and reload, keep the effective setting here
+	topUp := monitorCfg.TopUp
+	if topUp && !s.chainBindings().IsDevChain() {
+		lg.Error("top up is only supported on dev chains, disabled", "chainID", s.chainBindings().ChainID)
+		topUp = false
+	}
+
+	interval := time.Duration(monitorCfg.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.checkSignerBalances(ctx, monitorCfg, topUp, warnBalance, topUpBalance)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) checkSignerBalances(
+	ctx context.Context,
+	monitorCfg *config.BalanceMonitorConfig,
+	topUp bool,
+	warnBalance, topUpBalance *big.Int,
+) {
+	lg := s.logger.With("func", "BalanceMonitor")
+	bindings := s.chainBindings()
+
+	signers := make([]SignerBalance, 0, len(bindings.SignerAddresses))
+	for _, address := range bindings.SignerAddresses {
+		balance, err := bindings.RPCClient.BalanceAt(ctx, address, nil)
+		if err != nil {
+			lg.Error("failed to get signer balance", "signer", address, "error", err)
+			continue
+		}
+
+		low := balance.Cmp(warnBalance) < 0
+		if low {
+			lg.Error("signer balance is low, forwards will fail once it runs out",
+				"signer", address,
+				"balance", balance,
+				"warnBalance", warnBalance,
+			)
+		}
+
+		if low && topUp {
+			err = bindings.TopUp(ctx, monitorCfg, address, balance, topUpBalance)
+			if err != nil {
+				lg.Error("failed to top up signer", "signer", address, "error", err)
+			} else if newBalance, err := bindings.RPCClient.BalanceAt(ctx, address, nil); err == nil {
+				balance = newBalance
+				low = balance.Cmp(warnBalance) < 0
+			}
+		}
+
+		signers = append(signers, SignerBalance{
+			Address: address.Hex(),
+			Balance: balance.String(),
+			Low:     low,
+		})
+	}
+
+	emulatorServerState.SetSigners(signers)
+}
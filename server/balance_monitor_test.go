@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+)
+
+func TestBalanceMonitor(t *testing.T) {
+	var tests = map[string]struct {
+		chainID *big.Int
+		balance *big.Int
+		topUp   bool
+		want    SignerBalance
+	}{
+		"above the warn balance": {
+			chainID: testChainID,
+			balance: big.NewInt(2000),
+			topUp:   true,
+			want:    SignerBalance{Balance: "2000"},
+		},
+		"low balance": {
+			chainID: testChainID,
+			balance: big.NewInt(10),
+			want:    SignerBalance{Balance: "10", Low: true},
+		},
+		"topped up": {
+			chainID: testChainID,
+			balance: big.NewInt(10),
+			topUp:   true,
+			want:    SignerBalance{Balance: "5000"},
+		},
+		"no top up on a non dev chain": {
+			chainID: big.NewInt(1),
+			balance: big.NewInt(10),
+			topUp:   true,
+			want:    SignerBalance{Balance: "10", Low: true},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := ethtest.NewClient(tt.chainID)
+			client.SetBalance(testSender, tt.balance)
+			node := ethtest.NewDevNodeServer(client)
+			defer node.Close()
+
+			cfg := testConfig()
+			cfg.BalanceMonitor.WarnBalance = "1000"
+			cfg.BalanceMonitor.TopUpBalance = "5000"
+			cfg.BalanceMonitor.TopUp = tt.topUp
+			s := newTestServer(t, cfg, chains.WithClients(client, client))
+			s.bindings.Endpoint.RPCURL = node.URL
+
+			// a done context runs a single check
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			require.NoError(t, s.startBalanceMonitor(ctx, cfg.BalanceMonitor))
+
+			// the effective setting does not leak into the config
+			assert.Equal(t, tt.topUp, cfg.BalanceMonitor.TopUp)
+
+			statusMutex.RLock()
+			signers := emulatorServerState.Signers
+			statusMutex.RUnlock()
+			tt.want.Address = testSender.Hex()
+			assert.Equal(t, []SignerBalance{tt.want}, signers)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/consistency"
+)
+
+// startConsistencyCheck compares the mirrored state with its source chain every interval
+// and keeps the last report for /health/consistency, see the verify command.
+// The mismatches are corrected if the reconciler is enabled.
+func (s *Server) startConsistencyCheck(ctx context.Context, verifyCfg *config.VerifyConfig) error {
+	if verifyCfg == nil || verifyCfg.IntervalSeconds <= 0 {
+		s.logger.Info("consistency check disabled")
+		return nil
+	}
+
+	lg := s.logger.With("func", "ConsistencyCheck")
+	var reconciler *consistency.Reconciler
+	if verifyCfg.Reconcile != nil && verifyCfg.Reconcile.Enabled {
+		reconciler = consistency.NewReconciler(verifyCfg.Reconcile, s.journal, lg)
+		lg.Info("reconciler enabled", "dryRun", verifyCfg.Reconcile.DryRun)
+	}
+
+	ticker := time.NewTicker(time.Duration(verifyCfg.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		bindings := s.chainBindings()
+		report, err := consistency.NewChecker(bindings, verifyCfg, lg).Check(ctx)
+		if err != nil {
+			lg.Error("consistency check failed", "error", err)
+			continue
+		}
+		for _, m := range report.Mismatches {
+			lg.Error("mirrored state does not match its source",
+				"kind", m.Kind,
+				"staker", m.Staker,
+				"operator", m.Operator,
+				"strategy", m.Strategy,
+				"source", m.Source,
+				"expected", m.Expected,
+				"mirror", m.Mirror,
+				"actual", m.Actual,
+				"route", m.Route,
+				"cause", m.Cause,
+			)
+		}
+		lg.Info("consistency check done", "checked", report.Checked, "mismatches", len(report.Mismatches),
+			"unreadable", len(report.Unreadable))
+		s.consistency.Store(report)
+
+		if reconciler != nil && len(report.Mismatches) > 0 {
+			reconciler.Reconcile(ctx, bindings, report)
+		}
+	}
+}
+
+// handleConsistency returns the report of the last consistency check, 404 before the first one
+func (s *Server) handleConsistency(w http.ResponseWriter, _ *http.Request) {
+	report := s.consistency.Load()
+	if report == nil {
+		writeJSON(w, http.StatusNotFound, probeResult{Message: "no consistency check yet"})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	events2 "github.com/0xPellNetwork/pell-emulator/internal/events"
+)
+
+type routesHealth struct {
+	HeadBlock uint64                `json:"head_block"`
+	Routes    []events2.RouteStatus `json:"routes"`
+}
+
+type probeResult struct {
+	Ready   bool     `json:"ready"`
+	Message string   `json:"message"`
+	Down    []string `json:"down,omitempty"`
+}
+
+func (s *Server) routeStatuses() []events2.RouteStatus {
+	s.routesMu.RLock()
+	defer s.routesMu.RUnlock()
+
+	head := s.headBlock.Load()
+	statuses := make([]events2.RouteStatus, 0, len(s.routes))
+	for _, route := range s.routes {
+		statuses = append(statuses, route.Status(head))
+	}
+	return statuses
+}
+
+// handleRouteHealth reports subscription state, activity and lag of every route
+func (s *Server) handleRouteHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, routesHealth{
+		HeadBlock: s.headBlock.Load(),
+		Routes:    s.routeStatuses(),
+	})
+}
+
+// handleLivez answers as long as the process serves http
+func (s *Server) handleLivez(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, probeResult{Ready: true, Message: "ok"})
+}
+
+// handleReadyz is ok once the emulator started and every route is subscribed
+func (s *Server) handleReadyz(w http.ResponseWriter, _ *http.Request) {
+	statusMutex.RLock()
+	res := probeResult{Ready: emulatorServerState.Ready, Message: emulatorServerState.Message}
+	statusMutex.RUnlock()
+
+	for _, route := range s.routeStatuses() {
+		if !route.Subscribed {
+			res.Down = append(res.Down, route.Route)
+		}
+	}
+	if res.Ready && len(res.Down) > 0 {
+		res.Ready = false
+		res.Message = "routes not subscribed"
+	}
+
+	code := http.StatusOK
+	if !res.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, res)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
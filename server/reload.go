@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var (
+	reloadTimeout  = 60 * time.Second
+	reloadDebounce = 500 * time.Millisecond
+
+	// bindingsKeys are the config keys the chain bindings are built from
+	bindingsKeys = map[string]bool{
+		"contract_address":   true,
+		"rpc_url":            true,
+		"ws_url":             true,
+		"fallback_endpoints": true,
+	}
+)
+
+// Reload loads the config again and applies it without restarting. The bindings and the
+// routes are rebuilt if needed, the previous ones are kept if that fails. Changes that
+// need a restart, e.g. the port, reject the whole reload.
+func (s *Server) Reload(ctx context.Context) error {
+	if s.loadConfig == nil {
+		return errors.New("config reload is not enabled")
+	}
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	next, err := s.loadConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	prev := s.currentConfig()
+	changed, err := prev.ChangedKeys(next)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		s.logger.Info("config reloaded, nothing changed")
+		return nil
+	}
+	if restart := config.RestartRequiredChanges(changed); len(restart) > 0 {
+		return errors.Errorf("changes of %s cannot be applied live, restart the emulator", strings.Join(restart, ", "))
+	}
+
+	var nextLogger log.Logger
+	if s.dynamicLogger != nil && (next.LogLevel != prev.LogLevel || next.LogFormat != prev.LogFormat) {
+		nextLogger, err = log.NewLoggerWithFormat(os.Stdout, next.LogLevel, next.LogFormat)
+		if err != nil {
+			return errors.Wrap(err, "invalid log config")
+		}
+	}
+
+	if hasAny(changed, bindingsKeys) {
+		if err := s.reloadBindings(ctx, next, changed); err != nil {
+			return err
+		}
+	}
+
+	if nextLogger != nil {
+		s.dynamicLogger.Set(nextLogger)
+	}
+	s.cfg.Store(next)
+	config.SetGlobalConfig(next)
+	s.logger.Info("config reloaded", "changed", changed)
+	return nil
+}
+
+// reloadBindings builds bindings for next and hands them to the supervisor, which restarts the routes on them
+func (s *Server) reloadBindings(ctx context.Context, next *config.Config, changed []string) error {
+	bindings, err := s.chainBindings().Rebuild(ctx, next)
+	if err != nil {
+		return errors.Wrap(err, "failed to build bindings for the reloaded config")
+	}
+	if next.AutoUpdateConnector && hasAny(changed, map[string]bool{"contract_address": true}) {
+		if err := bindings.UpdateConnector(ctx); err != nil {
+			bindings.Close()
+			return errors.Wrap(err, "failed to update connector of the reloaded contracts")
+		}
+	}
+
+	swap := &bindingsSwap{next: bindings, done: make(chan error, 1)}
+	select {
+	case s.reloadCh <- swap:
+	case <-ctx.Done():
+		bindings.Close()
+		return errors.Wrap(ctx.Err(), "emulator is not running, try again once it is ready")
+	}
+
+	select {
+	case err := <-swap.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startConfigReload reloads the config when the file changes or on SIGHUP
+func (s *Server) startConfigReload(ctx context.Context) error {
+	if s.loadConfig == nil {
+		return nil
+	}
+	lg := s.logger.With("func", "ConfigReload")
+
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	var fileEvents <-chan fsnotify.Event
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		defer watcher.Close()
+		err = watcher.Add(filepath.Dir(s.configFile))
+	}
+	if err != nil {
+		lg.Error("failed to watch config file, reload on SIGHUP or /admin/reload only", "file", s.configFile, "error", err)
+	} else {
+		fileEvents = watcher.Events
+		lg.Info("watching config file", "file", s.configFile)
+	}
+
+	// editors write a file in several steps, reload once they are done
+	debounce := time.NewTimer(0)
+	<-debounce.C
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hupCh:
+			s.reloadAndLog(ctx, lg, "SIGHUP")
+		case event := <-fileEvents:
+			if filepath.Clean(event.Name) == filepath.Clean(s.configFile) && !event.Has(fsnotify.Chmod) {
+				debounce.Reset(reloadDebounce)
+			}
+		case <-debounce.C:
+			s.reloadAndLog(ctx, lg, "file change")
+		}
+	}
+}
+
+func (s *Server) reloadAndLog(ctx context.Context, lg log.Logger, trigger string) {
+	ctx, cancel := context.WithTimeout(ctx, reloadTimeout)
+	defer cancel()
+	if err := s.Reload(ctx); err != nil {
+		lg.Error("config reload rejected", "trigger", trigger, "error", err)
+	}
+}
+
+type reloadResult struct {
+	Reloaded bool   `json:"reloaded"`
+	Error    string `json:"error,omitempty"`
+}
+
+// handleReload reloads the config on POST /admin/reload
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, reloadResult{Error: "use POST"})
+		return
+	}
+	if s.loadConfig == nil {
+		writeJSON(w, http.StatusNotImplemented, reloadResult{Error: "config reload is not enabled"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), reloadTimeout)
+	defer cancel()
+	if err := s.Reload(ctx); err != nil {
+		writeJSON(w, http.StatusConflict, reloadResult{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, reloadResult{Reloaded: true})
+}
+
+func hasAny(keys []string, set map[string]bool) bool {
+	for _, key := range keys {
+		if set[key] {
+			return true
+		}
+	}
+	return false
+}
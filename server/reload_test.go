@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+)
+
+// reloadTo makes the reload of s load cfg
+func (s *Server) reloadTo(cfg *config.Config) {
+	s.loadConfig = func() (*config.Config, error) {
+		return cfg, nil
+	}
+}
+
+func TestReload(t *testing.T) {
+	var tests = map[string]struct {
+		change  func(cfg *config.Config)
+		wantErr string
+	}{
+		"nothing changed": {
+			change: func(cfg *config.Config) {},
+		},
+		"live key": {
+			change: func(cfg *config.Config) { cfg.LogLevel = "info" },
+		},
+		"restart only key": {
+			change:  func(cfg *config.Config) { cfg.Port = 9091 },
+			wantErr: "changes of port cannot be applied live, restart the emulator",
+		},
+		"live and restart only keys": {
+			change: func(cfg *config.Config) {
+				cfg.LogLevel = "info"
+				cfg.JournalFile = "journal.jsonl"
+				cfg.Routes = []string{"StakerDelegated"}
+			},
+			wantErr: "changes of journal_file, routes cannot be applied live",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := ethtest.NewClient(testChainID)
+			prev := testConfig()
+			s := newTestServer(t, prev, chains.WithClients(client, client))
+			bindings := s.chainBindings()
+
+			next := testConfig()
+			tt.change(next)
+			s.reloadTo(next)
+			err := s.Reload(context.Background())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Same(t, prev, s.currentConfig())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, next, s.currentConfig())
+			}
+			// no bindings key changed
+			assert.Same(t, bindings, s.chainBindings())
+		})
+	}
+
+	t.Run("not enabled", func(t *testing.T) {
+		client := ethtest.NewClient(testChainID)
+		s := newTestServer(t, testConfig(), chains.WithClients(client, client))
+		assert.ErrorContains(t, s.Reload(context.Background()), "config reload is not enabled")
+	})
+}
+
+func TestReloadRestartsRoutes(t *testing.T) {
+	fastSupervisor(t)
+	client := ethtest.NewClient(testChainID)
+	cfg := testConfig()
+	cfg.Routes = []string{"StakerDelegated"}
+	s := newTestServer(t, cfg, chains.WithClients(client, client))
+	first := s.chainBindings()
+
+	startSupervisor(t, s)
+	require.Eventually(t, func() bool {
+		return ready() && len(s.currentRoutes()) == 1 && client.Subscriptions() == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	firstRoute := s.currentRoutes()[0]
+
+	next := testConfig()
+	next.Routes = cfg.Routes
+	next.ContractAddress.StakingDelegationManager = "0x00000000000000000000000000000000000D0001"
+	s.reloadTo(next)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, s.Reload(ctx))
+
+	assert.Same(t, next, s.currentConfig())
+	assert.NotSame(t, first, s.chainBindings())
+	assert.Equal(t, next.ContractAddress.StakingDelegationManager,
+		s.chainBindings().ContractAddress.StakingDelegationManager)
+	require.Eventually(t, func() bool {
+		routes := s.currentRoutes()
+		return ready() && len(routes) == 1 && routes[0] != firstRoute && client.Subscriptions() == 1
+	}, 10*time.Second, 10*time.Millisecond)
+}
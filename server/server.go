@@ -5,21 +5,72 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/0xPellNetwork/pell-emulator/config"
 	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/consistency"
 	events2 "github.com/0xPellNetwork/pell-emulator/internal/events"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/metrics/collectors/relay"
+	rpccalls "github.com/0xPellNetwork/pell-emulator/libs/chains/metrics/collectors/rpc_calls"
 	"github.com/0xPellNetwork/pell-emulator/libs/log"
+	"github.com/0xPellNetwork/pell-emulator/libs/tracing"
 )
 
+// serviceName is the avs_name label of the rpc metrics and the service name of the traces
+const serviceName = "pell-emulator"
+
 type Server struct {
-	bindings *chains.ChainBindings
-	logger   log.Logger
-	port     int
+	// cfg is replaced by Reload, read it with currentConfig
+	cfg     atomic.Pointer[config.Config]
+	journal journal.Journal
+	logger  log.Logger
+	port    int
+
+	// bindings are replaced by the supervisor after a reconnect
+	bindingsMu sync.RWMutex
+	bindings   *chains.ChainBindings
+
+	registry     *prometheus.Registry
+	relayMetrics *relay.Collector
+	tracer       *tracing.Provider
+
+	// routes are set once the emulator started, headBlock is the latest block seen by the connection check
+	routesMu  sync.RWMutex
+	routes    []events2.IEvents
+	headBlock atomic.Uint64
+
+	// consistency is the report of the last periodic consistency check
+	consistency atomic.Pointer[consistency.Report]
+
+	// config reload, see WithConfigReload
+	configFile    string
+	loadConfig    func() (*config.Config, error)
+	dynamicLogger *log.DynamicLogger
+	reloadMu      sync.Mutex
+	reloadCh      chan *bindingsSwap
+}
+
+// Option configures optional features of the Server
+type Option func(*Server)
+
+// WithConfigReload reloads the config with load when configFile changes, on SIGHUP
+// or on POST /admin/reload. logger gets the reloaded log level and format.
+func WithConfigReload(configFile string, load func() (*config.Config, error), logger *log.DynamicLogger) Option {
+	return func(s *Server) {
+		s.configFile = configFile
+		s.loadConfig = load
+		s.dynamicLogger = logger
+	}
 }
 
 func NewServer(
@@ -27,20 +78,63 @@ func NewServer(
 	cfg *config.Config,
 	logger log.Logger,
 	port int,
+	opts ...Option,
 ) (*Server, error) {
-	bindings, err := chains.NewChainBindings(ctx, cfg, logger)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	connectors, err := events2.ConnectorContracts(cfg.Routes)
+	if err != nil {
+		logger.Error("Invalid routes", "error", err)
+		return nil, err
+	}
+
+	bindings, err := chains.NewChainBindings(ctx, cfg, logger,
+		chains.WithRPCCallsCollector(rpccalls.NewCollector(serviceName, registry)),
+		chains.WithConnectorContracts(connectors),
+	)
 	if err != nil {
 		logger.Error("Failed to create chain bindings", "error", err)
 		return nil, err
 	}
-	return &Server{
+
+	var jn journal.Journal = journal.NewNopJournal()
+	if cfg.JournalFile != "" {
+		jn, err = journal.NewFileJournal(cfg.JournalFile)
+		if err != nil {
+			logger.Error("Failed to open journal", "error", err, "file", cfg.JournalFile)
+			return nil, errors.Wrap(err, "failed to open journal")
+		}
+		logger.Info("journal enabled", "file", cfg.JournalFile)
+	}
+
+	tracer, err := newTracer(cfg.Tracing, logger)
+	if err != nil {
+		logger.Error("Failed to setup tracing", "error", err)
+		return nil, errors.Wrap(err, "failed to setup tracing")
+	}
+
+	s := &Server{
 		bindings: bindings,
+		journal:  jn,
 		logger:   logger,
 		port:     port,
-	}, nil
+
+		registry:     registry,
+		relayMetrics: relay.NewCollector(registry),
+		tracer:       tracer,
+		reloadCh:     make(chan *bindingsSwap),
+	}
+	s.cfg.Store(cfg)
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 func (s *Server) Start(ctx context.Context) error {
+	defer s.shutdownTracer()
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	// Start HTTP server
@@ -48,124 +142,65 @@ func (s *Server) Start(ctx context.Context) error {
 		return s.startHTTPServer(ctx, s.port)
 	})
 
-	// Start emulator server
-	emulatorCtx, emulatorCancel := context.WithCancel(ctx)
+	// Start emulator server, it is restarted on new connections by the supervisor
 	g.Go(func() error {
-		return s.startEmulator(emulatorCtx)
+		return s.superviseEmulator(ctx)
 	})
 
-	// Start ETH connection check
-	clientClosedCh := make(chan struct{})
+	// Start balance monitor
 	g.Go(func() error {
-		return s.startETHConnectionCheck(ctx, clientClosedCh)
+		return s.startBalanceMonitor(ctx, s.currentConfig().BalanceMonitor)
 	})
 
-	// Wait for all goroutines to finish
-	if err := g.Wait(); err != nil {
-		emulatorCancel() // Stop the emulator service
-		return err
-	}
+	// Start consistency check
+	g.Go(func() error {
+		return s.startConsistencyCheck(ctx, s.currentConfig().Verify)
+	})
 
-	select {
-	case <-clientClosedCh:
-		s.logger.Error("eth client closed, update state and stopping emulator")
-		emulatorServerState.Disable("eth client closed")
-		emulatorCancel()
-	case <-ctx.Done():
-		emulatorCancel()
-		return ctx.Err()
-	}
+	// Start config reload triggers
+	g.Go(func() error {
+		return s.startConfigReload(ctx)
+	})
 
-	<-ctx.Done()
-	return nil
+	return g.Wait()
 }
 
-// start a goroutine to monitor the connection of rpcClient and wsClient, every 5 seconds
-func (s *Server) startETHConnectionCheck(parrent context.Context, clientClosed chan struct{}) error {
-	var blockNumber uint64
-	var err error
-
-	lg := s.logger.With("func", "CheckClientClosed")
+// currentConfig returns the config the server runs with, the last reloaded one
+func (s *Server) currentConfig() *config.Config {
+	return s.cfg.Load()
+}
 
-	lg.Info("monitoring connection will be started soon")
+// chainBindings returns the bindings of the current connection
+func (s *Server) chainBindings() *chains.ChainBindings {
+	s.bindingsMu.RLock()
+	defer s.bindingsMu.RUnlock()
+	return s.bindings
+}
 
-	// init blockNumber
-	blockNumber, err = s.bindings.WsClient.BlockNumber(parrent)
+// startEmulator starts listening on every route with the current bindings, resume are the
+// positions of the stopped routes they replace, see events.WithResume
+func (s *Server) startEmulator(ctx context.Context, resume map[string]events2.LogPosition) error {
+	bindings := s.chainBindings()
+	events, err := events2.FilterRoutes(events2.GetAllEvents(
+		bindings.ChainID,
+		bindings.RPCClient,
+		bindings.RPCBindings,
+		bindings.WsClient,
+		bindings.WsBindings,
+		bindings.TxMgr,
+		s.logger,
+		events2.WithJournal(s.journal),
+		events2.WithMetrics(s.relayMetrics),
+		events2.WithTracerProvider(s.tracerProvider()),
+		events2.WithResume(resume),
+	), s.currentConfig().Routes)
 	if err != nil {
-		lg.Error("init failed to get blockNumber from wsClient",
-			"error", err,
-		)
 		return err
 	}
-	lg.Info("monitoring connection started", "blockNumber", blockNumber)
-
-	var maxFailedTimes = 3
-	var interval = 3 * time.Second
-
-	go func(ctx context.Context) {
-		failedTimes := 0
-		for {
-			select {
-			case <-ctx.Done():
-				lg.Info("parent done, exiting monitor")
-				select {
-				case clientClosed <- struct{}{}:
-				default:
-				}
-				return
-			default:
-				// check connection
-				blockNumber, err = s.bindings.WsClient.BlockNumber(ctx)
-				if err != nil {
-					lg.Error(
-						"failed to get blockNumber from webSocketClient, may be connection lost",
-						"error", err,
-						"inverval", interval,
-						"maxFailedTimes", maxFailedTimes,
-						"failedTimes", failedTimes,
-					)
-					failedTimes++
-				} else {
-					failedTimes = 0 // reset failedTimes
-				}
-
-				if failedTimes > maxFailedTimes {
-					lg.Error("wsClient.BlockNumber failed 5 times, exiting monitor")
-					emulatorServerState.Disable("wsClient.BlockNumber failed 5 times")
-					select {
-					case clientClosed <- struct{}{}:
-					default:
-					}
-					return
-				}
-
-				time.Sleep(interval)
-				if time.Now().Second()%10 == 0 {
-					lg.Debug("monitoring connection emulatorServerState",
-						"interval", interval,
-						"blockNumber", blockNumber,
-						"fialedTimes", failedTimes,
-					)
-				}
-			}
-
-		}
-	}(parrent)
-
-	return nil
-}
-
-func (s *Server) startEmulator(ctx context.Context) error {
-	events := events2.GetAllEvents(
-		s.bindings.ChainID,
-		s.bindings.RPCClient,
-		s.bindings.RPCBindings,
-		s.bindings.WsClient,
-		s.bindings.WsBindings,
-		s.bindings.TxMgr,
-		s.logger,
-	)
 	s.logger.Info("events loaded", "count", len(events))
+	s.routesMu.Lock()
+	s.routes = events
+	s.routesMu.Unlock()
 
 	for _, event := range events {
 		err := event.Init(ctx)
@@ -180,18 +215,14 @@ func (s *Server) startEmulator(ctx context.Context) error {
 		s.logger.Info("event started")
 	}
 
+	statusMutex.Lock()
 	emulatorServerState.Enable()
+	statusMutex.Unlock()
 
 	fmt.Println()
 	fmt.Println()
 	fmt.Println("start listening for events...")
 
-	// Wait for the context to be canceled
-	<-ctx.Done()
-
-	fmt.Println("Main function exiting...")
-	fmt.Println()
-
 	return nil
 }
 
@@ -205,6 +236,12 @@ func (s *Server) startHTTPServer(ctx context.Context, port int) error {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(emulatorServerState)
 	})
+	mux.Handle("/metrics", promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/health/routes", s.handleRouteHealth)
+	mux.HandleFunc("/livez", s.handleLivez)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.HandleFunc("/health/consistency", s.handleConsistency)
+	mux.HandleFunc("/admin/reload", s.handleReload)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
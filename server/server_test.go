@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	"github.com/0xPellNetwork/pell-emulator/internal/journal"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/metrics/collectors/relay"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var (
+	testChainID = big.NewInt(1337)
+	testSender  = gethcommon.HexToAddress("0x00000000000000000000000000000000000f0001")
+)
+
+// testConfig returns the default config with the discovered contracts pinned, so a fake
+// client that does not answer the views is accepted, and no connector update on start
+func testConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.AutoUpdateConnector = false
+	cfg.ContractAddress.PellStakeRegistryRouter = "0x00000000000000000000000000000000000C0001"
+	cfg.ContractAddress.DVSOperatorStakeManager = "0x00000000000000000000000000000000000C0002"
+	return cfg
+}
+
+// newTestServer returns a server on the bindings of cfg, sending with a recording tx manager
+// from testSender. The endpoints of cfg are dialed unless opts set the clients.
+func newTestServer(t *testing.T, cfg *config.Config, opts ...chains.Option) *Server {
+	opts = append([]chains.Option{chains.WithTxManager(ethtest.NewTxManager(testSender))}, opts...)
+	bindings, err := chains.NewChainBindings(context.Background(), cfg, log.NewNopLogger(), opts...)
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	s := &Server{
+		bindings:     bindings,
+		journal:      journal.NewNopJournal(),
+		logger:       log.NewNopLogger(),
+		registry:     registry,
+		relayMetrics: relay.NewCollector(registry),
+		reloadCh:     make(chan *bindingsSwap),
+	}
+	s.cfg.Store(cfg)
+	return s
+}
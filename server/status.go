@@ -3,8 +3,16 @@ package server
 import "sync"
 
 type ServerStatus struct {
-	Ready   bool   `json:"ready"`
-	Message string `json:"message"`
+	Ready   bool            `json:"ready"`
+	Message string          `json:"message"`
+	Signers []SignerBalance `json:"signers,omitempty"`
+}
+
+// SignerBalance is the last known balance of a signing address, in wei
+type SignerBalance struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+	Low     bool   `json:"low"`
 }
 
 func (ss *ServerStatus) Disable(msg string) {
@@ -17,6 +25,12 @@ func (ss *ServerStatus) Enable() {
 	ss.Message = "ok"
 }
 
+func (ss *ServerStatus) SetSigners(signers []SignerBalance) {
+	statusMutex.Lock()
+	defer statusMutex.Unlock()
+	ss.Signers = signers
+}
+
 var (
 	emulatorServerState = ServerStatus{
 		Ready:   false,
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	events2 "github.com/0xPellNetwork/pell-emulator/internal/events"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+)
+
+var (
+	connectionCheckInterval = 3 * time.Second
+	maxConnectionFailures   = 3
+	reconnectInterval       = 5 * time.Second
+	drainTimeout            = 30 * time.Second
+)
+
+// bindingsSwap asks the supervisor to restart the routes on next, prev is set by the supervisor
+// so it can go back to it if the routes do not start
+type bindingsSwap struct {
+	next *chains.ChainBindings
+	prev *chains.ChainBindings
+	done chan error
+}
+
+// superviseEmulator runs the routes and health checks the rpc and ws clients. When a client is
+// lost the routes are stopped, the bindings are rebuilt on the first reachable endpoint and the
+// routes are started again. Readiness is off while reconnecting. Reloaded bindings are swapped
+// in the same way and swapped back if the routes do not start on them.
+func (s *Server) superviseEmulator(ctx context.Context) error {
+	lg := s.logger.With("func", "ConnectionSupervisor")
+
+	var pending *bindingsSwap
+	var resume map[string]events2.LogPosition
+	for started := false; ; started = true {
+		routesCtx, cancelRoutes := context.WithCancel(ctx)
+		err := s.startEmulator(routesCtx, resume)
+		if pending != nil {
+			if err != nil {
+				cancelRoutes()
+				resume = s.stopRoutes(lg)
+				s.swapBindings(pending.prev).Close()
+				pending.done <- errors.Wrap(err, "failed to start routes with the reloaded config, kept the previous one")
+				pending = nil
+				continue
+			}
+			pending.prev.Close()
+			pending.done <- nil
+			pending = nil
+		}
+		if err != nil && !started {
+			cancelRoutes()
+			return err
+		}
+
+		var swap *bindingsSwap
+		if err == nil {
+			swap, err = s.watchConnection(routesCtx, lg)
+		}
+		cancelRoutes()
+		resume = s.stopRoutes(lg)
+
+		if ctx.Err() != nil {
+			if swap != nil {
+				swap.done <- ctx.Err()
+			}
+			fmt.Println("Main function exiting...")
+			fmt.Println()
+			return nil
+		}
+
+		if swap != nil {
+			lg.Info("restarting routes with reloaded bindings")
+			swap.prev = s.swapBindings(swap.next)
+			pending = swap
+			continue
+		}
+
+		lg.Error("connection lost, stopping emulator and reconnecting", "error", err)
+		statusMutex.Lock()
+		emulatorServerState.Disable(fmt.Sprintf("reconnecting: %s", err))
+		statusMutex.Unlock()
+
+		if err := s.reconnect(ctx, lg); err != nil {
+			return nil
+		}
+	}
+}
+
+// stopRoutes waits for the events in flight of the stopped routes and returns where each
+// route stopped. The routes started next replay the queued events and the ones emitted
+// while they were stopped from there.
+func (s *Server) stopRoutes(lg log.Logger) map[string]events2.LogPosition {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	s.routesMu.RLock()
+	defer s.routesMu.RUnlock()
+	positions := make(map[string]events2.LogPosition, len(s.routes))
+	for _, route := range s.routes {
+		name := events2.RouteName(route)
+		if err := route.Drain(ctx); err != nil {
+			lg.Error("route stopped before its event in flight finished", "route", name, "error", err)
+		}
+		if pos, ok := route.ResumeFrom(); ok {
+			positions[name] = pos
+			if depth := route.Status(0).QueueDepth; depth > 0 {
+				lg.Info("queued events of stopped route are replayed on restart", "route", name, "count", depth,
+					"fromBlock", pos.Block)
+			}
+		}
+	}
+	return positions
+}
+
+// swapBindings sets next as the current bindings and returns the previous ones
+func (s *Server) swapBindings(next *chains.ChainBindings) *chains.ChainBindings {
+	s.bindingsMu.Lock()
+	defer s.bindingsMu.Unlock()
+	prev := s.bindings
+	s.bindings = next
+	return prev
+}
+
+// watchConnection blocks until a client failed more than maxConnectionFailures times in a row
+// or reloaded bindings are submitted
+func (s *Server) watchConnection(ctx context.Context, lg log.Logger) (*bindingsSwap, error) {
+	bindings := s.chainBindings()
+	lg.Info("monitoring connection started", "rpcURL", bindings.Endpoint.RPCURL, "wsURL", bindings.Endpoint.WSURL)
+
+	failedTimes := 0
+	ticker := time.NewTicker(connectionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case swap := <-s.reloadCh:
+			return swap, nil
+		case <-ticker.C:
+		}
+
+		err := s.checkConnection(ctx)
+		if err == nil {
+			failedTimes = 0
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+
+		failedTimes++
+		lg.Error("connection check failed, may be connection lost",
+			"error", err,
+			"interval", connectionCheckInterval,
+			"maxFailedTimes", maxConnectionFailures,
+			"failedTimes", failedTimes,
+		)
+		if failedTimes > maxConnectionFailures {
+			return nil, err
+		}
+	}
+}
+
+// checkConnection probes both clients, the head block is taken from the ws client
+func (s *Server) checkConnection(ctx context.Context) error {
+	bindings := s.chainBindings()
+
+	blockNumber, err := bindings.WsClient.BlockNumber(ctx)
+	if err != nil {
+		return errors.Wrap(err, "ws client")
+	}
+	s.headBlock.Store(blockNumber)
+
+	if _, err := bindings.RPCClient.BlockNumber(ctx); err != nil {
+		return errors.Wrap(err, "rpc client")
+	}
+	return nil
+}
+
+// reconnect retries until new bindings are built or ctx is done
+func (s *Server) reconnect(ctx context.Context, lg log.Logger) error {
+	for attempt := 1; ; attempt++ {
+		next, err := s.chainBindings().Reconnect(ctx)
+		if err == nil {
+			s.swapBindings(next).Close()
+
+			lg.Info("reconnected", "rpcURL", next.Endpoint.RPCURL, "wsURL", next.Endpoint.WSURL, "attempt", attempt)
+			return nil
+		}
+		lg.Error("reconnect failed", "error", err, "attempt", attempt, "retryIn", reconnectInterval)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(reconnectInterval):
+		}
+	}
+}
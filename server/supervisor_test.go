@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/internal/chains"
+	events2 "github.com/0xPellNetwork/pell-emulator/internal/events"
+	"github.com/0xPellNetwork/pell-emulator/libs/chains/eth/ethtest"
+)
+
+// fastSupervisor shortens the connection check and reconnect intervals for the test
+func fastSupervisor(t *testing.T) {
+	checkInterval, retryInterval := connectionCheckInterval, reconnectInterval
+	connectionCheckInterval, reconnectInterval = 10*time.Millisecond, 200*time.Millisecond
+	t.Cleanup(func() {
+		connectionCheckInterval, reconnectInterval = checkInterval, retryInterval
+	})
+}
+
+// startSupervisor runs the supervisor of s until the test ends
+func startSupervisor(t *testing.T, s *Server) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.superviseEmulator(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
+
+func (s *Server) currentRoutes() []events2.IEvents {
+	s.routesMu.RLock()
+	defer s.routesMu.RUnlock()
+	return s.routes
+}
+
+func ready() bool {
+	statusMutex.RLock()
+	defer statusMutex.RUnlock()
+	return emulatorServerState.Ready
+}
+
+func TestSupervisorReconnects(t *testing.T) {
+	fastSupervisor(t)
+	client := ethtest.NewClient(testChainID)
+	cfg := testConfig()
+	cfg.Routes = []string{"StakerDelegated"}
+	s := newTestServer(t, cfg, chains.WithClients(client, client))
+	first := s.chainBindings()
+
+	startSupervisor(t, s)
+	require.Eventually(t, func() bool {
+		return ready() && len(s.currentRoutes()) == 1 && client.Subscriptions() == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	firstRoute := s.currentRoutes()[0]
+
+	// the ws client fails more than maxConnectionFailures checks in a row, then the first
+	// reconnect fails as well
+	lost := errors.New("websocket: close 1006")
+	failures := make([]error, maxConnectionFailures+1)
+	for i := range failures {
+		failures[i] = lost
+	}
+	client.FailNext("BlockNumber", failures...)
+	client.FailNext("ChainID", errors.New("dial tcp: connection refused"))
+
+	// not ready while reconnecting
+	require.Eventually(t, func() bool {
+		return !ready()
+	}, 10*time.Second, time.Millisecond)
+
+	// the routes are started again on the new bindings, the stopped route unsubscribed
+	require.Eventually(t, func() bool {
+		routes := s.currentRoutes()
+		return ready() && len(routes) == 1 && routes[0] != firstRoute && client.Subscriptions() == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.NotSame(t, first, s.chainBindings())
+	assert.Same(t, first.TxMgr, s.chainBindings().TxMgr)
+	assert.Same(t, first.WsClient, s.chainBindings().WsClient)
+}
+
+func TestReconnectRotatesToFallback(t *testing.T) {
+	primary := ethtest.NewDevNodeServer(ethtest.NewClient(testChainID))
+	fallback := ethtest.NewDevNodeServer(ethtest.NewClient(testChainID))
+	defer fallback.Close()
+	otherChain := ethtest.NewDevNodeServer(ethtest.NewClient(big.NewInt(1)))
+	defer otherChain.Close()
+
+	cfg := testConfig()
+	cfg.RPCURL, cfg.WSURL = primary.URL, ethtest.WSURL(primary)
+	cfg.FallbackEndpoints = []config.Endpoint{
+		{RPCURL: otherChain.URL, WSURL: ethtest.WSURL(otherChain)},
+		{RPCURL: fallback.URL, WSURL: ethtest.WSURL(fallback)},
+	}
+	s := newTestServer(t, cfg)
+	first := s.chainBindings()
+	require.Equal(t, cfg.Endpoints()[0], first.Endpoint)
+
+	primary.CloseClientConnections()
+	primary.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, s.reconnect(ctx, s.logger))
+
+	// the fallback of another chain is skipped
+	next := s.chainBindings()
+	defer next.Close()
+	assert.NotSame(t, first, next)
+	assert.Equal(t, cfg.FallbackEndpoints[1], next.Endpoint)
+	_, err := next.RPCClient.BlockNumber(ctx)
+	assert.NoError(t, err)
+}
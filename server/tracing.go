@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0xPellNetwork/pell-emulator/config"
+	"github.com/0xPellNetwork/pell-emulator/libs/log"
+	"github.com/0xPellNetwork/pell-emulator/libs/tracing"
+)
+
+// newTracer returns nil when tracing is not configured, the forwards are not traced then
+func newTracer(tracingCfg *config.TracingConfig, logger log.Logger) (*tracing.Provider, error) {
+	if !tracingCfg.Enabled() {
+		return nil, nil
+	}
+
+	provider, err := tracing.NewProvider(context.Background(), serviceName, tracingCfg.OTLPEndpoint, tracingCfg.File)
+	if err != nil {
+		return nil, err
+	}
+	if tracingCfg.OTLPEndpoint != "" {
+		logger.Info("tracing to otlp collector", "endpoint", tracingCfg.OTLPEndpoint)
+	}
+	if tracingCfg.File != "" {
+		logger.Info("tracing to file", "file", tracingCfg.File)
+	}
+	return provider, nil
+}
+
+// tracerProvider returns the provider the forwards are traced with, nil when tracing is off
+func (s *Server) tracerProvider() trace.TracerProvider {
+	if s.tracer == nil {
+		return nil
+	}
+	return s.tracer
+}
+
+func (s *Server) shutdownTracer() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.tracer.Shutdown(ctx); err != nil {
+		s.logger.Error("failed to flush traces", "error", err)
+	}
+}